@@ -20,6 +20,9 @@ type Region struct {
 type RegionSpec struct {
 	Description string `json:"description,omitempty"`
 	RegionName  string `json:"regionName,omitempty"`
+	// RegistryMirror is the registry whose credentials are added to the pull secrets of the apps in the region. Image
+	// references are not rewritten, the nodes of the region must be configured to pull through the mirror.
+	RegistryMirror string `json:"registryMirror,omitempty"`
 }
 
 type RegionStatus struct {
@@ -436,6 +436,71 @@ func (in *Config) DeepCopyInto(out *Config) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeSizeDefault != nil {
+		in, out := &in.VolumeSizeDefault, &out.VolumeSizeDefault
+		*out = new(string)
+		**out = **in
+	}
+	if in.PullThroughCache != nil {
+		in, out := &in.PullThroughCache, &out.PullThroughCache
+		*out = new(string)
+		**out = **in
+	}
+	if in.BackingSecretNamespace != nil {
+		in, out := &in.BackingSecretNamespace, &out.BackingSecretNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecretWebhookURL != nil {
+		in, out := &in.SecretWebhookURL, &out.SecretWebhookURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.DisallowedSecretTypes != nil {
+		in, out := &in.DisallowedSecretTypes, &out.DisallowedSecretTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GenerationJobBackoffLimit != nil {
+		in, out := &in.GenerationJobBackoffLimit, &out.GenerationJobBackoffLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.GenerationJobTTLSeconds != nil {
+		in, out := &in.GenerationJobTTLSeconds, &out.GenerationJobTTLSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.IngressClassControllerNamespaces != nil {
+		in, out := &in.IngressClassControllerNamespaces, &out.IngressClassControllerNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowTrafficFromNamespaceLabel != nil {
+		in, out := &in.AllowTrafficFromNamespaceLabel, &out.AllowTrafficFromNamespaceLabel
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecretCompatLabels != nil {
+		in, out := &in.SecretCompatLabels, &out.SecretCompatLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppResourceQuota != nil {
+		in, out := &in.AppResourceQuota, &out.AppResourceQuota
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Config.
@@ -1190,6 +1255,13 @@ func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DefaultSecrets != nil {
+		in, out := &in.DefaultSecrets, &out.DefaultSecrets
+		*out = make(map[string]internal_acorn_iov1.Secret, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
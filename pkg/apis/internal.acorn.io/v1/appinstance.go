@@ -10,20 +10,21 @@ import (
 type AppInstanceCondition string
 
 var (
-	AppInstanceConditionDefined      = "defined"
-	AppInstanceConditionDefaults     = "defaults"
-	AppInstanceConditionScheduling   = "scheduling"
-	AppInstanceConditionNamespace    = "namespace"
-	AppInstanceConditionParsed       = "parsed"
-	AppInstanceConditionController   = "controller"
-	AppInstanceConditionPulled       = "image-pull"
-	AppInstanceConditionSecrets      = "secrets"
-	AppInstanceConditionContainers   = "containers"
-	AppInstanceConditionJobs         = "jobs"
-	AppInstanceConditionAcorns       = "acorns"
-	AppInstanceConditionReady        = "Ready"
-	AppInstanceConditionVolumes      = "volumes"
-	AppInstanceConditionImageAllowed = "image-allowed"
+	AppInstanceConditionDefined              = "defined"
+	AppInstanceConditionDefaults             = "defaults"
+	AppInstanceConditionScheduling           = "scheduling"
+	AppInstanceConditionNamespace            = "namespace"
+	AppInstanceConditionParsed               = "parsed"
+	AppInstanceConditionController           = "controller"
+	AppInstanceConditionPulled               = "image-pull"
+	AppInstanceConditionSecrets              = "secrets"
+	AppInstanceConditionContainers           = "containers"
+	AppInstanceConditionJobs                 = "jobs"
+	AppInstanceConditionAcorns               = "acorns"
+	AppInstanceConditionReady                = "Ready"
+	AppInstanceConditionVolumes              = "volumes"
+	AppInstanceConditionImageAllowed         = "image-allowed"
+	AppInstanceConditionNamespaceTerminating = "namespace-terminating"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -84,6 +85,7 @@ type AppInstanceSpec struct {
 	Annotations         []ScopedLabel    `json:"annotations,omitempty"`
 	Image               string           `json:"image,omitempty"`
 	Stop                *bool            `json:"stop,omitempty"`
+	Suspend             *bool            `json:"suspend,omitempty"`
 	DevMode             *bool            `json:"devMode,omitempty"`
 	Profiles            []string         `json:"profiles,omitempty"`
 	Volumes             []VolumeBinding  `json:"volumes,omitempty"`
@@ -100,10 +102,16 @@ type AppInstanceSpec struct {
 	AutoUpgradeInterval string           `json:"autoUpgradeInterval,omitempty"`
 	ComputeClasses      ComputeClassMap  `json:"computeClass,omitempty"`
 	Memory              MemoryMap        `json:"memory,omitempty"`
+	PrePull             *bool            `json:"prePull,omitempty"`
 }
 
+// GetStopped returns true if the app is stopped, either explicitly or because it is suspended
 func (in *AppInstanceSpec) GetStopped() bool {
-	return in.Stop != nil && *in.Stop
+	return (in.Stop != nil && *in.Stop) || in.GetSuspended()
+}
+
+func (in *AppInstanceSpec) GetSuspended() bool {
+	return in.Suspend != nil && *in.Suspend
 }
 
 func (in *AppInstanceSpec) GetAutoUpgrade() bool {
@@ -114,6 +122,10 @@ func (in *AppInstanceSpec) GetNotifyUpgrade() bool {
 	return in.NotifyUpgrade != nil && *in.NotifyUpgrade
 }
 
+func (in *AppInstanceSpec) GetPrePull() bool {
+	return in.PrePull != nil && *in.PrePull
+}
+
 func (in *AppInstanceSpec) GetDevMode() bool {
 	return in.DevMode != nil && *in.DevMode
 }
@@ -158,6 +170,9 @@ type VolumeBinding struct {
 	Size        Quantity    `json:"size,omitempty"`
 	AccessModes AccessModes `json:"accessModes,omitempty"`
 	Class       string      `json:"class,omitempty"`
+	// Migrate allows binding a volume of one volume class to a different class, or changing the access modes of a
+	// volume, by copying its data into a new volume
+	Migrate bool `json:"migrate,omitempty"`
 }
 
 type ContainerStatus struct {
@@ -175,6 +190,13 @@ type JobStatus struct {
 	Message string `json:"message,omitempty"`
 }
 
+// NetworkPolicyStatus summarizes a NetworkPolicy that acorn generated for the app
+type NetworkPolicyStatus struct {
+	Name        string `json:"name,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
 type AppColumns struct {
 	Healthy   string `json:"healthy,omitempty" column:"name=Healthy,jsonpath=.status.columns.healthy"`
 	UpToDate  string `json:"upToDate,omitempty" column:"name=Up-To-Date,jsonpath=.status.columns.upToDate"`
@@ -192,6 +214,7 @@ type AppInstanceStatus struct {
 	AcornStatus            map[string]AcornStatus     `json:"acornStatus,omitempty"`
 	Ready                  bool                       `json:"ready,omitempty"`
 	Stopped                bool                       `json:"stopped,omitempty"`
+	Suspended              bool                       `json:"suspended,omitempty"`
 	Namespace              string                     `json:"namespace,omitempty"`
 	AppImage               AppImage                   `json:"appImage,omitempty"`
 	AvailableAppImage      string                     `json:"availableAppImage,omitempty"`
@@ -200,6 +223,7 @@ type AppInstanceStatus struct {
 	Scheduling             map[string]Scheduling      `json:"scheduling,omitempty"`
 	Conditions             []Condition                `json:"conditions,omitempty"`
 	Endpoints              []Endpoint                 `json:"endpoints,omitempty"`
+	NetworkPolicies        []NetworkPolicyStatus      `json:"networkPolicies,omitempty"`
 	Defaults               Defaults                   `json:"defaults,omitempty"`
 }
 
@@ -5,15 +5,18 @@ import (
 	"sort"
 	"strings"
 
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 )
 
 const (
 	VolumeRequestTypeEphemeral = "ephemeral"
 
-	AccessModeReadWriteMany AccessMode = "readWriteMany"
-	AccessModeReadWriteOnce AccessMode = "readWriteOnce"
-	AccessModeReadOnlyMany  AccessMode = "readOnlyMany"
+	AccessModeReadWriteMany    AccessMode = "readWriteMany"
+	AccessModeReadWriteOnce    AccessMode = "readWriteOnce"
+	AccessModeReadOnlyMany     AccessMode = "readOnlyMany"
+	AccessModeReadWriteOncePod AccessMode = "readWriteOncePod"
 )
 
 type AccessMode string
@@ -130,6 +133,17 @@ type PortDef struct {
 	Publish    bool     `json:"publish,omitempty"`
 	Port       int32    `json:"port,omitempty"`
 	TargetPort int32    `json:"targetPort,omitempty"`
+	// Path routes only the requests for this path to the port, when set the http ports of a hostname share it
+	Path     string   `json:"path,omitempty"`
+	PathType PathType `json:"pathType,omitempty"`
+	// Public allows traffic to a published tcp or udp port from any address, including the pods of the cluster
+	Public bool `json:"public,omitempty"`
+	// IngressClassName is the ingress class of the Ingress of a published http port, overriding the configured ingress class
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// SessionAffinity sends all connections from a client address to the same pod of a published tcp or udp port
+	SessionAffinity bool `json:"sessionAffinity,omitempty"`
+	// SessionAffinityTimeout is the number of seconds a client address sticks to the same pod, defaults to 3 hours
+	SessionAffinityTimeout int32 `json:"sessionAffinityTimeout,omitempty"`
 }
 
 func (in PortDef) Complete() PortDef {
@@ -154,6 +168,7 @@ type File struct {
 type VolumeSecretMount struct {
 	Name     string     `json:"name,omitempty"`
 	OnChange ChangeType `json:"onChange,omitempty"`
+	Mode     string     `json:"mode,omitempty"`
 }
 
 type VolumeMount struct {
@@ -217,6 +232,12 @@ type Probe struct {
 	FailureThreshold    int32      `json:"failureThreshold,omitempty"`
 }
 
+// Hook is an action run in a container, such as before it is stopped. Only one of Exec or HTTP should be set.
+type Hook struct {
+	Exec *ExecProbe `json:"exec,omitempty"`
+	HTTP *HTTPProbe `json:"http,omitempty"`
+}
+
 type Dependency struct {
 	TargetName string `json:"targetName,omitempty"`
 }
@@ -364,6 +385,17 @@ type Container struct {
 	Permissions  *Permissions           `json:"permissions,omitempty"`
 	ComputeClass *string                `json:"class,omitempty"`
 	Memory       *int64                 `json:"memory,omitempty"`
+	PreStop      *Hook                  `json:"preStop,omitempty"`
+
+	// ReadOnlyRootFilesystem runs the container with a read-only root filesystem, only its WritablePaths and volumes
+	// can be written to
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// WritablePaths are backed by scratch volumes when the root filesystem is read-only, defaults to /tmp
+	WritablePaths []string `json:"writablePaths,omitempty"`
+
+	// TerminationGracePeriodSeconds is not available on sidecars
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 
 	// Scale is only available on containers, not sidecars or jobs
 	Scale *int32 `json:"scale,omitempty"`
@@ -395,6 +427,51 @@ type AppSpec struct {
 	Acorns      map[string]Acorn         `json:"acorns,omitempty"`
 	Routers     map[string]Router        `json:"routers,omitempty"`
 	Services    map[string]Service       `json:"services,omitempty"`
+	DNSPolicy   string                   `json:"dnsPolicy,omitempty"`
+	DNSConfig   *DNSConfig               `json:"dnsConfig,omitempty"`
+
+	// Environment is the default environment of every container, sidecar and job
+	Environment EnvVars `json:"environment,omitempty"`
+
+	// NodeSelector and Tolerations are added to the scheduling rules of every container and job
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// ContainerEnvironment returns the environment of the container with the app's environment as the defaults. A
+// variable of the container replaces the app variable of the same name.
+func (in AppSpec) ContainerEnvironment(container Container) EnvVars {
+	if len(in.Environment) == 0 {
+		return container.Environment
+	}
+
+	var (
+		result = make(EnvVars, 0, len(in.Environment)+len(container.Environment))
+		names  = map[string]bool{}
+	)
+	for _, env := range container.Environment {
+		names[env.Name] = true
+	}
+	for _, env := range in.Environment {
+		// variables without a name import all the keys of a secret, they are only replaced by an identical import
+		if env.Name == "" && !slices.Contains(container.Environment, env) || env.Name != "" && !names[env.Name] {
+			result = append(result, env)
+		}
+	}
+	return append(result, container.Environment...)
+}
+
+// DNSConfig is applied to the DNS configuration of every pod of the app, in addition to the configuration generated
+// from the DNSPolicy
+type DNSConfig struct {
+	Nameservers []string    `json:"nameservers,omitempty"`
+	Searches    []string    `json:"searches,omitempty"`
+	Options     []DNSOption `json:"options,omitempty"`
+}
+
+type DNSOption struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
 }
 
 type Route struct {
@@ -438,6 +515,30 @@ type Secret struct {
 	Type        string            `json:"type,omitempty"`
 	Params      GenericMap        `json:"params,omitempty"`
 	Data        map[string]string `json:"data,omitempty"`
+	DependsOn   []string          `json:"dependsOn,omitempty"`
+	// Publish set to false generates the secret for other secrets to use without creating it in the app namespace
+	Publish *bool `json:"publish,omitempty"`
+	// Profiles limits the secret to apps running with one of the profiles, the secret is not generated for other apps
+	Profiles []string `json:"profiles,omitempty"`
+}
+
+// IsPublished returns true unless the secret is explicitly not published
+func (in Secret) IsPublished() bool {
+	return in.Publish == nil || *in.Publish
+}
+
+// AppliesToProfiles returns true if the secret is not limited to any profiles or one of its profiles is in the given
+// profiles. The "?" suffix that marks an optional profile is ignored.
+func (in Secret) AppliesToProfiles(profiles []string) bool {
+	if len(in.Profiles) == 0 {
+		return true
+	}
+	for _, profile := range profiles {
+		if slices.Contains(in.Profiles, strings.TrimSuffix(profile, "?")) {
+			return true
+		}
+	}
+	return false
 }
 
 type AccessModes []AccessMode
@@ -39,6 +39,20 @@ func TestParseVolumesWithoutBinding(t *testing.T) {
 	}, vs[1])
 }
 
+func TestParseVolumesMigrate(t *testing.T) {
+	vs, err := ParseVolumes([]string{"pv-data:data,class=fast,migrate=true"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, VolumeBinding{
+		Volume:  "pv-data",
+		Target:  "data",
+		Class:   "fast",
+		Migrate: true,
+	}, vs[0])
+
+	_, err = ParseVolumes([]string{"pv-data:data,migrate=maybe"}, true)
+	assert.Error(t, err)
+}
+
 func TestParseVolumes(t *testing.T) {
 	input := []string{
 		"bar:bar,size=11G,class=aclass",
@@ -9,6 +9,9 @@ const (
 	SecretTypeTemplate  corev1.SecretType = "secrets.acorn.io/template"
 	SecretTypeBasic     corev1.SecretType = "secrets.acorn.io/basic"
 	SecretTypeToken     corev1.SecretType = "secrets.acorn.io/token"
+	SecretTypeTLS       corev1.SecretType = "secrets.acorn.io/tls"
+	SecretTypeJWT       corev1.SecretType = "secrets.acorn.io/jwt"
+	SecretTypeDocker    corev1.SecretType = "secrets.acorn.io/docker"
 )
 
 var (
@@ -18,5 +21,8 @@ var (
 		SecretTypeTemplate:  true,
 		SecretTypeBasic:     true,
 		SecretTypeToken:     true,
+		SecretTypeTLS:       true,
+		SecretTypeJWT:       true,
+		SecretTypeDocker:    true,
 	}
 )
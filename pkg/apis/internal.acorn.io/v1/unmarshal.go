@@ -417,6 +417,8 @@ func addImpliedResources(in *AppSpec) error {
 		}
 	}
 
+	impliedSecretsForContainer(in, Container{Environment: in.Environment})
+
 	for containerName, c := range in.Containers {
 		impliedSecretsForContainer(in, c)
 		if err := impliedVolumesForContainer(in, containerName, "", c); err != nil {
@@ -452,6 +454,14 @@ func (in *AppSpec) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	var alias appSpecAliases
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	if len(alias.Env) > 0 {
+		in.Environment = append(in.Environment, alias.Env...)
+	}
+
 	if err := addImpliedResources(in); err != nil {
 		return err
 	}
@@ -545,6 +555,10 @@ func (in *AccessModes) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+type appSpecAliases struct {
+	Env EnvVars `json:"env,omitempty"`
+}
+
 type acornAliases struct {
 	Env NameValues `json:"env,omitempty"`
 	Mem MemoryMap  `json:"mem,omitempty"`
@@ -859,6 +873,7 @@ func (in *VolumeMount) UnmarshalJSON(data []byte) error {
 	if ok {
 		in.Secret.Name = sec.SecretReference.Name
 		in.Secret.OnChange = sec.SecretReference.OnChange
+		in.Secret.Mode = sec.Mode
 	} else if strings.HasPrefix(s, "./") {
 		in.ContextDir = s
 	} else {
@@ -1331,10 +1346,29 @@ func MustParseResourceQuantity(s Quantity) *resource.Quantity {
 	return &q
 }
 
+// Percentage returns the percentage of a quantity like "25%", which is only valid as the size of an ephemeral volume
+func (in Quantity) Percentage() (float64, bool) {
+	s, ok := strings.CutSuffix(string(in), "%")
+	if !ok {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(s, 64)
+	if err != nil || p <= 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
+}
+
 func ParseQuantity(s string) (Quantity, error) {
 	if s == "" {
 		return "", nil
 	}
+	if strings.HasSuffix(s, "%") {
+		if _, ok := Quantity(s).Percentage(); !ok {
+			return "", fmt.Errorf("invalid percentage [%s], must be greater than 0%% and at most 100%%", s)
+		}
+		return Quantity(s), nil
+	}
 	d, err := strconv.Atoi(s)
 	if err == nil {
 		if d < 1000000 {
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -43,3 +44,35 @@ func TestParseEnv(t *testing.T) {
 		Value: "y111",
 	}, f[1])
 }
+
+func TestAppSpecEnv(t *testing.T) {
+	var app AppSpec
+	err := json.Unmarshal([]byte(`{
+		"env": {"TZ": "UTC", "LOG_LEVEL": "info"},
+		"containers": {"web": {"env": {"LOG_LEVEL": "debug"}}}
+	}`), &app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, EnvVars{
+		{Name: "TZ", Value: "UTC"},
+		{Name: "LOG_LEVEL", Value: "debug"},
+	}, app.ContainerEnvironment(app.Containers["web"]))
+}
+
+func TestParseQuantityPercentage(t *testing.T) {
+	q, err := ParseQuantity("25%")
+	assert.NoError(t, err)
+	p, ok := q.Percentage()
+	assert.True(t, ok)
+	assert.Equal(t, 25.0, p)
+
+	for _, invalid := range []string{"0%", "101%", "ten%"} {
+		_, err := ParseQuantity(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	_, ok = Quantity("10G").Percentage()
+	assert.False(t, ok)
+}
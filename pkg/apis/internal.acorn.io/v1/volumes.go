@@ -2,6 +2,7 @@ package v1
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -35,6 +36,12 @@ func ParseVolumes(args []string, fromCLI bool) (result []VolumeBinding, _ error)
 				return nil, fmt.Errorf("parsing [%s]: %w", arg, err)
 			}
 			volumeBinding.Size = q
+			if migrate := strings.TrimSpace(kvOpts["migrate"]); migrate != "" {
+				volumeBinding.Migrate, err = strconv.ParseBool(migrate)
+				if err != nil {
+					return nil, fmt.Errorf("parsing migrate option of [%s]: %w", arg, err)
+				}
+			}
 		} else if len(kvOpts) > 0 {
 			return nil, fmt.Errorf("options [%s] are not supported in acorn volume binding definition", opts)
 		}
@@ -11,6 +11,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/tonistiigi/fsutil/types"
 	fstypes "github.com/tonistiigi/fsutil/types"
+	"golang.org/x/exp/slices"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -44,10 +45,15 @@ func newFileSyncClient(ctx context.Context, cwd, sessionID string, messages Mess
 		return nil, err
 	}
 
+	excludes, err := excludePatterns(dirs, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	md := metadata.MD{
 		keyOverrideExcludes:   opts.OverrideExcludes,
 		keyIncludePatterns:    opts.IncludePatterns,
-		keyExcludePatterns:    opts.ExcludePatterns,
+		keyExcludePatterns:    excludes,
 		keyFollowPaths:        opts.FollowPaths,
 		keyDirName:            opts.DirName,
 		keyExporterMetaPrefix: opts.ExporterMetaPrefix,
@@ -103,6 +109,19 @@ func createFileMapInput(cwd string, opts *SyncOptions) (string, map[string]strin
 	}, nil
 }
 
+// excludePatterns adds the ignore patterns of the local build context to the patterns requested by the builder, so
+// ignored files are never sent, even for builds that don't read a .dockerignore themselves
+func excludePatterns(localDirs map[string]string, opts *SyncOptions) ([]string, error) {
+	if !slices.Contains(opts.DirName, "context") {
+		return opts.ExcludePatterns, nil
+	}
+	patterns, err := ReadIgnorePatterns(localDirs["context"])
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]string{}, opts.ExcludePatterns...), patterns...), nil
+}
+
 func prepareSyncedDirs(localDirs map[string]string, dirNames []string, followPaths []string) ([]filesync.SyncedDir, error) {
 	for localDirName, d := range localDirs {
 		fi, err := os.Stat(d)
@@ -0,0 +1,105 @@
+package buildclient
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
+)
+
+// IgnoreFiles are the files in a build context that list patterns of files to leave out of the build. The patterns
+// of all the files that exist are combined, so .acornignore can add acorn specific patterns to a .dockerignore.
+var IgnoreFiles = []string{".dockerignore", ".acornignore"}
+
+// ReadIgnorePatterns reads the ignore patterns of the build context dir
+func ReadIgnorePatterns(dir string) (result []string, _ error) {
+	for _, name := range IgnoreFiles {
+		f, err := os.Open(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		patterns, err := dockerignore.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", filepath.Join(dir, name), err)
+		}
+		result = append(result, patterns...)
+	}
+	return result, nil
+}
+
+// FilterIgnored removes the files that are matched by the ignore patterns of the build context dir
+func FilterIgnored(dir string, files []string) ([]string, error) {
+	patterns, err := ReadIgnorePatterns(dir)
+	if err != nil || len(patterns) == 0 {
+		return files, err
+	}
+
+	matcher, err := fileutils.NewPatternMatcher(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			// files outside the build context are never ignored
+			result = append(result, file)
+			continue
+		}
+		ignored, err := matcher.MatchesOrParentMatches(rel)
+		if err != nil {
+			return nil, err
+		}
+		if !ignored {
+			result = append(result, file)
+		}
+	}
+	return result, nil
+}
+
+// ContextFiles returns the files of the build context dir that are not ignored, in lexical order
+func ContextFiles(dir string) (result []string, _ error) {
+	patterns, err := ReadIgnorePatterns(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher, err := fileutils.NewPatternMatcher(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		ignored, err := matcher.MatchesOrParentMatches(rel)
+		if err != nil {
+			return err
+		}
+		if ignored {
+			// a directory can only be skipped if no exclusion can include one of its files again
+			if d.IsDir() && !matcher.Exclusions() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			result = append(result, path)
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,54 @@
+package buildclient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludePatterns(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("node_modules\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".acornignore"), []byte("*.log\n!keep.log\n"), 0600))
+
+	dirs := map[string]string{
+		"context":    dir,
+		"dockerfile": dir,
+	}
+
+	excludes, err := excludePatterns(dirs, &SyncOptions{
+		DirName:         []string{"context"},
+		ExcludePatterns: []string{".git"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".git", "node_modules", "*.log", "!keep.log"}, excludes)
+
+	// the dockerfile dir is not the build context and is synced as requested
+	excludes, err = excludePatterns(dirs, &SyncOptions{
+		DirName: []string{"dockerfile"},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, excludes)
+}
+
+func TestFilterIgnored(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".acornignore"), []byte("docs\n*.log\n!keep.log\n"), 0600))
+
+	files, err := FilterIgnored(dir, []string{
+		filepath.Join(dir, "Dockerfile"),
+		filepath.Join(dir, "docs", "Dockerfile"),
+		filepath.Join(dir, "build.log"),
+		filepath.Join(dir, "keep.log"),
+		filepath.Join(filepath.Dir(dir), "Dockerfile"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "Dockerfile"),
+		filepath.Join(dir, "keep.log"),
+		filepath.Join(filepath.Dir(dir), "Dockerfile"),
+	}, files)
+}
@@ -30,7 +30,7 @@ func New() *cobra.Command {
 		},
 		StdOut: os.Stdout,
 		StdErr: os.Stderr,
-		StdIn:  nil,
+		StdIn:  os.Stdin,
 	}
 	root.AddCommand(
 		NewAll(cmdContext),
@@ -51,6 +51,7 @@ func New() *cobra.Command {
 		NewOfferings(cmdContext),
 		NewUninstall(cmdContext),
 		NewInfo(cmdContext),
+		NewLint(cmdContext),
 		NewLogs(cmdContext),
 		NewCredentialLogin(true, cmdContext),
 		NewCredentialLogout(true, cmdContext),
@@ -9,8 +9,9 @@ import (
 )
 
 func NewApp(c CommandContext) *cobra.Command {
-	return cli.Command(&App{client: c.ClientFactory}, cobra.Command{
+	cmd := cli.Command(&App{client: c.ClientFactory}, cobra.Command{
 		Use:     "app [flags] [APP_NAME...]",
+		Args:    cobra.ArbitraryArgs,
 		Aliases: []string{"apps", "a", "ps"},
 		Example: `
 acorn app`,
@@ -18,6 +19,10 @@ acorn app`,
 		Short:             "List or get apps",
 		ValidArgsFunction: newCompletion(c.ClientFactory, appsCompletion).complete,
 	})
+	cmd.AddCommand(NewAppRenderNetPol(c))
+	cmd.AddCommand(NewAppResume(c))
+	cmd.AddCommand(NewAppSuspend(c))
+	return cmd
 }
 
 type App struct {
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	cli "github.com/acorn-io/acorn/pkg/cli/builder"
+	"github.com/acorn-io/acorn/pkg/cli/builder/table"
+	"github.com/acorn-io/acorn/pkg/controller/networkpolicy"
+	"github.com/acorn-io/acorn/pkg/tables"
+	"github.com/spf13/cobra"
+)
+
+func NewAppRenderNetPol(c CommandContext) *cobra.Command {
+	return cli.Command(&AppRenderNetPol{client: c.ClientFactory}, cobra.Command{
+		Use: "render-netpol [flags] APP_NAME",
+		Example: `
+acorn app render-netpol my-app`,
+		SilenceUsage:      true,
+		Short:             "Compare the NetworkPolicies an app should have with those in the cluster",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newCompletion(c.ClientFactory, appsCompletion).withShouldCompleteOptions(onlyNumArgs(1)).complete,
+	})
+}
+
+type AppRenderNetPol struct {
+	Output string `usage:"Output format (json, yaml, {{gotemplate}})" short:"o"`
+	client ClientFactory
+}
+
+func (a *AppRenderNetPol) Run(cmd *cobra.Command, args []string) error {
+	c, err := a.client.CreateDefault()
+	if err != nil {
+		return err
+	}
+
+	app, err := c.AppGet(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	kc, err := c.GetClient()
+	if err != nil {
+		return err
+	}
+
+	expected, err := networkpolicy.Expected(cmd.Context(), kc, &v1.AppInstance{
+		ObjectMeta: app.ObjectMeta,
+		Spec:       app.Spec,
+		Status:     app.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("computing network policies for app %s: %w", app.Name, err)
+	}
+
+	drift, err := networkpolicy.Diff(cmd.Context(), kc, expected)
+	if err != nil {
+		return err
+	}
+
+	out := table.NewWriter(tables.NetworkPolicyDrift, false, a.Output)
+	for i := range drift {
+		out.Write(&drift[i])
+	}
+	return out.Err()
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+
+	cli "github.com/acorn-io/acorn/pkg/cli/builder"
+	"github.com/spf13/cobra"
+)
+
+func NewAppResume(c CommandContext) *cobra.Command {
+	return cli.Command(&AppResume{client: c.ClientFactory}, cobra.Command{
+		Use: "resume [flags] APP_NAME...",
+		Example: `
+acorn app resume my-app
+
+acorn app resume my-app1 my-app2`,
+		SilenceUsage:      true,
+		Short:             "Resume a suspended app",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: newCompletion(c.ClientFactory, appsCompletion).complete,
+	})
+}
+
+type AppResume struct {
+	client ClientFactory
+}
+
+func (a *AppResume) Run(cmd *cobra.Command, args []string) error {
+	c, err := a.client.CreateDefault()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		err := c.AppResume(cmd.Context(), arg)
+		if err != nil {
+			return fmt.Errorf("resuming %s: %w", arg, err)
+		}
+		fmt.Println(arg)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+
+	cli "github.com/acorn-io/acorn/pkg/cli/builder"
+	"github.com/spf13/cobra"
+)
+
+func NewAppSuspend(c CommandContext) *cobra.Command {
+	return cli.Command(&AppSuspend{client: c.ClientFactory}, cobra.Command{
+		Use: "suspend [flags] APP_NAME...",
+		Example: `
+acorn app suspend my-app
+
+acorn app suspend my-app1 my-app2`,
+		SilenceUsage:      true,
+		Short:             "Suspend an app, scaling it to zero while keeping its volumes and secrets",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: newCompletion(c.ClientFactory, appsCompletion).complete,
+	})
+}
+
+type AppSuspend struct {
+	client ClientFactory
+}
+
+func (a *AppSuspend) Run(cmd *cobra.Command, args []string) error {
+	c, err := a.client.CreateDefault()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		err := c.AppSuspend(cmd.Context(), arg)
+		if err != nil {
+			return fmt.Errorf("suspending %s: %w", arg, err)
+		}
+		fmt.Println(arg)
+	}
+
+	return nil
+}
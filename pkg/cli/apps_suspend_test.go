@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/acorn-io/acorn/pkg/cli/testdata"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppSuspendAndResume(t *testing.T) {
+	var _, w, _ = os.Pipe()
+	commandContext := CommandContext{
+		ClientFactory: &testdata.MockClientFactory{},
+		StdOut:        w,
+		StdErr:        w,
+		StdIn:         strings.NewReader(""),
+	}
+	tests := []struct {
+		name    string
+		cmd     func(CommandContext) *cobra.Command
+		args    []string
+		wantErr bool
+		wantOut string
+	}{
+		{
+			name:    "acorn app suspend found",
+			cmd:     NewAppSuspend,
+			args:    []string{"found", "found.container"},
+			wantOut: "found\nfound.container\n",
+		},
+		{
+			name:    "acorn app suspend dne",
+			cmd:     NewAppSuspend,
+			args:    []string{"dne"},
+			wantErr: true,
+			wantOut: "suspending dne: error: app dne does not exist",
+		},
+		{
+			name:    "acorn app resume found",
+			cmd:     NewAppResume,
+			args:    []string{"found"},
+			wantOut: "found\n",
+		},
+		{
+			name:    "acorn app resume dne",
+			cmd:     NewAppResume,
+			args:    []string{"dne"},
+			wantErr: true,
+			wantOut: "resuming dne: error: app dne does not exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+			cmd := tt.cmd(commandContext)
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err != nil && !tt.wantErr {
+				assert.Failf(t, "got err when err not expected", "got err: %s", err.Error())
+			} else if err != nil && tt.wantErr {
+				assert.Equal(t, tt.wantOut, err.Error())
+			} else {
+				w.Close()
+				out, _ := io.ReadAll(r)
+				assert.Equal(t, tt.wantOut, string(out))
+			}
+		})
+	}
+}
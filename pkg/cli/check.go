@@ -39,6 +39,8 @@ func (a *Check) Run(cmd *cobra.Command, args []string) error {
 		install.CheckDefaultStorageClass,
 		install.CheckIngressCapability,
 		install.CheckExec,
+		install.CheckNetworkPolicies,
+		install.CheckLoadBalancer,
 	)
 
 	failures := 0
@@ -1,9 +1,16 @@
 package cli
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+
 	cli "github.com/acorn-io/acorn/pkg/cli/builder"
 	"github.com/acorn-io/acorn/pkg/controller"
+	"github.com/acorn-io/acorn/pkg/tracing/otlp"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
 )
 
 func NewController(c CommandContext) *cobra.Command {
@@ -17,10 +24,26 @@ func NewController(c CommandContext) *cobra.Command {
 }
 
 type Controller struct {
-	client ClientFactory
+	HealthPort    int    `usage:"Port of the health endpoint reporting the reconcile backlog (0 to disable, the default)" env:"ACORN_CONTROLLER_HEALTH_PORT"`
+	TraceEndpoint string `usage:"OTLP gRPC endpoint that OpenTelemetry traces of the reconcile steps are exported to (empty to disable)" env:"ACORN_CONTROLLER_TRACE_ENDPOINT"`
+	TraceInsecure bool   `usage:"Export traces to the OTLP endpoint without TLS" env:"ACORN_CONTROLLER_TRACE_INSECURE"`
+	client        ClientFactory
 }
 
 func (s *Controller) Run(cmd *cobra.Command, _ []string) error {
+	if s.TraceEndpoint != "" {
+		exporter, err := otlp.NewExporter(cmd.Context(), s.TraceEndpoint, s.TraceInsecure)
+		if err != nil {
+			return err
+		}
+		provider := otlp.NewProvider(exporter)
+		otel.SetTracerProvider(provider)
+		defer func() {
+			// flush the spans that are still batched, the command context is already done at this point
+			_ = provider.Shutdown(context.Background())
+		}()
+	}
+
 	c, err := controller.New()
 	if err != nil {
 		return err
@@ -28,6 +51,20 @@ func (s *Controller) Run(cmd *cobra.Command, _ []string) error {
 	if err := c.Start(cmd.Context()); err != nil {
 		return err
 	}
+
+	if s.HealthPort != 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/healthz", c.Health())
+		address := fmt.Sprintf("0.0.0.0:%d", s.HealthPort)
+		go func() {
+			logrus.Infof("Serving health endpoint on %s", address)
+			// the controller keeps running without its health endpoint
+			if err := http.ListenAndServe(address, mux); err != nil {
+				logrus.Errorf("Failed to serve health endpoint on %s: %v", address, err)
+			}
+		}()
+	}
+
 	<-cmd.Context().Done()
 	return nil
 }
@@ -1,14 +1,19 @@
 package cli
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"strings"
 
 	cli "github.com/acorn-io/acorn/pkg/cli/builder"
 	"github.com/spf13/cobra"
 )
 
 func NewDev(c CommandContext) *cobra.Command {
-	cmd := cli.Command(&Dev{out: c.StdOut, client: c.ClientFactory}, cobra.Command{
+	cmd := cli.Command(&Dev{in: c.StdIn, out: c.StdOut, errOut: c.StdErr, client: c.ClientFactory}, cobra.Command{
 		Use:               "dev [flags] IMAGE|DIRECTORY [acorn args]",
 		SilenceUsage:      true,
 		Short:             "Run an app from an image or Acornfile in dev mode or attach a dev session to a currently running app",
@@ -18,6 +23,8 @@ acorn dev <IMAGE>
 acorn dev .
 acorn dev --name wandering-sound
 acorn dev --name wandering-sound <IMAGE>
+cat Acornfile | acorn dev -
+acorn dev https://example.com/Acornfile
 `})
 
 	// This will produce an error if the volume flag doesn't exist or a completion function has already
@@ -37,9 +44,14 @@ acorn dev --name wandering-sound <IMAGE>
 
 type Dev struct {
 	RunArgs
-	BidirectionalSync bool `usage:"In interactive mode download changes in addition to uploading" short:"b"`
-	Replace           bool `usage:"Replace the app with only defined values, resetting undefined fields to default values" json:"replace,omitempty"` // Replace sets patchMode to false, resulting in a full update, resetting all undefined fields to their defaults
+	BidirectionalSync bool   `usage:"In interactive mode download changes in addition to uploading" short:"b"`
+	Replace           bool   `usage:"Replace the app with only defined values, resetting undefined fields to default values" json:"replace,omitempty"` // Replace sets patchMode to false, resulting in a full update, resetting all undefined fields to their defaults
+	JSON              bool   `usage:"Write dev lifecycle events (file changes, builds, app updates) to stderr as JSON lines"`
+	ContextDir        string `usage:"Directory to use as the build context (default the directory of the image)"`
+	LogContainer      string `usage:"Only stream the logs of the containers and sidecars with this name"`
+	in                io.Reader
 	out               io.Writer
+	errOut            io.Writer
 	client            ClientFactory
 }
 
@@ -49,8 +61,98 @@ func (s *Dev) Run(cmd *cobra.Command, args []string) error {
 		Dev:               true,
 		BidirectionalSync: s.BidirectionalSync,
 		Replace:           s.Replace,
+		contextDir:        s.ContextDir,
+		logContainer:      s.LogContainer,
+		in:                s.in,
 		out:               s.out,
 		client:            s.client,
 	}
+
+	// The events never go to stdout, where they would be interleaved with the logs of the app
+	if s.JSON {
+		run.events = s.errOut
+	}
+
+	// An app definition read from stdin or fetched from a URL is built and run once, as there is no local file to
+	// watch for changes
+	source := s.File
+	if !isStdinOrURL(source) && len(args) > 0 && isStdinOrURL(args[0]) {
+		source = args[0]
+		// the build context defaults to the current directory instead of the directory of the temporary file
+		args = args[1:]
+		if run.contextDir == "" {
+			run.contextDir = "."
+		}
+	}
+	if isStdinOrURL(source) {
+		var (
+			file string
+			err  error
+		)
+		if source == "-" {
+			file, err = writeStdinAcornfile(s.in)
+		} else {
+			file, err = writeURLAcornfile(cmd.Context(), source)
+		}
+		if err != nil {
+			return err
+		}
+		defer os.Remove(file)
+
+		run.File = file
+		run.noWatch = true
+	}
+
 	return run.Run(cmd, args)
 }
+
+func isStdinOrURL(source string) bool {
+	return source == "-" || strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// writeStdinAcornfile writes the app definition read from in to a temporary file and returns its path
+func writeStdinAcornfile(in io.Reader) (string, error) {
+	if in == nil {
+		return "", fmt.Errorf("no app definition to read from stdin")
+	}
+	f, err := os.CreateTemp("", "Acornfile-")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, in); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("reading app definition from stdin: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// writeURLAcornfile writes the app definition fetched from url to a temporary file and returns its path
+func writeURLAcornfile(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("fetching app definition from %s: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching app definition from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("fetching app definition from %s: unexpected status %s", url, resp.Status)
+	}
+
+	f, err := os.CreateTemp("", "Acornfile-")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("fetching app definition from %s: %w", url, err)
+	}
+	return f.Name(), nil
+}
@@ -1,18 +1,23 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/acorn-io/acorn/pkg/build"
 	"github.com/acorn-io/acorn/pkg/cli/testdata"
 	"github.com/acorn-io/acorn/pkg/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDev(t *testing.T) {
@@ -87,3 +92,46 @@ func TestDev(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteStdinAcornfile(t *testing.T) {
+	file, err := writeStdinAcornfile(strings.NewReader(`containers: web: image: "nginx"`))
+	require.NoError(t, err)
+	defer os.Remove(file)
+
+	appDef, err := build.ResolveAndParse(file)
+	require.NoError(t, err)
+	appSpec, err := appDef.AppSpec()
+	require.NoError(t, err)
+	assert.Equal(t, "nginx", appSpec.Containers["web"].Image)
+
+	_, err = writeStdinAcornfile(nil)
+	assert.Error(t, err)
+}
+
+func TestWriteURLAcornfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Acornfile" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`containers: web: image: "nginx"`))
+	}))
+	defer server.Close()
+
+	file, err := writeURLAcornfile(context.Background(), server.URL+"/Acornfile")
+	require.NoError(t, err)
+	defer os.Remove(file)
+
+	appDef, err := build.ResolveAndParse(file)
+	require.NoError(t, err)
+	appSpec, err := appDef.AppSpec()
+	require.NoError(t, err)
+	assert.Equal(t, "nginx", appSpec.Containers["web"].Image)
+
+	_, err = writeURLAcornfile(context.Background(), server.URL+"/missing")
+	assert.EqualError(t, err, fmt.Sprintf("fetching app definition from %s/missing: unexpected status 404 Not Found", server.URL))
+
+	server.Close()
+	_, err = writeURLAcornfile(context.Background(), server.URL+"/Acornfile")
+	assert.ErrorContains(t, err, "fetching app definition from "+server.URL)
+}
@@ -2,8 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
 	cli "github.com/acorn-io/acorn/pkg/cli/builder"
 	"github.com/acorn-io/acorn/pkg/client"
 	"github.com/acorn-io/acorn/pkg/tags"
@@ -13,8 +16,14 @@ import (
 
 func NewImageDelete(c CommandContext) *cobra.Command {
 	cmd := cli.Command(&ImageDelete{client: c.ClientFactory}, cobra.Command{
-		Use:               "rm [IMAGE_NAME...]",
-		Example:           `acorn image rm my-image`,
+		Use: "rm [IMAGE_NAME...]",
+		Example: `acorn image rm my-image
+
+# Delete all images created more than 30 days ago
+acorn image rm --older-than 30d
+
+# Preview which images would be deleted
+acorn image rm --older-than 30d --dry-run`,
 		SilenceUsage:      true,
 		Short:             "Delete an Image",
 		ValidArgsFunction: newCompletion(c.ClientFactory, imagesCompletion(true)).complete,
@@ -23,8 +32,36 @@ func NewImageDelete(c CommandContext) *cobra.Command {
 }
 
 type ImageDelete struct {
-	client ClientFactory
-	Force  bool `usage:"Force Delete" short:"f"`
+	client    ClientFactory
+	Force     bool   `usage:"Force Delete" short:"f"`
+	OlderThan string `usage:"Delete all images created more than this long ago (ex: 30d, 12h)"`
+	DryRun    bool   `usage:"Print the images that would be deleted without deleting them"`
+}
+
+// parseAge parses a duration that, in addition to the units of time.ParseDuration, may be a whole number of days
+func parseAge(age string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", age)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(age)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", age)
+	}
+	return d, nil
+}
+
+// imagesOlderThan returns the IDs of the images created before the cutoff
+func imagesOlderThan(images []apiv1.Image, cutoff time.Time) (result []string) {
+	for _, image := range images {
+		if !image.CreationTimestamp.IsZero() && image.CreationTimestamp.Time.Before(cutoff) {
+			result = append(result, image.Name)
+		}
+	}
+	return
 }
 
 func (a *ImageDelete) Run(cmd *cobra.Command, args []string) error {
@@ -33,7 +70,28 @@ func (a *ImageDelete) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	for _, image := range args {
+	images := args
+	if a.OlderThan != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("image names can not be combined with --older-than")
+		}
+		age, err := parseAge(a.OlderThan)
+		if err != nil {
+			return err
+		}
+		allImages, err := c.ImageList(cmd.Context())
+		if err != nil {
+			return err
+		}
+		images = imagesOlderThan(allImages, time.Now().Add(-age))
+	}
+
+	for _, image := range images {
+		if a.DryRun {
+			fmt.Println(image)
+			continue
+		}
+
 		opts := []name.Option{name.WithDefaultRegistry("")}
 
 		if strings.HasPrefix("sha256:", image) || tags.SHAPermissivePrefixPattern.MatchString(image) {
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/cli/testdata"
@@ -428,6 +429,107 @@ func TestImage(t *testing.T) {
 			wantErr: false,
 			wantOut: "ff12345\n",
 		},
+		{
+			name: "acorn image rm --older-than 30d --dry-run", fields: fields{
+				All:    false,
+				Quiet:  false,
+				Output: "",
+			},
+			commandContext: CommandContext{
+				ClientFactory: &testdata.MockClientFactory{
+					ImageList: []apiv1.Image{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "old-image", CreationTimestamp: metav1.NewTime(time.Now().Add(-40 * 24 * time.Hour))},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "new-image", CreationTimestamp: metav1.NewTime(time.Now().Add(-24 * time.Hour))},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "unknown-age-image"},
+						},
+					},
+				},
+				StdOut: w,
+				StdErr: w,
+				StdIn:  strings.NewReader("y\n"),
+			},
+			args: args{
+				args:   []string{"rm", "--older-than", "30d", "--dry-run"},
+				client: &testdata.MockClient{},
+			},
+			wantErr: false,
+			wantOut: "old-image\n",
+		},
+		{
+			name: "acorn image rm --older-than 72h", fields: fields{
+				All:    false,
+				Quiet:  false,
+				Output: "",
+			},
+			commandContext: CommandContext{
+				ClientFactory: &testdata.MockClientFactory{
+					ImageList: []apiv1.Image{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "old-image", CreationTimestamp: metav1.NewTime(time.Now().Add(-40 * 24 * time.Hour))},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "new-image", CreationTimestamp: metav1.NewTime(time.Now().Add(-24 * time.Hour))},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "unknown-age-image"},
+						},
+					},
+					ImageItem: &apiv1.Image{},
+				},
+				StdOut: w,
+				StdErr: w,
+				StdIn:  strings.NewReader("y\n"),
+			},
+			args: args{
+				args:   []string{"rm", "--older-than", "72h"},
+				client: &testdata.MockClient{},
+			},
+			wantErr: false,
+			wantOut: "old-image\n",
+		},
+		{
+			name: "acorn image rm --older-than 30d found-image1234567", fields: fields{
+				All:    false,
+				Quiet:  false,
+				Output: "",
+			},
+			commandContext: CommandContext{
+				ClientFactory: &testdata.MockClientFactory{},
+				StdOut:        w,
+				StdErr:        w,
+				StdIn:         strings.NewReader("y\n"),
+			},
+			args: args{
+				args:   []string{"rm", "--older-than", "30d", "found-image1234567"},
+				client: &testdata.MockClient{},
+			},
+			wantErr: true,
+			wantOut: "image names can not be combined with --older-than",
+		},
+		{
+			name: "acorn image rm --older-than 30x", fields: fields{
+				All:    false,
+				Quiet:  false,
+				Output: "",
+			},
+			commandContext: CommandContext{
+				ClientFactory: &testdata.MockClientFactory{},
+				StdOut:        w,
+				StdErr:        w,
+				StdIn:         strings.NewReader("y\n"),
+			},
+			args: args{
+				args:   []string{"rm", "--older-than", "30x"},
+				client: &testdata.MockClient{},
+			},
+			wantErr: true,
+			wantOut: "invalid age \"30x\"",
+		},
 	}
 
 	for _, tt := range tests {
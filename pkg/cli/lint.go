@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/acorn-io/acorn/pkg/build"
+	cli "github.com/acorn-io/acorn/pkg/cli/builder"
+	"github.com/acorn-io/acorn/pkg/lint"
+	"github.com/spf13/cobra"
+)
+
+func NewLint(c CommandContext) *cobra.Command {
+	return cli.Command(&Lint{out: c.StdOut}, cobra.Command{
+		Use:          "lint [flags] [ACORNFILE]",
+		SilenceUsage: true,
+		Short:        "Check an Acornfile for problems without a cluster",
+		Args:         cobra.MaximumNArgs(1),
+	})
+}
+
+type Lint struct {
+	Profile []string `usage:"Profile to assign default values"`
+	out     io.Writer
+}
+
+func (s *Lint) Run(cmd *cobra.Command, args []string) error {
+	file := "Acornfile"
+	if len(args) > 0 {
+		file = args[0]
+		if s, err := os.Stat(file); err == nil && s.IsDir() {
+			file = filepath.Join(file, "Acornfile")
+		}
+	}
+
+	appDef, err := build.ResolveAndParse(file)
+	if err != nil {
+		return err
+	}
+
+	appDef, _, err = appDef.WithArgs(nil, s.Profile)
+	if err != nil {
+		return err
+	}
+
+	appSpec, err := appDef.AppSpec()
+	if err != nil {
+		return err
+	}
+
+	errs := lint.AppSpec(appSpec)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	for _, err := range errs {
+		fmt.Fprintln(s.out, err.Error())
+	}
+	return fmt.Errorf("found %d problem(s) in %s", len(errs), file)
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// followMaxRetries is how many times acorn logs -f re-attaches to an app without seeing any new log lines
+const followMaxRetries = 5
+
 func NewLogs(c CommandContext) *cobra.Command {
 	logs := &Logs{client: c.ClientFactory}
 	return cli.Command(logs, cobra.Command{
@@ -50,9 +53,15 @@ func (s *Logs) Run(cmd *cobra.Command, args []string) error {
 	} else {
 		tailLines = &s.Tail
 	}
-	return log.Output(cmd.Context(), c, args[0], &client.LogOptions{
+	opts := &client.LogOptions{
 		Follow: s.Follow,
 		Tail:   tailLines,
 		Since:  s.Since,
-	})
+	}
+	if s.Follow {
+		backoff := log.DefaultFollowBackoff
+		backoff.MaxRetries = followMaxRetries
+		return log.Follow(cmd.Context(), c, args[0], opts, backoff)
+	}
+	return log.Output(cmd.Context(), c, args[0], opts)
 }
@@ -9,7 +9,7 @@ import (
 )
 
 func NewRegions(c CommandContext) *cobra.Command {
-	return cli.Command(&Regions{client: c.ClientFactory}, cobra.Command{
+	cmd := cli.Command(&Regions{client: c.ClientFactory}, cobra.Command{
 		Use:     "regions [flags] [REGION...]",
 		Aliases: []string{"region"},
 		Example: `
@@ -18,6 +18,8 @@ acorn offering regions`,
 		Short:             "List available regions",
 		ValidArgsFunction: newCompletion(c.ClientFactory, regionsCompletion).complete,
 	})
+	cmd.AddCommand(NewRegionDescribe(c))
+	return cmd
 }
 
 type Regions struct {
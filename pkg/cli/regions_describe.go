@@ -0,0 +1,64 @@
+package cli
+
+import (
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	cli "github.com/acorn-io/acorn/pkg/cli/builder"
+	"github.com/acorn-io/acorn/pkg/cli/builder/table"
+	"github.com/acorn-io/acorn/pkg/tables"
+	"github.com/spf13/cobra"
+)
+
+func NewRegionDescribe(c CommandContext) *cobra.Command {
+	return cli.Command(&RegionDescribe{client: c.ClientFactory}, cobra.Command{
+		Use: "describe [flags] REGION",
+		Example: `
+acorn offering regions describe local`,
+		SilenceUsage:      true,
+		Short:             "Show the details of a region",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newCompletion(c.ClientFactory, regionsCompletion).withShouldCompleteOptions(onlyNumArgs(1)).complete,
+	})
+}
+
+type RegionDescribe struct {
+	Output string `usage:"Output format (json, yaml, {{gotemplate}})" short:"o"`
+	client ClientFactory
+}
+
+// regionDescription is a region along with details that are relative to the current project.
+type regionDescription struct {
+	*apiv1.Region
+	Default bool `json:"default"`
+}
+
+func (a *RegionDescribe) Run(cmd *cobra.Command, args []string) error {
+	c, err := a.client.CreateDefault()
+	if err != nil {
+		return err
+	}
+
+	region, err := c.RegionGet(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	desc := regionDescription{
+		Region: region,
+	}
+	if projectName := c.GetProject(); projectName != "" {
+		project, err := c.ProjectGet(cmd.Context(), projectName)
+		if err != nil {
+			return err
+		}
+		desc.Default = project != nil && project.GetRegion() == region.Name
+	}
+
+	format := a.Output
+	if format == "" {
+		format = tables.RegionDescribe
+	}
+
+	out := table.NewWriter(nil, false, format)
+	out.Write(desc)
+	return out.Err()
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/cli/testdata"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -119,3 +120,84 @@ func TestRegions(t *testing.T) {
 		})
 	}
 }
+
+func TestRegionDescribe(t *testing.T) {
+	tenYearsAgo := metav1.Now().AddDate(-10, 0, 0)
+	region := apiv1.Region{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "local",
+			CreationTimestamp: metav1.NewTime(tenYearsAgo),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					Name: "local",
+				},
+			},
+		},
+		Spec: apiv1.RegionSpec{
+			Description: "Test region",
+			RegionName:  "us-east-2",
+		},
+		Status: apiv1.RegionStatus{
+			Conditions: []v1.Condition{
+				{
+					Type:   apiv1.RegionConditionClusterReady,
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		wantOut string
+	}{
+		{
+			name: "acorn regions describe known region",
+			args: []string{"describe", "local"},
+			wantOut: "Name:             local\n" +
+				"Account:          local\n" +
+				"Region Name:      us-east-2\n" +
+				"Default:          true\n" +
+				"Created:          10y ago\n" +
+				"Description:      Test region\n" +
+				"Conditions:       \n" +
+				"  ClusterReady:   True      \n",
+		},
+		{
+			name:    "acorn regions describe unknown region",
+			args:    []string{"describe", "unknown"},
+			wantErr: true,
+			wantOut: "regions.api.acorn.io \"unknown\" not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+			cmd := NewRegions(CommandContext{
+				ClientFactory: &testdata.MockClientFactory{
+					RegionList: []apiv1.Region{region},
+					ProjectItem: &apiv1.Project{
+						ObjectMeta: metav1.ObjectMeta{Name: "project"},
+						Spec:       apiv1.ProjectSpec{DefaultRegion: "local"},
+					},
+				},
+				StdOut: w,
+				StdErr: w,
+				StdIn:  strings.NewReader(""),
+			})
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err != nil && !tt.wantErr {
+				assert.Failf(t, "got err when err not expected", "got err: %s", err.Error())
+			} else if err != nil && tt.wantErr {
+				assert.Equal(t, tt.wantOut, err.Error())
+			} else {
+				assert.Nil(t, w.Close(), "error closing writer")
+				out, _ := io.ReadAll(r)
+				assert.Equal(t, tt.wantOut, string(out))
+			}
+		})
+	}
+}
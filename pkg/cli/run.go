@@ -12,6 +12,7 @@ import (
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	cli "github.com/acorn-io/acorn/pkg/cli/builder"
 	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/client/term"
 	"github.com/acorn-io/acorn/pkg/dev"
 	"github.com/acorn-io/acorn/pkg/imagesource"
 	"github.com/acorn-io/acorn/pkg/rulerequest"
@@ -23,7 +24,7 @@ import (
 )
 
 func NewRun(c CommandContext) *cobra.Command {
-	cmd := cli.Command(&Run{out: c.StdOut, client: c.ClientFactory}, cobra.Command{
+	cmd := cli.Command(&Run{in: c.StdIn, out: c.StdOut, client: c.ClientFactory}, cobra.Command{
 		Use:               "run [flags] IMAGE|DIRECTORY [acorn args]",
 		SilenceUsage:      true,
 		Short:             "Run an app from an image or Acornfile",
@@ -115,9 +116,16 @@ type Run struct {
 	Quiet             bool  `usage:"Do not print status" short:"q"`
 	Update            bool  `usage:"Update the app if it already exists" short:"u"`
 	Replace           bool  `usage:"Replace the app with only defined values, resetting undefined fields to default values" json:"replace,omitempty"` // Replace sets patchMode to false, resulting in a full update, resetting all undefined fields to their defaults
-
-	out    io.Writer
-	client ClientFactory
+	Recreate          bool  `usage:"Delete and run the app again if it already exists, keeping its volumes and bound secrets"`
+	OutputPermissions bool  `usage:"If the app requests permissions, output the app granting them as yaml for an admin to apply instead of running it"`
+
+	events       io.Writer
+	contextDir   string
+	noWatch      bool
+	logContainer string
+	in           io.Reader
+	out          io.Writer
+	client       ClientFactory
 }
 
 type RunArgs struct {
@@ -141,6 +149,7 @@ type RunArgs struct {
 	Interval        string   `usage:"If configured for auto-upgrade, this is the time interval at which to check for new releases (ex: 1h, 5m)"`
 	Memory          []string `usage:"Set memory for a workload in the format of workload=memory. Only specify an amount to set all workloads. (ex foo=512Mi or 512Mi)" short:"m"`
 	ComputeClass    []string `usage:"Set computeclass for a workload in the format of workload=computeclass. Specify a single computeclass to set all workloads. (ex foo=example-class or example-class)"`
+	PrePull         *bool    `usage:"Pull the images of the app onto every node ahead of the containers that use them"`
 }
 
 func (s RunArgs) ToOpts() (client.AppRunOptions, error) {
@@ -156,6 +165,7 @@ func (s RunArgs) ToOpts() (client.AppRunOptions, error) {
 	opts.AutoUpgrade = s.AutoUpgrade
 	opts.NotifyUpgrade = s.NotifyUpgrade
 	opts.AutoUpgradeInterval = s.Interval
+	opts.PrePull = s.PrePull
 
 	opts.Memory, err = v1.ParseMemory(s.Memory)
 	if err != nil {
@@ -238,13 +248,21 @@ func (s *Run) Run(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	if s.Dev {
-		return dev.Dev(cmd.Context(), c, &dev.Options{
+		imageSource.ContextDir = s.contextDir
+		devOpts := &dev.Options{
 			ImageSource:       imageSource,
 			Run:               opts,
 			Replace:           s.Replace,
 			Dangerous:         s.Dangerous,
 			BidirectionalSync: s.BidirectionalSync,
-		})
+			NoWatch:           s.noWatch,
+			LogContainer:      s.logContainer,
+			Events:            s.events,
+		}
+		if s.in != nil && term.IsTerminal(s.in) {
+			devOpts.Input = s.in
+		}
+		return dev.Dev(cmd.Context(), c, devOpts)
 	}
 
 	defer func() {
@@ -253,6 +271,19 @@ func (s *Run) Run(cmd *cobra.Command, args []string) (err error) {
 		}
 	}()
 
+	if s.OutputPermissions && (s.Recreate || s.Replace || s.Update || s.Output != "") {
+		return fmt.Errorf("--output-permissions can not be combined with --recreate, --update, --replace or --output")
+	}
+
+	if s.Recreate {
+		if s.Replace || s.Update || s.Output != "" {
+			return fmt.Errorf("--recreate can not be combined with --update, --replace or --output")
+		}
+		if s.Name == "" {
+			return fmt.Errorf("--name is required for --recreate")
+		}
+	}
+
 	if s.Replace || s.Update {
 		if s.Output != "" {
 			return fmt.Errorf("--output can not be combined with --update or --replace")
@@ -278,7 +309,20 @@ func (s *Run) Run(cmd *cobra.Command, args []string) (err error) {
 		return outputApp(s.out, s.Output, app)
 	}
 
-	app, err = rulerequest.PromptRun(cmd.Context(), c, s.Dangerous, image, opts)
+	if s.OutputPermissions {
+		out := s.out
+		if out == nil {
+			out = os.Stdout
+		}
+		app, err = rulerequest.RunOrDocument(cmd.Context(), c, image, opts, out)
+		if err != nil || app == nil {
+			return err
+		}
+	} else if s.Recreate {
+		app, err = rulerequest.PromptRecreate(cmd.Context(), c, s.Dangerous, image, opts)
+	} else {
+		app, err = rulerequest.PromptRun(cmd.Context(), c, s.Dangerous, image, opts)
+	}
 	if err != nil {
 		return err
 	}
@@ -22,6 +22,9 @@ acorn secret`,
 	cmd.AddCommand(NewSecretDelete(c))
 	cmd.AddCommand(NewSecretReveal(c))
 	cmd.AddCommand(NewSecretEncrypt(c))
+	cmd.AddCommand(NewSecretDiff(c))
+	cmd.AddCommand(NewSecretExportApp(c))
+	cmd.AddCommand(NewSecretImportApp(c))
 	return cmd
 }
 
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	cli "github.com/acorn-io/acorn/pkg/cli/builder"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/encryption/nacl"
+	"github.com/spf13/cobra"
+)
+
+func NewSecretExportApp(c CommandContext) *cobra.Command {
+	cmd := cli.Command(&SecretExportApp{client: c.ClientFactory, out: c.StdOut}, cobra.Command{
+		Use: "export-app [flags] APP_NAME",
+		Example: `
+# Export the secrets of an app to a bundle, generating a key pair to encrypt it with
+acorn secret export-app --out bundle.enc --key-out bundle.key my-app
+
+# Export the secrets of an app to a bundle encrypted with an existing public key
+acorn secret export-app --out bundle.enc --public-key 3Ub0BOPCZnPdjxbNPDuCPtCCoNn0CWtKZgC_vmfr-Xk my-app`,
+		SilenceUsage:      true,
+		Short:             "Export the secrets of an app to an encrypted bundle",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newCompletion(c.ClientFactory, appsCompletion).withShouldCompleteOptions(onlyNumArgs(1)).complete,
+	})
+	return cmd
+}
+
+type SecretExportApp struct {
+	Out       string `usage:"File to write the bundle to, defaults to stdout"`
+	PublicKey string `usage:"Public key to encrypt the bundle with"`
+	KeyOut    string `usage:"Generate a key pair to encrypt the bundle with and write the private key to this file"`
+	client    ClientFactory
+	out       io.Writer
+}
+
+func (s *SecretExportApp) Run(cmd *cobra.Command, args []string) error {
+	if (s.PublicKey == "") == (s.KeyOut == "") {
+		return fmt.Errorf("exactly one of --public-key or --key-out is required")
+	}
+
+	c, err := s.client.CreateDefault()
+	if err != nil {
+		return err
+	}
+
+	bundle, err := client.ExportAppSecrets(cmd.Context(), c, args[0])
+	if err != nil {
+		return err
+	}
+
+	publicKey := s.PublicKey
+	if s.KeyOut != "" {
+		var privateKey string
+		publicKey, privateKey, err = nacl.GenerateKeyPair()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(s.KeyOut, []byte(privateKey+"\n"), 0600); err != nil {
+			return err
+		}
+	}
+
+	data, err := bundle.Encrypt(publicKey)
+	if err != nil {
+		return err
+	}
+
+	if s.Out == "" {
+		_, err = fmt.Fprintln(s.out, string(data))
+		return err
+	}
+	return os.WriteFile(s.Out, data, 0600)
+}
+
+func NewSecretImportApp(c CommandContext) *cobra.Command {
+	cmd := cli.Command(&SecretImportApp{client: c.ClientFactory, out: c.StdOut}, cobra.Command{
+		Use: "import-app [flags] BUNDLE_FILE",
+		Example: `
+# Create secrets from a bundle exported with acorn secret export-app
+acorn secret import-app --private-key-file bundle.key bundle.enc
+
+# Then run the app with the secrets bound, using the --secret flags printed by the import
+acorn run --name my-app --secret my-app-db:db ...`,
+		SilenceUsage: true,
+		Short:        "Create secrets from a bundle exported with export-app",
+		Args:         cobra.ExactArgs(1),
+	})
+	return cmd
+}
+
+type SecretImportApp struct {
+	PrivateKeyFile string `usage:"File holding the private key to decrypt the bundle with"`
+	Prefix         string `usage:"Prefix of the names of the created secrets, defaults to the name of the exported app followed by -"`
+	client         ClientFactory
+	out            io.Writer
+}
+
+func (s *SecretImportApp) Run(cmd *cobra.Command, args []string) error {
+	if s.PrivateKeyFile == "" {
+		return fmt.Errorf("--private-key-file is required")
+	}
+
+	privateKey, err := os.ReadFile(s.PrivateKeyFile)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	bundle, err := client.DecryptSecretBundle([]byte(strings.TrimSpace(string(data))), string(privateKey))
+	if err != nil {
+		return err
+	}
+
+	c, err := s.client.CreateDefault()
+	if err != nil {
+		return err
+	}
+
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = bundle.App + "-"
+	}
+	bindings, err := client.ImportSecretBundle(cmd.Context(), c, bundle, prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, binding := range bindings {
+		fmt.Fprintf(s.out, "--secret %s\n", binding)
+	}
+	return nil
+}
@@ -1,14 +1,18 @@
 package cli
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"os"
 	"strings"
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	cli "github.com/acorn-io/acorn/pkg/cli/builder"
 	"github.com/acorn-io/aml/pkg/cue"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func NewSecretCreate(c CommandContext) *cobra.Command {
@@ -22,7 +26,13 @@ acorn secret create --data key-name=value --data key-name2=value2 my-secret
 acorn secret create --file secret.yaml my-secret
 
 # Read key value from a file
-acorn secret create --data @key-name=secret.yaml my-secret`,
+acorn secret create --data @key-name=secret.yaml my-secret
+
+# Import an existing TLS certificate and key
+acorn secret create --type tls --cert cert.pem --key key.pem --ca ca.pem my-tls
+
+# Import an existing CA that can sign other certificates
+acorn secret create --type tls --ca-cert ca.pem --ca-key ca.key my-ca`,
 		SilenceUsage: true,
 		Short:        "Create a secret",
 		Args:         cobra.ExactArgs(1),
@@ -34,9 +44,19 @@ type SecretCreate struct {
 	Data   []string `usage:"Secret data format key=value or @key=filename to read from file"`
 	File   string   `usage:"File to read for entire secret in cue/yaml/json format"`
 	Type   string   `usage:"Secret type"`
+	Cert   string   `usage:"File to read the TLS certificate from"`
+	Key    string   `usage:"File to read the TLS private key from"`
+	CA     string   `usage:"File to read the TLS CA certificate from"`
+	CACert string   `usage:"File to read the certificate of a CA that can sign other certificates from" name:"ca-cert"`
+	CAKey  string   `usage:"File to read the private key of a CA that can sign other certificates from" name:"ca-key"`
 	client ClientFactory
 }
 
+const (
+	caCertKey = "ca.crt"
+	caKeyKey  = "ca.key"
+)
+
 func (a *SecretCreate) buildSecret() (*apiv1.Secret, error) {
 	secret := &struct {
 		apiv1.Secret `json:",inline"`
@@ -75,6 +95,20 @@ func (a *SecretCreate) buildSecret() (*apiv1.Secret, error) {
 		secret.Data[key] = []byte(value)
 	}
 
+	if a.Cert != "" || a.Key != "" || a.CA != "" || a.CACert != "" || a.CAKey != "" {
+		tlsData, err := a.readTLS()
+		if err != nil {
+			return nil, err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		for k, v := range tlsData {
+			secret.Data[k] = v
+		}
+		secret.Type = strings.TrimPrefix(string(v1.SecretTypeTLS), v1.SecretTypePrefix)
+	}
+
 	if a.Type != "" {
 		secret.Type = a.Type
 	}
@@ -82,6 +116,101 @@ func (a *SecretCreate) buildSecret() (*apiv1.Secret, error) {
 	return &secret.Secret, nil
 }
 
+func (a *SecretCreate) readTLS() (map[string][]byte, error) {
+	if a.CACert != "" || a.CAKey != "" {
+		if a.CA != "" {
+			return nil, fmt.Errorf("--ca can't be used with --ca-cert, the CA certificate is read from --ca-cert")
+		}
+		data, err := a.readCA()
+		if err != nil {
+			return nil, err
+		}
+		if a.Cert == "" && a.Key == "" {
+			// the CA is the certificate of the secret
+			data[corev1.TLSCertKey] = data[caCertKey]
+			data[corev1.TLSPrivateKeyKey] = data[caKeyKey]
+			return data, nil
+		}
+		cert, err := a.readCertAndKey()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range cert {
+			data[k] = v
+		}
+		return data, nil
+	}
+
+	return a.readCertAndKey()
+}
+
+// readCertAndKey reads the certificate of the secret, its key and the optional CA certificate
+func (a *SecretCreate) readCertAndKey() (map[string][]byte, error) {
+	if a.Cert == "" || a.Key == "" {
+		return nil, fmt.Errorf("both --cert and --key are required to create a TLS secret")
+	}
+
+	data, err := readFiles(map[string]string{
+		corev1.TLSCertKey:       a.Cert,
+		corev1.TLSPrivateKeyKey: a.Key,
+		caCertKey:               a.CA,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tls.X509KeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey]); err != nil {
+		return nil, fmt.Errorf("invalid certificate and key pair [%s, %s]: %w", a.Cert, a.Key, err)
+	}
+
+	return data, nil
+}
+
+// readCA reads the certificate and key of a CA, which must be able to sign other certificates
+func (a *SecretCreate) readCA() (map[string][]byte, error) {
+	if a.CACert == "" || a.CAKey == "" {
+		return nil, fmt.Errorf("both --ca-cert and --ca-key are required to import a CA")
+	}
+
+	data, err := readFiles(map[string]string{
+		caCertKey: a.CACert,
+		caKeyKey:  a.CAKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := tls.X509KeyPair(data[caCertKey], data[caKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate and key pair [%s, %s]: %w", a.CACert, a.CAKey, err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate [%s]: %w", a.CACert, err)
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate [%s] is not a CA, it can't sign other certificates", a.CACert)
+	}
+
+	return data, nil
+}
+
+// readFiles reads the content of each file into the key of the file, files that are not set are skipped
+func readFiles(files map[string]string) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for key, file := range files {
+		if file == "" {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		data[key] = content
+	}
+	return data, nil
+}
+
 func (a *SecretCreate) Run(cmd *cobra.Command, args []string) error {
 	client, err := a.client.CreateDefault()
 	if err != nil {
@@ -26,3 +26,86 @@ func TestBuildSecret(t *testing.T) {
 		"key4": []byte("value4"),
 	}, secret.Data)
 }
+
+func TestBuildSecretTLS(t *testing.T) {
+	c := SecretCreate{
+		Cert: "testdata/secret/tls.crt",
+		Key:  "testdata/secret/tls.key",
+		CA:   "testdata/secret/ca.crt",
+	}
+
+	secret, err := c.buildSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "tls", secret.Type)
+	assert.Len(t, secret.Data, 3)
+	assert.Contains(t, string(secret.Data["tls.crt"]), "BEGIN CERTIFICATE")
+	assert.Contains(t, string(secret.Data["tls.key"]), "PRIVATE KEY")
+	assert.Contains(t, string(secret.Data["ca.crt"]), "BEGIN CERTIFICATE")
+}
+
+func TestBuildSecretTLSMismatchedKey(t *testing.T) {
+	c := SecretCreate{
+		Type: "tls",
+		Cert: "testdata/secret/tls.crt",
+		Key:  "testdata/secret/mismatched.key",
+	}
+
+	_, err := c.buildSecret()
+	assert.ErrorContains(t, err, "invalid certificate and key pair")
+}
+
+func TestBuildSecretTLSMissingKey(t *testing.T) {
+	c := SecretCreate{
+		Type: "tls",
+		Cert: "testdata/secret/tls.crt",
+	}
+
+	_, err := c.buildSecret()
+	assert.ErrorContains(t, err, "both --cert and --key are required")
+}
+
+func TestBuildSecretTLSImportCA(t *testing.T) {
+	c := SecretCreate{
+		Type:   "tls",
+		CACert: "testdata/secret/signing-ca.crt",
+		CAKey:  "testdata/secret/signing-ca.key",
+	}
+
+	secret, err := c.buildSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "tls", secret.Type)
+	assert.Len(t, secret.Data, 4)
+	assert.Contains(t, string(secret.Data["ca.crt"]), "BEGIN CERTIFICATE")
+	assert.Contains(t, string(secret.Data["ca.key"]), "PRIVATE KEY")
+	// the CA is also the certificate of the secret
+	assert.Equal(t, secret.Data["ca.crt"], secret.Data["tls.crt"])
+	assert.Equal(t, secret.Data["ca.key"], secret.Data["tls.key"])
+}
+
+func TestBuildSecretTLSImportNotCA(t *testing.T) {
+	c := SecretCreate{
+		Type:   "tls",
+		CACert: "testdata/secret/leaf.crt",
+		CAKey:  "testdata/secret/leaf.key",
+	}
+
+	_, err := c.buildSecret()
+	assert.ErrorContains(t, err, "certificate [testdata/secret/leaf.crt] is not a CA")
+}
+
+func TestBuildSecretTLSImportCAMismatchedKey(t *testing.T) {
+	c := SecretCreate{
+		Type:   "tls",
+		CACert: "testdata/secret/signing-ca.crt",
+		CAKey:  "testdata/secret/leaf.key",
+	}
+
+	_, err := c.buildSecret()
+	assert.ErrorContains(t, err, "invalid CA certificate and key pair")
+}
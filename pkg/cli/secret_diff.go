@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+
+	cli "github.com/acorn-io/acorn/pkg/cli/builder"
+	"github.com/acorn-io/acorn/pkg/cli/builder/table"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/prompt"
+	"github.com/spf13/cobra"
+	"k8s.io/utils/strings/slices"
+)
+
+func NewSecretDiff(c CommandContext) *cobra.Command {
+	cmd := cli.Command(&SecretDiff{client: c.ClientFactory}, cobra.Command{
+		Use: "diff [flags] SECRET_A SECRET_B",
+		Example: `
+# Compare the keys of two secrets
+acorn secret diff my-secret my-other-secret
+
+# Also compare the values of the keys both secrets have
+acorn secret diff --values my-secret my-other-secret`,
+		SilenceUsage:      true,
+		Short:             "Compare the keys of two secrets",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: newCompletion(c.ClientFactory, secretsCompletion).complete,
+	})
+	return cmd
+}
+
+type SecretDiff struct {
+	Values bool   `usage:"Compare the values of the common keys, this reveals the data of both secrets"`
+	Yes    bool   `usage:"Do not prompt before comparing values" short:"y"`
+	Output string `usage:"Output format (json, yaml, {{gotemplate}})" short:"o"`
+	client ClientFactory
+}
+
+type secretDiffEntry struct {
+	Key    string
+	Status string
+}
+
+func (a *SecretDiff) Run(cmd *cobra.Command, args []string) error {
+	c, err := a.client.CreateDefault()
+	if err != nil {
+		return err
+	}
+
+	get := c.SecretGet
+	if a.Values {
+		if !a.Yes {
+			ok, err := prompt.Bool("Comparing values reveals the data of both secrets. Do you want to continue?", false)
+			if err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+		}
+		get = c.SecretReveal
+	}
+
+	secretA, err := get(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+	secretB, err := get(cmd.Context(), args[1])
+	if err != nil {
+		return err
+	}
+
+	diff := client.DiffSecrets(secretA, secretB)
+
+	out := table.NewWriter([][]string{
+		{"KEY", "Key"},
+		{"STATUS", "Status"},
+	}, false, a.Output)
+
+	for _, key := range diff.OnlyA {
+		out.Write(&secretDiffEntry{Key: key, Status: fmt.Sprintf("only in %s", args[0])})
+	}
+	for _, key := range diff.OnlyB {
+		out.Write(&secretDiffEntry{Key: key, Status: fmt.Sprintf("only in %s", args[1])})
+	}
+	for _, key := range diff.Common {
+		status := "in both"
+		if a.Values {
+			status = "same value"
+			if slices.Contains(diff.Changed, key) {
+				status = "different value"
+			}
+		}
+		out.Write(&secretDiffEntry{Key: key, Status: status})
+	}
+
+	return out.Err()
+}
@@ -185,6 +185,26 @@ func (m *MockClient) AppStart(ctx context.Context, name string) error {
 	return fmt.Errorf("error: app %s does not exist", name)
 }
 
+func (m *MockClient) AppSuspend(ctx context.Context, name string) error {
+	switch name {
+	case "found":
+		return nil
+	case "found.container":
+		return nil
+	}
+	return fmt.Errorf("error: app %s does not exist", name)
+}
+
+func (m *MockClient) AppResume(ctx context.Context, name string) error {
+	switch name {
+	case "found":
+		return nil
+	case "found.container":
+		return nil
+	}
+	return fmt.Errorf("error: app %s does not exist", name)
+}
+
 func (m *MockClient) AppRun(ctx context.Context, image string, opts *client.AppRunOptions) (*apiv1.App, error) {
 	if m.AppItem != nil {
 		return m.AppItem, nil
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	cli "github.com/acorn-io/acorn/pkg/cli/builder"
+	"github.com/acorn-io/acorn/pkg/cli/builder/table"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+func NewVolumeConsumers(c CommandContext) *cobra.Command {
+	cmd := cli.Command(&VolumeConsumers{client: c.ClientFactory}, cobra.Command{
+		Use: "consumers [flags] VOLUME_NAME",
+		Example: `
+acorn volume consumers my-app.data`,
+		SilenceUsage:      true,
+		Short:             "List the apps that use a volume",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newCompletion(c.ClientFactory, volumesCompletion).complete,
+	})
+	return cmd
+}
+
+type VolumeConsumers struct {
+	Output string `usage:"Output format (json, yaml, {{gotemplate}})" short:"o"`
+	client ClientFactory
+}
+
+func (a *VolumeConsumers) Run(cmd *cobra.Command, args []string) error {
+	c, err := a.client.CreateDefault()
+	if err != nil {
+		return err
+	}
+
+	consumers, err := client.VolumeConsumers(cmd.Context(), c, args[0])
+	if err != nil {
+		return err
+	}
+
+	out := table.NewWriter([][]string{
+		{"APP", "App"},
+		{"VOLUME", "Target"},
+		{"BOUND", "Bound"},
+	}, false, a.Output)
+
+	for _, consumer := range consumers {
+		out.Write(consumer)
+	}
+
+	return out.Err()
+}
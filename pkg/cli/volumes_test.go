@@ -13,6 +13,7 @@ import (
 	"github.com/golang/mock/gomock"
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/cli/testdata"
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/spf13/cobra"
@@ -206,6 +207,25 @@ func TestVolume(t *testing.T) {
 			},
 			wantOut: "NAME        APP-NAME   BOUND-VOLUME   CAPACITY   VOLUME-CLASS   STATUS    ACCESS-MODES   CREATED\nmy-volume                             <nil>      my-class                                10y ago\n",
 		},
+		{
+			name: "acorn volume consumers found.vol",
+			prepare: func(f *mocks.MockClient) {
+				defaultMockPreparation(f)
+				f.EXPECT().AppList(gomock.Any()).Return([]apiv1.App{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+						Spec: v1.AppInstanceSpec{Volumes: []v1.VolumeBinding{
+							{Volume: "found.vol", Target: "source"},
+						}},
+					},
+				}, nil)
+			},
+			args: args{
+				args:   []string{"consumers", "found.vol"},
+				client: &testdata.MockClient{},
+			},
+			wantOut: "APP       VOLUME    BOUND\nfound     vol       false\nbackup    source    true\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
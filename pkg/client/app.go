@@ -61,6 +61,7 @@ func ToApp(namespace, image string, opts *AppRunOptions) *apiv1.App {
 			AutoUpgradeInterval: opts.AutoUpgradeInterval,
 			Memory:              opts.Memory,
 			ComputeClasses:      opts.ComputeClasses,
+			PrePull:             opts.PrePull,
 		},
 	}
 }
@@ -145,6 +146,9 @@ func ToAppUpdate(ctx context.Context, c Client, name string, opts *AppUpdateOpti
 	if len(opts.ComputeClasses) != 0 {
 		app.Spec.ComputeClasses = opts.ComputeClasses
 	}
+	if opts.PrePull != nil {
+		app.Spec.PrePull = opts.PrePull
+	}
 
 	return app, nil
 }
@@ -413,8 +417,9 @@ func (c *DefaultClient) appStart(ctx context.Context, name string) error {
 	if err != nil {
 		return err
 	}
-	if app.Spec.Stop != nil && *app.Spec.Stop {
+	if app.Spec.GetStopped() {
 		app.Spec.Stop = new(bool)
+		app.Spec.Suspend = nil
 		return c.Client.Update(ctx, app)
 	}
 	return nil
@@ -446,6 +451,48 @@ func (c *DefaultClient) appStop(ctx context.Context, name string) error {
 	}
 	return nil
 }
+
+// AppSuspend stops the app and marks it as suspended. Like a stopped app, a suspended app keeps its volumes and
+// secrets.
+func (c *DefaultClient) AppSuspend(ctx context.Context, name string) (err error) {
+	for i := 0; i < 5; i++ {
+		err = c.setAppSuspend(ctx, name, true)
+		if apierrors.IsConflict(err) {
+			continue
+		}
+		return
+	}
+	return
+}
+
+// AppResume clears the suspension of the app. The app still won't run if it was also stopped.
+func (c *DefaultClient) AppResume(ctx context.Context, name string) (err error) {
+	for i := 0; i < 5; i++ {
+		err = c.setAppSuspend(ctx, name, false)
+		if apierrors.IsConflict(err) {
+			continue
+		}
+		return
+	}
+	return
+}
+
+func (c *DefaultClient) setAppSuspend(ctx context.Context, name string, suspend bool) error {
+	app := &apiv1.App{}
+	err := c.Client.Get(ctx, kclient.ObjectKey{
+		Name:      name,
+		Namespace: c.Namespace,
+	}, app)
+	if err != nil {
+		return err
+	}
+	if app.Spec.GetSuspended() != suspend {
+		app.Spec.Suspend = &suspend
+		return c.Client.Update(ctx, app)
+	}
+	return nil
+}
+
 func (c *DefaultClient) AppConfirmUpgrade(ctx context.Context, name string) error {
 	app := &apiv1.App{}
 	err := c.Client.Get(ctx, kclient.ObjectKey{
@@ -97,6 +97,7 @@ type AppUpdateOptions struct {
 	AutoUpgradeInterval string
 	Memory              v1.MemoryMap
 	ComputeClasses      v1.ComputeClassMap
+	PrePull             *bool
 }
 
 type LogOptions apiv1.LogOptions
@@ -122,6 +123,7 @@ type AppRunOptions struct {
 	AutoUpgradeInterval string
 	Memory              v1.MemoryMap
 	ComputeClasses      v1.ComputeClassMap
+	PrePull             *bool
 }
 
 func (a AppRunOptions) ToUpdate() AppUpdateOptions {
@@ -144,6 +146,7 @@ func (a AppRunOptions) ToUpdate() AppUpdateOptions {
 		AutoUpgradeInterval: a.AutoUpgradeInterval,
 		Memory:              a.Memory,
 		ComputeClasses:      a.ComputeClasses,
+		PrePull:             a.PrePull,
 	}
 }
 
@@ -167,6 +170,7 @@ func (a AppUpdateOptions) ToRun() AppRunOptions {
 		AutoUpgradeInterval: a.AutoUpgradeInterval,
 		Memory:              a.Memory,
 		ComputeClasses:      a.ComputeClasses,
+		PrePull:             a.PrePull,
 	}
 }
 
@@ -189,6 +193,8 @@ type Client interface {
 	AppGet(ctx context.Context, name string) (*apiv1.App, error)
 	AppStop(ctx context.Context, name string) error
 	AppStart(ctx context.Context, name string) error
+	AppSuspend(ctx context.Context, name string) error
+	AppResume(ctx context.Context, name string) error
 	AppRun(ctx context.Context, image string, opts *AppRunOptions) (*apiv1.App, error)
 	AppUpdate(ctx context.Context, name string, opts *AppUpdateOptions) (*apiv1.App, error)
 	AppLog(ctx context.Context, name string, opts *LogOptions) (<-chan apiv1.LogMessage, error)
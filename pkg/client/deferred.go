@@ -66,6 +66,20 @@ func (d *DeferredClient) AppStart(ctx context.Context, name string) error {
 	return d.Client.AppStart(ctx, name)
 }
 
+func (d *DeferredClient) AppSuspend(ctx context.Context, name string) error {
+	if err := d.create(); err != nil {
+		return err
+	}
+	return d.Client.AppSuspend(ctx, name)
+}
+
+func (d *DeferredClient) AppResume(ctx context.Context, name string) error {
+	if err := d.create(); err != nil {
+		return err
+	}
+	return d.Client.AppResume(ctx, name)
+}
+
 func (d *DeferredClient) AppRun(ctx context.Context, image string, opts *AppRunOptions) (*apiv1.App, error) {
 	if err := d.create(); err != nil {
 		return nil, err
@@ -105,6 +105,14 @@ func (c IgnoreUninstalled) AppStart(ctx context.Context, name string) error {
 	return c.Client.AppStart(ctx, name)
 }
 
+func (c IgnoreUninstalled) AppSuspend(ctx context.Context, name string) error {
+	return c.Client.AppSuspend(ctx, name)
+}
+
+func (c IgnoreUninstalled) AppResume(ctx context.Context, name string) error {
+	return c.Client.AppResume(ctx, name)
+}
+
 func (c IgnoreUninstalled) AppRun(ctx context.Context, image string, opts *AppRunOptions) (*apiv1.App, error) {
 	return promptInstall(ctx, func() (*apiv1.App, error) {
 		return c.Client.AppRun(ctx, image, opts)
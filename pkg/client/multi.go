@@ -151,6 +151,20 @@ func (m *MultiClient) AppStart(ctx context.Context, name string) error {
 	return err
 }
 
+func (m *MultiClient) AppSuspend(ctx context.Context, name string) error {
+	_, err := onOne(ctx, m.Factory, name, func(name string, c Client) (*apiv1.App, error) {
+		return &apiv1.App{}, c.AppSuspend(ctx, name)
+	})
+	return err
+}
+
+func (m *MultiClient) AppResume(ctx context.Context, name string) error {
+	_, err := onOne(ctx, m.Factory, name, func(name string, c Client) (*apiv1.App, error) {
+		return &apiv1.App{}, c.AppResume(ctx, name)
+	})
+	return err
+}
+
 func (m *MultiClient) AppRun(ctx context.Context, image string, opts *AppRunOptions) (*apiv1.App, error) {
 	name := ""
 	if opts != nil {
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/encryption/nacl"
+	"github.com/acorn-io/acorn/pkg/labels"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// SecretBundle holds the data of the secrets of an app, so that they can be backed up and restored
+type SecretBundle struct {
+	App     string          `json:"app"`
+	Secrets []BundledSecret `json:"secrets"`
+}
+
+// BundledSecret is a secret of an app in a SecretBundle. Name is the name of the secret in the app, not the name of
+// the secret that stores it.
+type BundledSecret struct {
+	Name string            `json:"name"`
+	Type string            `json:"type"`
+	Data map[string][]byte `json:"data"`
+}
+
+// ExportAppSecrets returns a bundle with the data of all the secrets of the app. An error is returned if a secret the
+// app generates is not found, rather than leaving it out of the bundle.
+func ExportAppSecrets(ctx context.Context, c Client, appName string) (*SecretBundle, error) {
+	app, err := c.AppGet(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := c.SecretList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &SecretBundle{
+		App:     appName,
+		Secrets: []BundledSecret{},
+	}
+	for _, secret := range secrets {
+		secretName := secret.Labels[labels.AcornSecretName]
+		if secret.Labels[labels.AcornAppName] != appName || secretName == "" {
+			continue
+		}
+		revealed, err := c.SecretReveal(ctx, secret.Name)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Secrets = append(bundle.Secrets, BundledSecret{
+			Name: secretName,
+			Type: revealed.Type,
+			Data: revealed.Data,
+		})
+	}
+
+	sort.Slice(bundle.Secrets, func(i, j int) bool {
+		return bundle.Secrets[i].Name < bundle.Secrets[j].Name
+	})
+
+	if missing := missingAppSecrets(app, bundle); len(missing) > 0 {
+		return nil, fmt.Errorf("secrets [%s] of app [%s] were not found, they may not be generated yet", strings.Join(missing, ", "), appName)
+	}
+	return bundle, nil
+}
+
+// missingAppSecrets returns the names of the secrets the app generates that are not in the bundle. Secrets bound to
+// an existing secret, not published or limited to profiles the app is not running with are not generated for the app.
+func missingAppSecrets(app *apiv1.App, bundle *SecretBundle) (result []string) {
+	exported := map[string]bool{}
+	for _, secret := range bundle.Secrets {
+		exported[secret.Name] = true
+	}
+	for _, binding := range app.Spec.Secrets {
+		exported[binding.Target] = true
+	}
+
+	profiles := app.Spec.GetProfiles()
+	for name, secret := range app.Status.AppSpec.Secrets {
+		if exported[name] || !secret.IsPublished() || !secret.AppliesToProfiles(profiles) {
+			continue
+		}
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Encrypt returns the bundle encrypted for the given public key
+func (b *SecretBundle) Encrypt(publicKey string) ([]byte, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	encData, err := nacl.Encrypt(string(data), publicKey)
+	if err != nil {
+		return nil, err
+	}
+	result, err := encData.Marshal()
+	return []byte(result), err
+}
+
+// DecryptSecretBundle returns the bundle in data, as encrypted by SecretBundle.Encrypt for the public key of the
+// given private key
+func DecryptSecretBundle(data []byte, privateKey string) (*SecretBundle, error) {
+	if !nacl.IsAcornEncryptedData(data) {
+		return nil, fmt.Errorf("not an encrypted secret bundle")
+	}
+	decrypted, err := nacl.DecryptWithPrivateKey(data, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &SecretBundle{}
+	if err := json.Unmarshal(decrypted, bundle); err != nil {
+		return nil, fmt.Errorf("invalid secret bundle: %w", err)
+	}
+	return bundle, nil
+}
+
+// ImportSecretBundle creates a secret for each secret of the bundle, named prefix followed by the name of the secret
+// in the app, or updates the data of the secret if it already exists. It returns the bindings that restore the
+// secrets when the app is run again, in the format of the --secret flag of acorn run.
+func ImportSecretBundle(ctx context.Context, c Client, bundle *SecretBundle, prefix string) (bindings []string, _ error) {
+	for _, secret := range bundle.Secrets {
+		name := prefix + secret.Name
+		_, err := c.SecretGet(ctx, name)
+		if apierrors.IsNotFound(err) {
+			_, err = c.SecretCreate(ctx, name, secret.Type, secret.Data)
+		} else if err == nil {
+			_, err = c.SecretUpdate(ctx, name, secret.Data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("importing secret [%s]: %w", secret.Name, err)
+		}
+		bindings = append(bindings, name+":"+secret.Name)
+	}
+	return bindings, nil
+}
@@ -0,0 +1,140 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/encryption/nacl"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func appSecret(name, app, secretName string) apiv1.Secret {
+	return apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				labels.AcornAppName:    app,
+				labels.AcornSecretName: secretName,
+			},
+		},
+	}
+}
+
+func TestSecretBundleRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	source := mocks.NewMockClient(ctrl)
+	source.EXPECT().AppGet(gomock.Any(), "app").Return(&apiv1.App{}, nil)
+	source.EXPECT().SecretList(gomock.Any()).Return([]apiv1.Secret{
+		appSecret("db-abcde", "app", "db"),
+		appSecret("token-fghij", "app", "token"),
+		appSecret("other-klmno", "other", "db"),
+		{ObjectMeta: metav1.ObjectMeta{Name: "user-secret"}},
+	}, nil)
+	source.EXPECT().SecretReveal(gomock.Any(), "db-abcde").Return(&apiv1.Secret{
+		Type: "basic",
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("secret"),
+		},
+	}, nil)
+	source.EXPECT().SecretReveal(gomock.Any(), "token-fghij").Return(&apiv1.Secret{
+		Type: "token",
+		Data: map[string][]byte{
+			"token": []byte("abc123"),
+		},
+	}, nil)
+
+	bundle, err := client.ExportAppSecrets(ctx, source, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, privateKey, err := nacl.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := bundle.Encrypt(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, string(data), "abc123")
+
+	// the bundle can't be decrypted with another key
+	_, otherPrivateKey, err := nacl.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.DecryptSecretBundle(data, otherPrivateKey)
+	assert.Error(t, err)
+
+	decrypted, err := client.DecryptSecretBundle(data, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, bundle, decrypted)
+
+	target := mocks.NewMockClient(ctrl)
+	target.EXPECT().SecretGet(gomock.Any(), "app-db").Return(nil,
+		apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "app-db"))
+	target.EXPECT().SecretCreate(gomock.Any(), "app-db", "basic", map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("secret"),
+	}).Return(&apiv1.Secret{}, nil)
+	target.EXPECT().SecretGet(gomock.Any(), "app-token").Return(&apiv1.Secret{}, nil)
+	target.EXPECT().SecretUpdate(gomock.Any(), "app-token", map[string][]byte{
+		"token": []byte("abc123"),
+	}).Return(&apiv1.Secret{}, nil)
+
+	bindings, err := client.ImportSecretBundle(ctx, target, decrypted, "app-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"app-db:db", "app-token:token"}, bindings)
+}
+
+func TestExportAppSecretsMissing(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	unpublished := false
+	c := mocks.NewMockClient(ctrl)
+	c.EXPECT().AppGet(gomock.Any(), "app").Return(&apiv1.App{
+		Spec: v1.AppInstanceSpec{
+			Secrets: []v1.SecretBinding{{Secret: "existing", Target: "bound"}},
+		},
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"db":       {Type: "basic"},
+					"token":    {Type: "token"},
+					"bound":    {Type: "basic"},
+					"internal": {Type: "token", Publish: &unpublished},
+					"prod":     {Type: "token", Profiles: []string{"prod"}},
+				},
+			},
+		},
+	}, nil)
+	c.EXPECT().SecretList(gomock.Any()).Return([]apiv1.Secret{
+		appSecret("db-abcde", "app", "db"),
+	}, nil)
+	c.EXPECT().SecretReveal(gomock.Any(), "db-abcde").Return(&apiv1.Secret{
+		Type: "basic",
+		Data: map[string][]byte{
+			"password": []byte("secret"),
+		},
+	}, nil)
+
+	_, err := client.ExportAppSecrets(ctx, c, "app")
+	assert.EqualError(t, err, "secrets [token] of app [app] were not found, they may not be generated yet")
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"sort"
 	"strings"
@@ -98,3 +99,52 @@ func (c *DefaultClient) SecretDelete(ctx context.Context, serverAddress string)
 	}
 	return secret, err
 }
+
+// SecretDiff is the comparison of the keys of two secrets
+type SecretDiff struct {
+	OnlyA  []string
+	OnlyB  []string
+	Common []string
+	// Changed are the common keys that have different values. It is only set if the data of both secrets was compared.
+	Changed []string
+}
+
+// DiffSecrets compares the keys of secrets a and b. The values of the common keys are only compared if both secrets
+// include their data, as returned by SecretReveal.
+func DiffSecrets(a, b *apiv1.Secret) (result SecretDiff) {
+	aKeys, bKeys := secretKeys(a), secretKeys(b)
+	compareValues := a.Data != nil && b.Data != nil
+
+	for key := range aKeys {
+		if _, ok := bKeys[key]; !ok {
+			result.OnlyA = append(result.OnlyA, key)
+			continue
+		}
+		result.Common = append(result.Common, key)
+		if compareValues && !bytes.Equal(a.Data[key], b.Data[key]) {
+			result.Changed = append(result.Changed, key)
+		}
+	}
+	for key := range bKeys {
+		if _, ok := aKeys[key]; !ok {
+			result.OnlyB = append(result.OnlyB, key)
+		}
+	}
+
+	sort.Strings(result.OnlyA)
+	sort.Strings(result.OnlyB)
+	sort.Strings(result.Common)
+	sort.Strings(result.Changed)
+	return
+}
+
+func secretKeys(secret *apiv1.Secret) map[string]struct{} {
+	keys := map[string]struct{}{}
+	for _, key := range secret.Keys {
+		keys[key] = struct{}{}
+	}
+	for key := range secret.Data {
+		keys[key] = struct{}{}
+	}
+	return keys
+}
@@ -0,0 +1,49 @@
+package client_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSecrets(t *testing.T) {
+	a := &apiv1.Secret{Keys: []string{"password", "username", "host"}}
+	b := &apiv1.Secret{Keys: []string{"username", "port", "password"}}
+
+	assert.Equal(t, client.SecretDiff{
+		OnlyA:  []string{"host"},
+		OnlyB:  []string{"port"},
+		Common: []string{"password", "username"},
+	}, client.DiffSecrets(a, b))
+
+	assert.Equal(t, client.SecretDiff{
+		Common: []string{"host", "password", "username"},
+	}, client.DiffSecrets(a, a))
+}
+
+func TestDiffSecretsValues(t *testing.T) {
+	a := &apiv1.Secret{Data: map[string][]byte{
+		"password": []byte("one"),
+		"username": []byte("admin"),
+	}}
+	b := &apiv1.Secret{Data: map[string][]byte{
+		"password": []byte("two"),
+		"username": []byte("admin"),
+		"port":     []byte("5432"),
+	}}
+
+	assert.Equal(t, client.SecretDiff{
+		OnlyB:   []string{"port"},
+		Common:  []string{"password", "username"},
+		Changed: []string{"password"},
+	}, client.DiffSecrets(a, b))
+
+	// values are not compared unless both secrets include their data
+	b.Data = nil
+	b.Keys = []string{"password", "username"}
+	assert.Equal(t, client.SecretDiff{
+		Common: []string{"password", "username"},
+	}, client.DiffSecrets(a, b))
+}
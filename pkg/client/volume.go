@@ -75,3 +75,58 @@ func (c *DefaultClient) VolumeClassGet(ctx context.Context, name string) (*apiv1
 		Name:      name,
 	}, storage)
 }
+
+// VolumeConsumer is an app that uses a volume
+type VolumeConsumer struct {
+	App string
+	// Target is the name of the volume in the app
+	Target string
+	// Bound is set if the app uses the volume through a volume binding rather than the volume being created for it
+	Bound bool
+}
+
+// VolumeConsumers returns the apps that use the volume: the app the volume was created for and the apps that bind the
+// volume, by its name or by its <app>.<volume> alias.
+func VolumeConsumers(ctx context.Context, c Client, name string) (result []VolumeConsumer, _ error) {
+	volume, err := c.VolumeGet(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{
+		volume.Name: true,
+	}
+	if volume.Status.AppPublicName != "" && volume.Status.VolumeName != "" {
+		names[volume.Status.AppPublicName+"."+volume.Status.VolumeName] = true
+		result = append(result, VolumeConsumer{
+			App:    volume.Status.AppPublicName,
+			Target: volume.Status.VolumeName,
+		})
+	}
+
+	apps, err := c.AppList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range apps {
+		for _, binding := range app.Spec.Volumes {
+			if binding.Volume == "" || !names[binding.Volume] {
+				continue
+			}
+			consumer := VolumeConsumer{
+				App:    app.Name,
+				Target: binding.Target,
+				Bound:  true,
+			}
+			if len(result) > 0 && result[0].App == consumer.App && result[0].Target == consumer.Target {
+				// the app the volume was created for also binds it
+				result[0].Bound = true
+				continue
+			}
+			result = append(result, consumer)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVolumeConsumers(t *testing.T) {
+	c := mocks.NewMockClient(gomock.NewController(t))
+	c.EXPECT().VolumeGet(gomock.Any(), "db.data").Return(&apiv1.Volume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-1234"},
+		Status:     apiv1.VolumeStatus{AppPublicName: "db", VolumeName: "data"},
+	}, nil)
+	c.EXPECT().AppList(gomock.Any()).Return([]apiv1.App{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "db"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+			Spec: v1.AppInstanceSpec{Volumes: []v1.VolumeBinding{
+				{Volume: "db.data", Target: "source"},
+				{Volume: "other.data", Target: "other"},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "report"},
+			Spec: v1.AppInstanceSpec{Volumes: []v1.VolumeBinding{
+				{Volume: "pvc-1234", Target: "input"},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrelated"},
+			Spec: v1.AppInstanceSpec{Volumes: []v1.VolumeBinding{
+				{Target: "data", Class: "fast"},
+			}},
+		},
+	}, nil)
+
+	consumers, err := client.VolumeConsumers(context.Background(), c, "db.data")
+	require.NoError(t, err)
+	assert.Equal(t, []client.VolumeConsumer{
+		{App: "db", Target: "data"},
+		{App: "backup", Target: "source", Bound: true},
+		{App: "report", Target: "input", Bound: true},
+	}, consumers)
+}
@@ -31,6 +31,9 @@ var (
 
 	// Default HttpEndpointPattern set to enable Let's Encrypt
 	DefaultHttpEndpointPattern = "{{hashConcat 8 .Container .App .Namespace | truncate}}.{{.ClusterDomain}}"
+
+	// GenerationJobBackoffLimitDefault is the default number of retries of a job that generates a secret
+	GenerationJobBackoffLimitDefault = 3
 )
 
 func complete(ctx context.Context, c *apiv1.Config, getter kclient.Reader) error {
@@ -101,6 +104,33 @@ func complete(ctx context.Context, c *apiv1.Config, getter kclient.Reader) error
 	if c.AWSIdentityProviderARN == nil {
 		c.AWSIdentityProviderARN = new(string)
 	}
+	if c.VolumeSizeDefault == nil {
+		c.VolumeSizeDefault = new(string)
+	}
+	if c.PullThroughCache == nil {
+		c.PullThroughCache = new(string)
+	}
+	if c.BackingSecretNamespace == nil {
+		c.BackingSecretNamespace = new(string)
+	}
+	if c.SecretWebhookURL == nil {
+		c.SecretWebhookURL = new(string)
+	}
+	if c.GenerationJobBackoffLimit == nil {
+		c.GenerationJobBackoffLimit = &[]int{GenerationJobBackoffLimitDefault}[0]
+	}
+	if c.GenerationJobTTLSeconds == nil {
+		c.GenerationJobTTLSeconds = new(int)
+	}
+	if c.ReadOnlyRootFilesystem == nil {
+		c.ReadOnlyRootFilesystem = new(bool)
+	}
+	if c.AllowTrafficFromNamespaceLabel == nil {
+		c.AllowTrafficFromNamespaceLabel = new(string)
+	}
+	if c.AppResourceQuota == nil {
+		c.AppResourceQuota = new(bool)
+	}
 
 	return nil
 }
@@ -351,6 +381,66 @@ func merge(oldConfig, newConfig *apiv1.Config) *apiv1.Config {
 		mergedConfig.AWSIdentityProviderARN = newConfig.AWSIdentityProviderARN
 	}
 
+	if newConfig.VolumeSizeDefault != nil {
+		mergedConfig.VolumeSizeDefault = newConfig.VolumeSizeDefault
+	}
+
+	if newConfig.PullThroughCache != nil {
+		mergedConfig.PullThroughCache = newConfig.PullThroughCache
+	}
+
+	if newConfig.BackingSecretNamespace != nil {
+		mergedConfig.BackingSecretNamespace = newConfig.BackingSecretNamespace
+	}
+
+	if newConfig.SecretWebhookURL != nil {
+		mergedConfig.SecretWebhookURL = newConfig.SecretWebhookURL
+	}
+
+	if newConfig.GenerationJobBackoffLimit != nil {
+		mergedConfig.GenerationJobBackoffLimit = newConfig.GenerationJobBackoffLimit
+	}
+
+	if newConfig.GenerationJobTTLSeconds != nil {
+		mergedConfig.GenerationJobTTLSeconds = newConfig.GenerationJobTTLSeconds
+	}
+
+	if len(newConfig.DisallowedSecretTypes) > 0 && newConfig.DisallowedSecretTypes[0] == "" {
+		mergedConfig.DisallowedSecretTypes = nil
+	} else if len(newConfig.DisallowedSecretTypes) > 0 {
+		mergedConfig.DisallowedSecretTypes = newConfig.DisallowedSecretTypes
+	}
+
+	if len(newConfig.IngressClassControllerNamespaces) > 0 && newConfig.IngressClassControllerNamespaces[0] == "" {
+		mergedConfig.IngressClassControllerNamespaces = nil
+	} else if len(newConfig.IngressClassControllerNamespaces) > 0 {
+		mergedConfig.IngressClassControllerNamespaces = newConfig.IngressClassControllerNamespaces
+	}
+
+	if newConfig.ReadOnlyRootFilesystem != nil {
+		mergedConfig.ReadOnlyRootFilesystem = newConfig.ReadOnlyRootFilesystem
+	}
+
+	if newConfig.AllowTrafficFromNamespaceLabel != nil {
+		mergedConfig.AllowTrafficFromNamespaceLabel = newConfig.AllowTrafficFromNamespaceLabel
+	}
+
+	if len(newConfig.SecretCompatLabels) > 0 && newConfig.SecretCompatLabels[0] == "" {
+		mergedConfig.SecretCompatLabels = nil
+	} else if len(newConfig.SecretCompatLabels) > 0 {
+		mergedConfig.SecretCompatLabels = newConfig.SecretCompatLabels
+	}
+
+	if newConfig.AppResourceQuota != nil {
+		mergedConfig.AppResourceQuota = newConfig.AppResourceQuota
+	}
+
+	if len(newConfig.RegistryMirrors) > 0 && newConfig.RegistryMirrors[0] == "" {
+		mergedConfig.RegistryMirrors = nil
+	} else if len(newConfig.RegistryMirrors) > 0 {
+		mergedConfig.RegistryMirrors = newConfig.RegistryMirrors
+	}
+
 	return &mergedConfig
 }
 
@@ -471,3 +561,34 @@ func Get(ctx context.Context, getter kclient.Reader) (*apiv1.Config, error) {
 	err = complete(ctx, cfg, getter)
 	return cfg, err
 }
+
+// SecretTypeDisallowed returns true if apps in the given project are not allowed to declare secrets of the given type.
+func SecretTypeDisallowed(cfg *apiv1.Config, project, secretType string) bool {
+	for _, entry := range cfg.DisallowedSecretTypes {
+		if entryProject, entryType, found := strings.Cut(entry, "="); found && entryProject == project && entryType == secretType {
+			return true
+		}
+	}
+	return false
+}
+
+// RegistryMirror returns the registry mirror configured for the given region, or an empty string if there is none.
+func RegistryMirror(cfg *apiv1.Config, region string) string {
+	for _, mirror := range cfg.RegistryMirrors {
+		if mirrorRegion, registry, found := strings.Cut(mirror, "="); found && mirrorRegion == region {
+			return registry
+		}
+	}
+	return ""
+}
+
+// IngressControllerNamespace returns the namespace where the controller of the given ingress class runs, which is the
+// configured ingress controller namespace unless one is configured for the class.
+func IngressControllerNamespace(cfg *apiv1.Config, ingressClassName string) string {
+	for _, entry := range cfg.IngressClassControllerNamespaces {
+		if class, namespace, found := strings.Cut(entry, "="); found && class == ingressClassName {
+			return namespace
+		}
+	}
+	return *cfg.IngressControllerNamespace
+}
@@ -102,6 +102,7 @@ func toAcorn(appInstance *v1.AppInstance, tag name.Reference, pullSecrets *PullS
 			DeployArgs:  acorn.DeployArgs,
 			Publish:     acorn.Publish,
 			Stop:        appInstance.Spec.Stop,
+			Suspend:     appInstance.Spec.Suspend,
 			Environment: append(acorn.Environment, appInstance.Spec.Environment...),
 			Permissions: trimPermPrefix(appInstance.Spec.Permissions, acornName),
 		},
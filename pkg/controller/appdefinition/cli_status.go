@@ -90,6 +90,9 @@ func uptodate(app *v1.AppInstance) string {
 }
 
 func healthy(app *v1.AppInstance) string {
+	if app.Status.Suspended {
+		return "suspended"
+	}
 	if app.Status.Stopped {
 		return "stopped"
 	}
@@ -7,6 +7,7 @@ import (
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/secrets"
 	"github.com/acorn-io/baaah/pkg/apply"
 	"github.com/acorn-io/baaah/pkg/router"
 	appsv1 "k8s.io/api/apps/v1"
@@ -79,6 +80,10 @@ func (d *depCheckingResponse) isJobReady(jobName string) (ready bool, found bool
 	var jobDep batchv1.Job
 	err := d.req.Get(&jobDep, d.app.Status.Namespace, jobName)
 	if apierrors.IsNotFound(err) {
+		// A job that generates secrets is ready if it was deleted after it finished and generated them
+		if finished, err := secrets.GenerationJobFinished(d.req, d.app, jobName); err == nil && finished {
+			return true, true
+		}
 		return false, false
 	}
 	if err != nil {
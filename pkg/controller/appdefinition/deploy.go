@@ -83,7 +83,12 @@ func DeploySpec(req router.Request, resp router.Response) (err error) {
 		return err
 	}
 
-	pullSecrets, err := NewPullSecrets(req, appInstance)
+	cfg, err := config.Get(req.Ctx, req.Client)
+	if err != nil {
+		return err
+	}
+
+	pullSecrets, err := NewPullSecrets(req, cfg, appInstance)
 	if err != nil {
 		return err
 	}
@@ -104,6 +109,10 @@ func DeploySpec(req router.Request, resp router.Response) (err error) {
 		return err
 	}
 	addAcorns(appInstance, tag, pullSecrets, resp)
+	if err := addPrePull(req, appInstance, tag, pullSecrets, resp); err != nil {
+		return err
+	}
+	addResourceQuota(cfg, appInstance, resp)
 
 	resp.Objects(pullSecrets.Objects()...)
 	resp.Objects(interpolator.Objects()...)
@@ -265,8 +274,12 @@ func toMounts(app *v1.AppInstance, container v1.Container, interpolation *secret
 				SubPath:   mount.SubPath,
 			})
 		} else {
+			suffix := ""
+			if volume.NormalizeMode(mount.Secret.Mode) != "" {
+				suffix = "-" + mount.Secret.Mode
+			}
 			result = append(result, corev1.VolumeMount{
-				Name:      secretPodVolName(mount.Secret.Name),
+				Name:      secretPodVolName(mount.Secret.Name + suffix),
 				MountPath: path.Join("/", mountPath),
 			})
 		}
@@ -394,15 +407,44 @@ func toProbe(container v1.Container, probeType v1.ProbeType) *corev1.Probe {
 	return nil
 }
 
+func toLifecycle(container v1.Container) *corev1.Lifecycle {
+	if container.PreStop == nil {
+		return nil
+	}
+	handler := toProbeHandler(v1.Probe{
+		Exec: container.PreStop.Exec,
+		HTTP: container.PreStop.HTTP,
+	})
+	if handler.Exec == nil && handler.HTTPGet == nil {
+		return nil
+	}
+	return &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec:    handler.Exec,
+			HTTPGet: handler.HTTPGet,
+		},
+	}
+}
+
+// terminationGracePeriod returns the grace period of the container's pod, which defaults to 5 seconds
+func terminationGracePeriod(container v1.Container) *int64 {
+	if container.TerminationGracePeriodSeconds != nil {
+		return container.TerminationGracePeriodSeconds
+	}
+	return &[]int64{5}[0]
+}
+
 func toContainer(app *v1.AppInstance, tag name.Reference, containerName string, container v1.Container, interpolator *secrets.Interpolator) corev1.Container {
+	args, argsEnv := interpolator.ToArgs(container.Command)
+	env := app.Status.AppSpec.ContainerEnvironment(container)
 	containerObject := corev1.Container{
 		Name:           containerName,
 		Image:          images.ResolveTag(tag, container.Image),
 		Command:        container.Entrypoint,
-		Args:           container.Command,
+		Args:           args,
 		WorkingDir:     container.WorkingDir,
-		Env:            toEnv(container.Environment, app.Spec.Environment, interpolator),
-		EnvFrom:        toEnvFrom(container.Environment),
+		Env:            append(toEnv(env, app.Spec.Environment, interpolator), argsEnv...),
+		EnvFrom:        toEnvFrom(env),
 		TTY:            container.Interactive,
 		Stdin:          container.Interactive,
 		Ports:          toPorts(container),
@@ -410,6 +452,7 @@ func toContainer(app *v1.AppInstance, tag name.Reference, containerName string,
 		LivenessProbe:  toProbe(container, v1.LivenessProbeType),
 		StartupProbe:   toProbe(container, v1.StartupProbeType),
 		ReadinessProbe: toProbe(container, v1.ReadinessProbeType),
+		Lifecycle:      toLifecycle(container),
 		Resources:      app.Status.Scheduling[containerName].Requirements,
 	}
 
@@ -506,7 +549,8 @@ func isStateful(appInstance *v1.AppInstance, container v1.Container) bool {
 				continue
 			}
 			if dir.Volume == volName {
-				if len(vol.AccessModes) == 0 || (len(vol.AccessModes) == 1 && vol.AccessModes[0] == v1.AccessModeReadWriteOnce) {
+				if accessModes := translateAccessModes(vol.AccessModes); len(accessModes) == 1 &&
+					(accessModes[0] == corev1.ReadWriteOnce || accessModes[0] == corev1.ReadWriteOncePod) {
 					return true
 				}
 			}
@@ -537,7 +581,7 @@ func getSecretAnnotations(req router.Request, appInstance *v1.AppInstance, conta
 		result  = map[string]string{}
 	)
 
-	for _, env := range container.Environment {
+	for _, env := range appInstance.Status.AppSpec.ContainerEnvironment(container) {
 		if env.Secret.OnChange == v1.ChangeTypeRedeploy {
 			secrets = append(secrets, env.Secret.Name)
 		}
@@ -584,7 +628,7 @@ func toDeployment(req router.Request, appInstance *v1.AppInstance, tag name.Refe
 		return nil, err
 	}
 
-	volumes, err := toVolumes(appInstance, container, interpolator)
+	volumes, err := toVolumes(req, appInstance, container, interpolator)
 	if err != nil {
 		return nil, err
 	}
@@ -615,7 +659,7 @@ func toDeployment(req router.Request, appInstance *v1.AppInstance, tag name.Refe
 				Spec: corev1.PodSpec{
 					Affinity:                      appInstance.Status.Scheduling[name].Affinity,
 					Tolerations:                   appInstance.Status.Scheduling[name].Tolerations,
-					TerminationGracePeriodSeconds: &[]int64{5}[0],
+					TerminationGracePeriodSeconds: terminationGracePeriod(container),
 					ImagePullSecrets:              pullSecrets.ForContainer(name, append(containers, initContainers...)),
 					EnableServiceLinks:            new(bool),
 					Containers:                    containers,
@@ -627,6 +671,22 @@ func toDeployment(req router.Request, appInstance *v1.AppInstance, tag name.Refe
 		},
 	}
 
+	if err := applyPodSecurity(req, &dep.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+
+	if err := applyReadOnlyRootFilesystem(req, name, container, &dep.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+
+	if err := applyDNS(appInstance.Status.AppSpec, &dep.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+
+	if err := applyNodeSelection(appInstance.Status.AppSpec, &dep.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+
 	interpolator.AddMissingAnnotations(dep.Annotations)
 
 	if stateful {
@@ -637,7 +697,7 @@ func toDeployment(req router.Request, appInstance *v1.AppInstance, tag name.Refe
 		dep.Spec.Template.Spec.Hostname = dep.Name
 	}
 
-	if appInstance.Spec.Stop != nil && *appInstance.Spec.Stop {
+	if appInstance.Spec.GetStopped() {
 		dep.Spec.Replicas = new(int32)
 	}
 
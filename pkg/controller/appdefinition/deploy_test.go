@@ -9,6 +9,7 @@ import (
 	"github.com/acorn-io/acorn/pkg/digest"
 	"github.com/acorn-io/acorn/pkg/scheme"
 	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/acorn/pkg/system"
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/router/tester"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -16,6 +17,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -32,6 +34,10 @@ func TestGlobalEnv(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/globalenv", DeploySpec)
 }
 
+func TestAppEnv(t *testing.T) {
+	tester.DefaultTest(t, scheme.Scheme, "testdata/appenv", DeploySpec)
+}
+
 func TestDeploySpec(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/deployspec/basic", DeploySpec)
 }
@@ -136,6 +142,137 @@ func TestEnvironment(t *testing.T) {
 	}, dep.Spec.Template.Spec.Containers[0].Env)
 }
 
+func TestDNS(t *testing.T) {
+	dep := ToDeploymentsTest(t, &v1.AppInstance{
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				DNSPolicy: "None",
+				DNSConfig: &v1.DNSConfig{
+					Nameservers: []string{"10.0.0.10", "fd00::10"},
+					Searches:    []string{"discovery.internal"},
+					Options: []v1.DNSOption{
+						{Name: "ndots", Value: "2"},
+						{Name: "edns0"},
+					},
+				},
+				Containers: map[string]v1.Container{
+					"test": {},
+				},
+			},
+		},
+	}, testTag, nil)[1].(*appsv1.Deployment)
+	assert.Equal(t, corev1.DNSNone, dep.Spec.Template.Spec.DNSPolicy)
+	assert.Equal(t, &corev1.PodDNSConfig{
+		Nameservers: []string{"10.0.0.10", "fd00::10"},
+		Searches:    []string{"discovery.internal"},
+		Options: []corev1.PodDNSConfigOption{
+			{Name: "ndots", Value: &[]string{"2"}[0]},
+			{Name: "edns0"},
+		},
+	}, dep.Spec.Template.Spec.DNSConfig)
+}
+
+func TestNodeSelection(t *testing.T) {
+	workloadToleration := corev1.Toleration{
+		Key:      "taints.acorn.io/workload",
+		Operator: corev1.TolerationOpExists,
+	}
+	gpuToleration := corev1.Toleration{
+		Key:      "nvidia.com/gpu",
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
+	dep := ToDeploymentsTest(t, &v1.AppInstance{
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				NodeSelector: map[string]string{
+					"node.kubernetes.io/instance-type": "g4dn.xlarge",
+				},
+				Tolerations: []corev1.Toleration{gpuToleration, workloadToleration},
+				Containers: map[string]v1.Container{
+					"test": {},
+				},
+			},
+			Scheduling: map[string]v1.Scheduling{
+				"test": {
+					Tolerations: []corev1.Toleration{workloadToleration},
+				},
+			},
+		},
+	}, testTag, nil)[1].(*appsv1.Deployment)
+	assert.Equal(t, map[string]string{
+		"node.kubernetes.io/instance-type": "g4dn.xlarge",
+	}, dep.Spec.Template.Spec.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{workloadToleration, gpuToleration}, dep.Spec.Template.Spec.Tolerations)
+}
+
+func TestNodeSelectionInvalidTolerations(t *testing.T) {
+	for _, toleration := range []corev1.Toleration{
+		{Key: "a", Operator: "Matches"},
+		{Operator: corev1.TolerationOpEqual, Value: "b"},
+		{Key: "a", Operator: corev1.TolerationOpExists, Value: "b"},
+		{Key: "a", Operator: corev1.TolerationOpExists, Effect: "NoRun"},
+		{Key: "a", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule, TolerationSeconds: &[]int64{10}[0]},
+	} {
+		assert.Error(t, applyNodeSelection(v1.AppSpec{Tolerations: []corev1.Toleration{toleration}}, &corev1.PodSpec{}))
+	}
+}
+
+func TestPreStop(t *testing.T) {
+	dep := ToDeploymentsTest(t, &v1.AppInstance{
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				Containers: map[string]v1.Container{
+					"test": {
+						TerminationGracePeriodSeconds: &[]int64{60}[0],
+						PreStop: &v1.Hook{
+							HTTP: &v1.HTTPProbe{
+								URL: "http://localhost:8080/drain",
+							},
+						},
+						Sidecars: map[string]v1.Container{
+							"side": {
+								PreStop: &v1.Hook{
+									Exec: &v1.ExecProbe{
+										Command: []string{"sleep", "10"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, testTag, nil)[1].(*appsv1.Deployment)
+	assert.Equal(t, int64(60), *dep.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	assert.Equal(t, &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/drain",
+				Port: intstr.FromInt(8080),
+			},
+		},
+	}, dep.Spec.Template.Spec.Containers[0].Lifecycle)
+	assert.Equal(t, &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sleep", "10"},
+			},
+		},
+	}, dep.Spec.Template.Spec.Containers[1].Lifecycle)
+}
+
+func TestDNSInvalid(t *testing.T) {
+	for _, appSpec := range []v1.AppSpec{
+		{DNSPolicy: "Custom"},
+		{DNSPolicy: "None"},
+		{DNSConfig: &v1.DNSConfig{Nameservers: []string{"dns.internal"}}},
+		{DNSConfig: &v1.DNSConfig{Options: []v1.DNSOption{{Value: "2"}}}},
+	} {
+		assert.Error(t, applyDNS(appSpec, &corev1.PodSpec{}))
+	}
+}
+
 func TestWorkdir(t *testing.T) {
 	dep := ToDeploymentsTest(t, &v1.AppInstance{
 		Status: v1.AppInstanceStatus{
@@ -310,3 +447,265 @@ func TestFiles(t *testing.T) {
 	assert.Equal(t, []byte("d"), configMap.Data[toHash("ZA==")])
 	assert.Equal(t, []byte("e"), configMap.Data[toHash("ZQ==")])
 }
+
+func TestRestrictedPodSecurity(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      system.ConfigName,
+					Namespace: system.Namespace,
+				},
+				Data: map[string]string{
+					"config": `{"podSecurityEnforceProfile": "restricted"}`,
+				},
+			},
+		},
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "image",
+			},
+			AppSpec: v1.AppSpec{
+				Containers: map[string]v1.Container{
+					"web": {
+						Image: "web",
+						Sidecars: map[string]v1.Container{
+							"init": {
+								Image: "init",
+								Init:  true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dep *appsv1.Deployment
+	for _, obj := range resp.Collected {
+		if d, ok := obj.(*appsv1.Deployment); ok && d.Name == "web" {
+			dep = d
+		}
+	}
+	if !assert.NotNil(t, dep) {
+		return
+	}
+
+	podSpec := dep.Spec.Template.Spec
+	assert.True(t, *podSpec.SecurityContext.RunAsNonRoot)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, podSpec.SecurityContext.SeccompProfile.Type)
+	for _, container := range append(podSpec.Containers, podSpec.InitContainers...) {
+		assert.False(t, *container.SecurityContext.AllowPrivilegeEscalation, container.Name)
+		assert.Equal(t, []corev1.Capability{"ALL"}, container.SecurityContext.Capabilities.Drop, container.Name)
+	}
+}
+
+func TestReadOnlyRootFilesystem(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "image",
+			},
+			AppSpec: v1.AppSpec{
+				Containers: map[string]v1.Container{
+					"web": {
+						Image:                  "web",
+						ReadOnlyRootFilesystem: true,
+						WritablePaths:          []string{"/tmp", "var/cache", "/var/run"},
+						Dirs: map[string]v1.VolumeMount{
+							"/var/run": {
+								Volume: "run",
+							},
+						},
+						Sidecars: map[string]v1.Container{
+							"helper": {
+								Image: "helper",
+							},
+						},
+					},
+				},
+			},
+		},
+	}, DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dep *appsv1.Deployment
+	for _, obj := range resp.Collected {
+		if d, ok := obj.(*appsv1.Deployment); ok && d.Name == "web" {
+			dep = d
+		}
+	}
+	if !assert.NotNil(t, dep) {
+		return
+	}
+
+	podSpec := dep.Spec.Template.Spec
+	var web, helper corev1.Container
+	for _, container := range podSpec.Containers {
+		switch container.Name {
+		case "web":
+			web = container
+		case "helper":
+			helper = container
+		}
+	}
+
+	// the writable paths get scratch volumes, except /var/run that already has a volume
+	assert.True(t, *web.SecurityContext.ReadOnlyRootFilesystem)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "run", MountPath: "/var/run"},
+		{Name: "scratch-" + pathHash("web", "/tmp"), MountPath: "/tmp"},
+		{Name: "scratch-" + pathHash("web", "/var/cache"), MountPath: "/var/cache"},
+	}, web.VolumeMounts)
+	for _, writablePath := range []string{"/tmp", "/var/cache"} {
+		assert.Contains(t, podSpec.Volumes, corev1.Volume{
+			Name: "scratch-" + pathHash("web", writablePath),
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	// the sidecar didn't ask for a read-only root filesystem
+	assert.Nil(t, helper.SecurityContext)
+	assert.Empty(t, helper.VolumeMounts)
+}
+
+func TestReadOnlyRootFilesystemConfig(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      system.ConfigName,
+					Namespace: system.Namespace,
+				},
+				Data: map[string]string{
+					"config": `{"readOnlyRootFilesystem": true}`,
+				},
+			},
+		},
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "image",
+			},
+			AppSpec: v1.AppSpec{
+				Containers: map[string]v1.Container{
+					"web": {
+						Image: "web",
+					},
+				},
+			},
+		},
+	}, DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dep *appsv1.Deployment
+	for _, obj := range resp.Collected {
+		if d, ok := obj.(*appsv1.Deployment); ok && d.Name == "web" {
+			dep = d
+		}
+	}
+	if !assert.NotNil(t, dep) {
+		return
+	}
+
+	// without writable paths, /tmp is writable
+	web := dep.Spec.Template.Spec.Containers[0]
+	assert.True(t, *web.SecurityContext.ReadOnlyRootFilesystem)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "scratch-" + pathHash("web", "/tmp"), MountPath: "/tmp"},
+	}, web.VolumeMounts)
+}
+
+// TestDeploySpecParallel reconciles several apps at once, as the workers of the router do, to catch state shared
+// between reconciles when run with -race
+func TestDeploySpecParallel(t *testing.T) {
+	for _, dir := range []string{
+		"testdata/deployspec/basic",
+		"testdata/deployspec/scale",
+		"testdata/files",
+		"testdata/globalenv",
+		"testdata/template",
+	} {
+		dir := dir
+		t.Run(dir, func(t *testing.T) {
+			t.Parallel()
+			for i := 0; i < 10; i++ {
+				harness, input, err := tester.FromDir(scheme.Scheme, dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := harness.Invoke(t, input, router.HandlerFunc(DeploySpec)); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSuspendScalesToZeroAndKeepsVolumes(t *testing.T) {
+	deploy := func(suspend bool) (replicas map[string]int32, claims []string) {
+		harness, input, err := tester.FromDir(scheme.Scheme, "testdata/volumes/named")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Only the deployments and claims are compared below
+		harness.ExpectedOutput = nil
+		input.(*v1.AppInstance).Spec.Suspend = &suspend
+
+		resp, err := harness.Invoke(t, input, router.HandlerFunc(DeploySpec))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		replicas = map[string]int32{}
+		for _, obj := range resp.Collected {
+			switch obj := obj.(type) {
+			case *appsv1.Deployment:
+				replicas[obj.Name] = *obj.Spec.Replicas
+			case *corev1.PersistentVolumeClaim:
+				claims = append(claims, obj.Name)
+			}
+		}
+		return replicas, claims
+	}
+
+	suspendedReplicas, suspendedClaims := deploy(true)
+	assert.Equal(t, map[string]int32{"container-name": 0}, suspendedReplicas)
+	assert.Equal(t, []string{"foo"}, suspendedClaims)
+
+	resumedReplicas, resumedClaims := deploy(false)
+	assert.Equal(t, map[string]int32{"container-name": 1}, resumedReplicas)
+	assert.Equal(t, suspendedClaims, resumedClaims)
+}
@@ -0,0 +1,60 @@
+package appdefinition
+
+import (
+	"fmt"
+	"net"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var dnsPolicies = map[corev1.DNSPolicy]bool{
+	corev1.DNSClusterFirst:            true,
+	corev1.DNSClusterFirstWithHostNet: true,
+	corev1.DNSDefault:                 true,
+	corev1.DNSNone:                    true,
+}
+
+// applyDNS sets the DNS policy and configuration of the app on the pod spec
+func applyDNS(appSpec v1.AppSpec, podSpec *corev1.PodSpec) error {
+	if appSpec.DNSPolicy != "" {
+		policy := corev1.DNSPolicy(appSpec.DNSPolicy)
+		if !dnsPolicies[policy] {
+			return fmt.Errorf("invalid dnsPolicy [%s], must be one of %s, %s, %s or %s", appSpec.DNSPolicy,
+				corev1.DNSClusterFirst, corev1.DNSClusterFirstWithHostNet, corev1.DNSDefault, corev1.DNSNone)
+		}
+		if policy == corev1.DNSNone && (appSpec.DNSConfig == nil || len(appSpec.DNSConfig.Nameservers) == 0) {
+			return fmt.Errorf("dnsPolicy %s requires at least one nameserver in dnsConfig", corev1.DNSNone)
+		}
+		podSpec.DNSPolicy = policy
+	}
+
+	if appSpec.DNSConfig == nil {
+		return nil
+	}
+
+	for _, nameserver := range appSpec.DNSConfig.Nameservers {
+		if net.ParseIP(nameserver) == nil {
+			return fmt.Errorf("invalid dnsConfig nameserver [%s], must be an IP address", nameserver)
+		}
+	}
+
+	dnsConfig := &corev1.PodDNSConfig{
+		Nameservers: appSpec.DNSConfig.Nameservers,
+		Searches:    appSpec.DNSConfig.Searches,
+	}
+	for _, option := range appSpec.DNSConfig.Options {
+		if option.Name == "" {
+			return fmt.Errorf("invalid dnsConfig option, name is required")
+		}
+		podOption := corev1.PodDNSConfigOption{
+			Name: option.Name,
+		}
+		if option.Value != "" {
+			podOption.Value = &[]string{option.Value}[0]
+		}
+		dnsConfig.Options = append(dnsConfig.Options, podOption)
+	}
+	podSpec.DNSConfig = dnsConfig
+	return nil
+}
@@ -1,10 +1,19 @@
 package appdefinition
 
 import (
+	"context"
 	"testing"
 
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestFileModes(t *testing.T) {
@@ -18,3 +27,87 @@ func TestFileModesBug(t *testing.T) {
 func TestInterpolation(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/interpolation", DeploySpec)
 }
+
+func argsInterpolator(t *testing.T) (*v1.AppInstance, *secrets.Interpolator) {
+	t.Helper()
+
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-namespace",
+			UID:       "1234567890abcdef",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-created-namespace",
+		},
+	}
+	return app, secrets.NewInterpolator(router.Request{
+		Ctx: context.Background(),
+		Client: &tester.Client{
+			SchemeObj: scheme.Scheme,
+			Objects: []kclient.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "sec-1", Namespace: "app-created-namespace"},
+					Data:       map[string][]byte{"key1": []byte("value1")},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "db-creds", Namespace: "app-created-namespace"},
+					Data:       map[string][]byte{"token": []byte("db-token")},
+				},
+				&v1.ServiceInstance{
+					ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "app-created-namespace"},
+					Spec: v1.ServiceInstanceSpec{
+						Address: "db.internal",
+					},
+				},
+			},
+		},
+		Object: app,
+	}, app)
+}
+
+func TestArgsInterpolation(t *testing.T) {
+	app, interpolator := argsInterpolator(t)
+	container := toContainer(app, testTag, "web", v1.Container{
+		Command: []string{
+			"--host=@{service.db.address}",
+			"--password=@{secret.sec-1.key1}",
+			"--token=@{service.db.secrets.db-creds.token}",
+			"plain",
+		},
+	}, interpolator)
+	require.NoError(t, interpolator.Err())
+
+	assert.Equal(t, []string{"--host=db.internal", "$(ACORN_ARG_1)", "$(ACORN_ARG_2)", "plain"}, container.Args)
+	require.Len(t, container.Env, 2)
+	assert.Equal(t, "ACORN_ARG_1", container.Env[0].Name)
+	assert.Equal(t, "ACORN_ARG_2", container.Env[1].Name)
+
+	// The secret values are only stored in the interpolated secret
+	objs := interpolator.Objects()
+	require.Len(t, objs, 1)
+	secret := objs[0].(*corev1.Secret)
+	for i, expected := range []string{"--password=value1", "--token=db-token"} {
+		ref := container.Env[i].ValueFrom.SecretKeyRef
+		require.NotNil(t, ref)
+		assert.Equal(t, interpolator.SecretName(), ref.Name)
+		assert.Equal(t, expected, string(secret.Data[ref.Key]))
+	}
+}
+
+func TestReplacePlain(t *testing.T) {
+	_, interpolator := argsInterpolator(t)
+
+	value, err := interpolator.ReplacePlain("@{service.db.address}/@{app.name}")
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal/app-name", value)
+
+	for _, content := range []string{
+		"@{secret.sec-1.key1}",
+		"@{secrets://sec-1/key1}",
+		"@{service.db.secrets.db-creds.token}",
+	} {
+		_, err := interpolator.ReplacePlain(content)
+		assert.ErrorIs(t, err, secrets.ErrSecretReference, content)
+	}
+}
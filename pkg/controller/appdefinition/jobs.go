@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/config"
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/secrets"
 	"github.com/acorn-io/baaah/pkg/router"
@@ -11,6 +12,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -37,11 +39,50 @@ func toJobs(req router.Request, appInstance *v1.AppInstance, pullSecrets *PullSe
 		if perms := v1.FindPermission(job.GetName(), appInstance.Spec.Permissions); perms.HasRules() {
 			result = append(result, toPermissions(perms, job.GetLabels(), job.GetAnnotations(), appInstance)...)
 		}
-		result = append(result, sa, job)
+		result = append(result, sa)
+
+		cleanedUp, err := generationJobCleanedUp(req, appInstance, job)
+		if err != nil {
+			return nil, err
+		}
+		if !cleanedUp {
+			result = append(result, job)
+		}
 	}
 	return result, nil
 }
 
+// generationJobCleanedUp returns true if the job generates secrets and was deleted after it finished, because of its
+// TTL, once the secrets were generated. Such a job must not be created again, that would generate new values.
+func generationJobCleanedUp(req router.Request, appInstance *v1.AppInstance, obj kclient.Object) (bool, error) {
+	job, ok := obj.(*batchv1.Job)
+	if !ok || job.Spec.TTLSecondsAfterFinished == nil {
+		return false, nil
+	}
+
+	err := req.Get(&batchv1.Job{}, job.Namespace, job.Name)
+	if !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	return secrets.GenerationJobFinished(req, appInstance, job.Name)
+}
+
+// applyGenerationJobSettings sets the backoff limit and the TTL after finishing of the job if it generates secrets
+func applyGenerationJobSettings(req router.Request, appInstance *v1.AppInstance, name string, jobSpec *batchv1.JobSpec) error {
+	cfg, err := config.Get(req.Ctx, req.Client)
+	if err != nil {
+		return err
+	}
+
+	backoffLimit, ttlSeconds, ok, err := secrets.GenerationJobSettings(cfg, &appInstance.Status.AppSpec, name)
+	if err != nil || !ok {
+		return err
+	}
+	jobSpec.BackoffLimit = backoffLimit
+	jobSpec.TTLSecondsAfterFinished = ttlSeconds
+	return nil
+}
+
 func setTerminationPath(containers []corev1.Container) (result []corev1.Container) {
 	for _, c := range containers {
 		c.TerminationMessagePath = "/run/secrets/output"
@@ -60,7 +101,7 @@ func toJob(req router.Request, appInstance *v1.AppInstance, pullSecrets *PullSec
 		return nil, err
 	}
 
-	volumes, err := toVolumes(appInstance, container, interpolator)
+	volumes, err := toVolumes(req, appInstance, container, interpolator)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +121,7 @@ func toJob(req router.Request, appInstance *v1.AppInstance, pullSecrets *PullSec
 			Spec: corev1.PodSpec{
 				Affinity:                      appInstance.Status.Scheduling[name].Affinity,
 				Tolerations:                   appInstance.Status.Scheduling[name].Tolerations,
-				TerminationGracePeriodSeconds: &[]int64{5}[0],
+				TerminationGracePeriodSeconds: terminationGracePeriod(container),
 				ImagePullSecrets:              pullSecrets.ForContainer(name, append(containers, initContainers...)),
 				EnableServiceLinks:            new(bool),
 				RestartPolicy:                 corev1.RestartPolicyNever,
@@ -92,6 +133,26 @@ func toJob(req router.Request, appInstance *v1.AppInstance, pullSecrets *PullSec
 		},
 	}
 
+	if err := applyPodSecurity(req, &jobSpec.Template.Spec); err != nil {
+		return nil, err
+	}
+
+	if err := applyReadOnlyRootFilesystem(req, name, container, &jobSpec.Template.Spec); err != nil {
+		return nil, err
+	}
+
+	if err := applyDNS(appInstance.Status.AppSpec, &jobSpec.Template.Spec); err != nil {
+		return nil, err
+	}
+
+	if err := applyNodeSelection(appInstance.Status.AppSpec, &jobSpec.Template.Spec); err != nil {
+		return nil, err
+	}
+
+	if err := applyGenerationJobSettings(req, appInstance, name, &jobSpec); err != nil {
+		return nil, err
+	}
+
 	interpolator.AddMissingAnnotations(baseAnnotations)
 
 	if container.Schedule == "" {
@@ -3,9 +3,17 @@ package appdefinition
 import (
 	"testing"
 
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/controller/namespace"
+	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/system"
 	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestJobs(t *testing.T) {
@@ -23,3 +31,134 @@ func TestJobsLabelsNamespace(t *testing.T) {
 func TestCronJobs(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/cronjob", DeploySpec)
 }
+
+func generationJobApp() *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Jobs: map[string]v1.Container{
+					"gen":   {Image: "gen"},
+					"gen2":  {Image: "gen"},
+					"other": {Image: "other"},
+				},
+				Secrets: map[string]v1.Secret{
+					"a": {
+						Type: "generated",
+						Params: v1.GenericMap{
+							"job": "gen",
+						},
+					},
+					"b": {
+						Type: "generated",
+						Params: v1.GenericMap{
+							"job":                     "gen2",
+							"backoffLimit":            int64(0),
+							"ttlSecondsAfterFinished": int64(60),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func generationJobConfig(config string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      system.ConfigName,
+			Namespace: system.Namespace,
+		},
+		Data: map[string]string{
+			"config": config,
+		},
+	}
+}
+
+func collectedJobs(objs []kclient.Object) map[string]*batchv1.Job {
+	result := map[string]*batchv1.Job{}
+	for _, obj := range objs {
+		if job, ok := obj.(*batchv1.Job); ok {
+			result[job.Name] = job
+		}
+	}
+	return result
+}
+
+func TestGenerationJobSettings(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			generationJobConfig(`{"generationJobBackoffLimit": 2, "generationJobTTLSeconds": 300}`),
+		},
+	}
+	resp, err := h.InvokeFunc(t, generationJobApp(), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := collectedJobs(resp.Collected)
+	if !assert.Len(t, jobs, 3) {
+		return
+	}
+	assert.Equal(t, int32(2), *jobs["gen"].Spec.BackoffLimit)
+	assert.Equal(t, int32(300), *jobs["gen"].Spec.TTLSecondsAfterFinished)
+	assert.Equal(t, int32(0), *jobs["gen2"].Spec.BackoffLimit)
+	assert.Equal(t, int32(60), *jobs["gen2"].Spec.TTLSecondsAfterFinished)
+	assert.Nil(t, jobs["other"].Spec.BackoffLimit)
+	assert.Nil(t, jobs["other"].Spec.TTLSecondsAfterFinished)
+}
+
+func TestGenerationJobDefaults(t *testing.T) {
+	resp, err := (&tester.Harness{Scheme: scheme.Scheme}).InvokeFunc(t, generationJobApp(), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := collectedJobs(resp.Collected)
+	if !assert.Len(t, jobs, 3) {
+		return
+	}
+	assert.Equal(t, int32(3), *jobs["gen"].Spec.BackoffLimit)
+	assert.Nil(t, jobs["gen"].Spec.TTLSecondsAfterFinished)
+}
+
+func TestGenerationJobNotRecreatedAfterCleanup(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			// Only the secret of gen2 was generated, its job was deleted because of its TTL
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "b-abcde",
+					Namespace: "app-ns",
+					Labels: map[string]string{
+						labels.AcornAppName:         "app",
+						labels.AcornManaged:         "true",
+						labels.AcornSecretName:      "b",
+						labels.AcornSecretGenerated: "true",
+					},
+				},
+				Data: map[string][]byte{
+					"content": []byte("generated"),
+				},
+			},
+		},
+	}
+	resp, err := h.InvokeFunc(t, generationJobApp(), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := collectedJobs(resp.Collected)
+	assert.Contains(t, jobs, "gen")
+	assert.Contains(t, jobs, "other")
+	assert.NotContains(t, jobs, "gen2")
+}
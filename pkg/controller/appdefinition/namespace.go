@@ -6,6 +6,7 @@ import (
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/condition"
+	"github.com/acorn-io/acorn/pkg/controller/namespace"
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/rancher/wrangler/pkg/name"
@@ -76,6 +77,34 @@ func IgnoreTerminatingNamespace(h router.Handler) router.Handler {
 	})
 }
 
+// NamespaceTerminatingStatus reports the namespace of the app being stuck terminating, which the handlers that skip
+// terminating namespaces would otherwise leave unnoticed
+func NamespaceTerminatingStatus(req router.Request, resp router.Response) error {
+	appInstance := req.Object.(*v1.AppInstance)
+	cond := condition.Setter(appInstance, resp, v1.AppInstanceConditionNamespaceTerminating)
+
+	ns := &corev1.Namespace{}
+	if err := req.Get(ns, "", appInstance.Status.Namespace); apierror.IsNotFound(err) {
+		cond.Success()
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	terminatingFor, terminating := namespace.TerminatingFor(ns)
+	if !terminating {
+		cond.Success()
+	} else if terminatingFor < namespace.TerminatingThreshold {
+		cond.Unknown(fmt.Sprintf("namespace [%s] is terminating", ns.Name))
+		resp.RetryAfter(namespace.TerminatingThreshold - terminatingFor)
+	} else {
+		cond.Error(namespace.StuckTerminatingError(ns))
+	}
+
+	resp.Objects(appInstance)
+	return nil
+}
+
 func RequireNamespace(h router.Handler) router.Handler {
 	return router.HandlerFunc(func(req router.Request, resp router.Response) error {
 		appInstance := req.Object.(*v1.AppInstance)
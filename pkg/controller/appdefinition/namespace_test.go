@@ -2,13 +2,16 @@ package appdefinition
 
 import (
 	"testing"
+	"time"
 
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/controller/namespace"
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/scheme"
 	"github.com/acorn-io/baaah/pkg/router/tester"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -28,6 +31,10 @@ func TestLabelsAnnotationsNoConfigset(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/propagation_noconfig", namespace.AddNamespace)
 }
 
+func TestPodSecurityRestricted(t *testing.T) {
+	tester.DefaultTest(t, scheme.Scheme, "testdata/podsecurity_restricted", namespace.AddNamespace)
+}
+
 func TestHandler_AddAcornProjectLabel(t *testing.T) {
 	harness, input, err := tester.FromDir(scheme.Scheme, "testdata/addacornprojectlabel")
 	if err != nil {
@@ -69,3 +76,106 @@ func TestHandler_AddAcornProjectLabelAlreadyExists(t *testing.T) {
 
 	assert.Equal(t, "true", projectNamespace.Labels[labels.AcornProject])
 }
+
+func TestNamespaceTerminatingStatus(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			&v1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "app-target-ns",
+					DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+					Finalizers:        []string{"example.com/leaked"},
+				},
+				Spec: v1.NamespaceSpec{
+					Finalizers: []v1.FinalizerName{v1.FinalizerKubernetes},
+				},
+				Status: v1.NamespaceStatus{
+					Phase: v1.NamespaceTerminating,
+					Conditions: []v1.NamespaceCondition{
+						{
+							Type:    v1.NamespaceContentRemaining,
+							Status:  v1.ConditionTrue,
+							Message: "Some resources are remaining: persistentvolumeclaims. has 1 resource instances",
+						},
+						{
+							Type:    v1.NamespaceFinalizersRemaining,
+							Status:  v1.ConditionTrue,
+							Message: "Some content in the namespace has finalizers remaining: kubernetes.io/pvc-protection in 1 resource instances",
+						},
+						{
+							Type:    v1.NamespaceDeletionDiscoveryFailure,
+							Status:  v1.ConditionFalse,
+							Message: "All resources successfully discovered",
+						},
+					},
+				},
+			},
+		},
+	}
+	resp, err := h.InvokeFunc(t, &apiv1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+		},
+		Status: apiv1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+		},
+	}, NamespaceTerminatingStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := resp.Collected[0].(*apiv1.AppInstance)
+	var cond apiv1.Condition
+	for _, c := range app.Status.Conditions {
+		if c.Type == apiv1.AppInstanceConditionNamespaceTerminating {
+			cond = c
+		}
+	}
+	assert.True(t, cond.Error)
+	assert.Contains(t, cond.Message, "namespace [app-target-ns] has been terminating since")
+	assert.Contains(t, cond.Message, "finalizers [kubernetes example.com/leaked]")
+	assert.Contains(t, cond.Message, "persistentvolumeclaims")
+	assert.Contains(t, cond.Message, "kubernetes.io/pvc-protection")
+	assert.NotContains(t, cond.Message, "successfully discovered")
+}
+
+func TestNamespaceTerminatingStatusRecent(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			&v1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "app-target-ns",
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Status: v1.NamespaceStatus{
+					Phase: v1.NamespaceTerminating,
+				},
+			},
+		},
+	}
+	resp, err := h.InvokeFunc(t, &apiv1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+		},
+		Status: apiv1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+		},
+	}, NamespaceTerminatingStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the namespace is only reported as stuck after the threshold
+	app := resp.Collected[0].(*apiv1.AppInstance)
+	for _, c := range app.Status.Conditions {
+		if c.Type == apiv1.AppInstanceConditionNamespaceTerminating {
+			assert.False(t, c.Error)
+			assert.True(t, c.Transitioning)
+		}
+	}
+	assert.NotZero(t, resp.Delay)
+}
@@ -0,0 +1,65 @@
+package appdefinition
+
+import (
+	"fmt"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// applyNodeSelection adds the node selector and tolerations of the app to the pod spec. The tolerations are added to
+// the ones already calculated for the workload, such as those of its compute class.
+func applyNodeSelection(appSpec v1.AppSpec, podSpec *corev1.PodSpec) error {
+	for _, toleration := range appSpec.Tolerations {
+		if err := validateToleration(toleration); err != nil {
+			return err
+		}
+	}
+
+	if len(appSpec.NodeSelector) > 0 {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		for k, v := range appSpec.NodeSelector {
+			podSpec.NodeSelector[k] = v
+		}
+	}
+
+	for _, toleration := range appSpec.Tolerations {
+		if slices.IndexFunc(podSpec.Tolerations, func(existing corev1.Toleration) bool {
+			return existing.MatchToleration(&toleration)
+		}) < 0 {
+			podSpec.Tolerations = append(podSpec.Tolerations, toleration)
+		}
+	}
+	return nil
+}
+
+func validateToleration(toleration corev1.Toleration) error {
+	switch toleration.Operator {
+	case "", corev1.TolerationOpEqual:
+		if toleration.Key == "" {
+			return fmt.Errorf("invalid toleration, operator %s requires a key", corev1.TolerationOpEqual)
+		}
+	case corev1.TolerationOpExists:
+		if toleration.Value != "" {
+			return fmt.Errorf("invalid toleration [%s], operator %s must not have a value", toleration.Key, corev1.TolerationOpExists)
+		}
+	default:
+		return fmt.Errorf("invalid toleration [%s], operator [%s] must be %s or %s", toleration.Key, toleration.Operator,
+			corev1.TolerationOpEqual, corev1.TolerationOpExists)
+	}
+
+	switch toleration.Effect {
+	case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule:
+		if toleration.TolerationSeconds != nil {
+			return fmt.Errorf("invalid toleration [%s], tolerationSeconds requires effect %s", toleration.Key, corev1.TaintEffectNoExecute)
+		}
+	case corev1.TaintEffectNoExecute:
+	default:
+		return fmt.Errorf("invalid toleration [%s], effect [%s] must be %s, %s or %s", toleration.Key, toleration.Effect,
+			corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+	}
+	return nil
+}
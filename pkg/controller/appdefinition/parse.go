@@ -4,6 +4,7 @@ import (
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/appdefinition"
 	"github.com/acorn-io/acorn/pkg/condition"
+	"github.com/acorn-io/acorn/pkg/secrets"
 	"github.com/acorn-io/baaah/pkg/router"
 )
 
@@ -35,6 +36,13 @@ func ParseAppImage(req router.Request, resp router.Response) error {
 	}
 
 	appInstance.Status.AppSpec = *appSpec
+
+	// The default secrets of the project are declared here too, so that the handlers running before CreateSecrets,
+	// like DeploySpec, can resolve references to them
+	if err := secrets.AddProjectDefaults(req, appInstance); err != nil {
+		status.Error(err)
+		return nil
+	}
 	status.Success()
 	return nil
 }
@@ -0,0 +1,45 @@
+package appdefinition
+
+import (
+	"github.com/acorn-io/acorn/pkg/config"
+	"github.com/acorn-io/baaah/pkg/router"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const podSecurityProfileRestricted = "restricted"
+
+// applyPodSecurity sets the security contexts required by the restricted Pod Security Standard when app namespaces
+// are labeled to enforce it. Without them, Pod Security Admission would reject every pod the app creates.
+func applyPodSecurity(req router.Request, podSpec *corev1.PodSpec) error {
+	cfg, err := config.Get(req.Ctx, req.Client)
+	if err != nil {
+		return err
+	}
+	if !*cfg.SetPodSecurityEnforceProfile || cfg.PodSecurityEnforceProfile != podSecurityProfileRestricted {
+		return nil
+	}
+
+	if podSpec.SecurityContext == nil {
+		podSpec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	podSpec.SecurityContext.RunAsNonRoot = &[]bool{true}[0]
+	podSpec.SecurityContext.SeccompProfile = &corev1.SeccompProfile{
+		Type: corev1.SeccompProfileTypeRuntimeDefault,
+	}
+
+	restrictContainers(podSpec.Containers)
+	restrictContainers(podSpec.InitContainers)
+	return nil
+}
+
+func restrictContainers(containers []corev1.Container) {
+	for i := range containers {
+		if containers[i].SecurityContext == nil {
+			containers[i].SecurityContext = &corev1.SecurityContext{}
+		}
+		containers[i].SecurityContext.AllowPrivilegeEscalation = new(bool)
+		containers[i].SecurityContext.Capabilities = &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		}
+	}
+}
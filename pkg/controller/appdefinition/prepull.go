@@ -0,0 +1,134 @@
+package appdefinition
+
+import (
+	"sort"
+	"strconv"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/images"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/google/go-containerregistry/pkg/name"
+	name2 "github.com/rancher/wrangler/pkg/name"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func prePullName(appInstance *v1.AppInstance) string {
+	return name2.SafeConcatName("prepull", appInstance.ShortID())
+}
+
+// prePullImages returns the images of the containers, sidecars and jobs of the app, sorted and without duplicates
+func prePullImages(appInstance *v1.AppInstance, tag name.Reference) []string {
+	seen := map[string]bool{}
+	add := func(container v1.Container) {
+		if container.Image != "" {
+			seen[images.ResolveTag(tag, container.Image)] = true
+		}
+		for _, sidecar := range container.Sidecars {
+			if sidecar.Image != "" {
+				seen[images.ResolveTag(tag, sidecar.Image)] = true
+			}
+		}
+	}
+	for _, container := range appInstance.Status.AppSpec.Containers {
+		add(container)
+	}
+	for _, job := range appInstance.Status.AppSpec.Jobs {
+		add(job)
+	}
+
+	result := make([]string, 0, len(seen))
+	for image := range seen {
+		result = append(result, image)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// addPrePull creates a DaemonSet that pulls the images of the app onto every node when the app is run with prePull,
+// so that containers scheduled to any node start without waiting for a pull. Each image is pulled by an init
+// container that only runs the acorn helper, which needs nothing from the image. The DaemonSet stays in place while
+// prePull is set, which also keeps the images from being garbage collected on the nodes.
+func addPrePull(req router.Request, appInstance *v1.AppInstance, tag name.Reference, pullSecrets *PullSecrets, resp router.Response) error {
+	if !appInstance.Spec.GetPrePull() {
+		return nil
+	}
+
+	imageNames := prePullImages(appInstance, tag)
+	if len(imageNames) == 0 {
+		return nil
+	}
+
+	helperMount := corev1.VolumeMount{
+		Name:      sanitizeVolumeName(AcornHelper),
+		MountPath: AcornHelperPath,
+	}
+	initContainers := []corev1.Container{
+		{
+			Name:            "acorn-helper",
+			Image:           system.DefaultImage(),
+			Command:         []string{"acorn-helper-init"},
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			VolumeMounts:    []corev1.VolumeMount{helperMount},
+		},
+	}
+	pulled := make([]corev1.Container, 0, len(imageNames))
+	for i, image := range imageNames {
+		pulled = append(pulled, corev1.Container{
+			Name:            name2.SafeConcatName("pull", strconv.Itoa(i)),
+			Image:           image,
+			Command:         []string{AcornHelperPath + "/acorn-helper", "--help"},
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			VolumeMounts:    []corev1.VolumeMount{helperMount},
+		})
+	}
+	initContainers = append(initContainers, pulled...)
+
+	podLabels := labels.Managed(appInstance, labels.AcornPrePull, "true")
+	podSpec := corev1.PodSpec{
+		InitContainers:   initContainers,
+		ImagePullSecrets: pullSecrets.ForContainer("prepull", pulled),
+		Containers: []corev1.Container{
+			{
+				Name:            "pause",
+				Image:           system.DefaultImage(),
+				Command:         []string{"sleep", "infinity"},
+				ImagePullPolicy: corev1.PullIfNotPresent,
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: helperMount.Name,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			},
+		},
+	}
+	if err := applyPodSecurity(req, &podSpec); err != nil {
+		return err
+	}
+
+	resp.Objects(&appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prePullName(appInstance),
+			Namespace: appInstance.Status.Namespace,
+			Labels:    podLabels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: podSpec,
+			},
+		},
+	})
+	return nil
+}
@@ -0,0 +1,99 @@
+package appdefinition
+
+import (
+	"strings"
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	prePullWebImage = "sha256:" + strings.Repeat("a", 64)
+	prePullJobImage = "sha256:" + strings.Repeat("b", 64)
+)
+
+func prePullApp(prePull *bool) *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+			UID:       "1234567890abcdef",
+		},
+		Spec: v1.AppInstanceSpec{
+			PrePull: prePull,
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Containers: map[string]v1.Container{
+					"web": {
+						Image: prePullWebImage,
+						Sidecars: map[string]v1.Container{
+							"init": {
+								Image: prePullWebImage,
+								Init:  true,
+							},
+						},
+					},
+				},
+				Jobs: map[string]v1.Container{
+					"job": {
+						Image: prePullJobImage,
+					},
+				},
+			},
+		},
+	}
+}
+
+func findPrePull(objs []kclient.Object) *appsv1.DaemonSet {
+	for _, obj := range objs {
+		if ds, ok := obj.(*appsv1.DaemonSet); ok && ds.Name == "prepull-1234567890ab" {
+			return ds
+		}
+	}
+	return nil
+}
+
+func TestPrePull(t *testing.T) {
+	resp, err := (&tester.Harness{Scheme: scheme.Scheme}).InvokeFunc(t, prePullApp(&[]bool{true}[0]), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := findPrePull(resp.Collected)
+	if !assert.NotNil(t, ds) {
+		return
+	}
+	assert.Equal(t, "app-target-ns", ds.Namespace)
+
+	podSpec := ds.Spec.Template.Spec
+	var pulled []string
+	for _, container := range podSpec.InitContainers[1:] {
+		pulled = append(pulled, container.Image)
+		assert.Equal(t, corev1.PullIfNotPresent, container.ImagePullPolicy)
+	}
+	assert.Equal(t, []string{
+		"index.docker.io/library/test@" + prePullWebImage,
+		"index.docker.io/library/test@" + prePullJobImage,
+	}, pulled)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "prepull-pull-1234567890ab"}}, podSpec.ImagePullSecrets)
+}
+
+func TestPrePullDisabled(t *testing.T) {
+	resp, err := (&tester.Harness{Scheme: scheme.Scheme}).InvokeFunc(t, prePullApp(nil), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, findPrePull(resp.Collected))
+}
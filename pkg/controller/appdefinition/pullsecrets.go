@@ -1,7 +1,9 @@
 package appdefinition
 
 import (
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/config"
 	"github.com/acorn-io/acorn/pkg/pullsecret"
 	"github.com/acorn-io/baaah/pkg/merr"
 	"github.com/acorn-io/baaah/pkg/router"
@@ -12,21 +14,24 @@ import (
 )
 
 type PullSecrets struct {
-	objects  []kclient.Object
-	keychain authn.Keychain
-	app      *v1.AppInstance
-	errs     []error
+	objects        []kclient.Object
+	keychain       authn.Keychain
+	registryMirror string
+	app            *v1.AppInstance
+	errs           []error
 }
 
-func NewPullSecrets(req router.Request, appInstance *v1.AppInstance) (*PullSecrets, error) {
+// NewPullSecrets returns the pull secrets of the app. The config is read once by the handler and passed in.
+func NewPullSecrets(req router.Request, cfg *apiv1.Config, appInstance *v1.AppInstance) (*PullSecrets, error) {
 	keychain, err := pullsecret.Keychain(req.Ctx, req.Client, appInstance.Namespace)
 	if err != nil {
 		return nil, err
 	}
 
 	return &PullSecrets{
-		keychain: keychain,
-		app:      appInstance,
+		keychain:       keychain,
+		registryMirror: config.RegistryMirror(cfg, appInstance.GetRegion()),
+		app:            appInstance,
 	}, nil
 }
 
@@ -64,7 +69,7 @@ func (p *PullSecrets) ForContainer(containerName string, containers []corev1.Con
 	}
 
 	secretName := name.SafeConcatName(containerName, "pull", p.app.ShortID())
-	secret, err := pullsecret.ForImages(secretName, p.app.Status.Namespace, p.keychain, images...)
+	secret, err := pullsecret.ForImages(secretName, p.app.Status.Namespace, p.keychain, p.registryMirror, images...)
 	if err != nil {
 		p.errs = append(p.errs, err)
 		return nil
@@ -1,10 +1,17 @@
 package appdefinition
 
 import (
+	"context"
 	"testing"
 
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestPullSecrets(t *testing.T) {
@@ -14,3 +21,36 @@ func TestPullSecrets(t *testing.T) {
 func TestPullSecretsCustom(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/pullsecrets/custom", DeploySpec)
 }
+
+func TestPullSecretsRegistryMirror(t *testing.T) {
+	cfg := &apiv1.Config{
+		RegistryMirrors: []string{"east=east-mirror.example.com", "west=west-mirror.example.com"},
+	}
+	for region, mirror := range map[string]string{
+		"east":  "east-mirror.example.com",
+		"west":  "west-mirror.example.com",
+		"north": "",
+	} {
+		app := &v1.AppInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-name",
+				Namespace: "app-namespace",
+			},
+			Status: v1.AppInstanceStatus{
+				Namespace: "app-created-namespace",
+				Defaults: v1.Defaults{
+					Region: region,
+				},
+			},
+		}
+		p, err := NewPullSecrets(router.Request{
+			Ctx: context.Background(),
+			Client: &tester.Client{
+				SchemeObj: scheme.Scheme,
+			},
+			Object: app,
+		}, cfg, app)
+		require.NoError(t, err, region)
+		assert.Equal(t, mirror, p.registryMirror, region)
+	}
+}
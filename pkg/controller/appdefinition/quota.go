@@ -0,0 +1,180 @@
+package appdefinition
+
+import (
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/ports"
+	"github.com/acorn-io/baaah/pkg/router"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const resourceQuotaName = "acorn-app"
+
+// quotaResources are the compute resources a quota can cover, each read from the requests or the limits of the
+// containers. Acorn sets memory as both the request and the limit, and cpu only as the request.
+var quotaResources = []struct {
+	name     corev1.ResourceName
+	resource corev1.ResourceName
+	limit    bool
+}{
+	{name: corev1.ResourceRequestsCPU, resource: corev1.ResourceCPU},
+	{name: corev1.ResourceRequestsMemory, resource: corev1.ResourceMemory},
+	{name: corev1.ResourceLimitsMemory, resource: corev1.ResourceMemory, limit: true},
+}
+
+// helperResources are given by the LimitRange to the containers acorn adds to the app, like the routers and the dev
+// mode helper, which declare no resources of their own
+var helperResources = corev1.ResourceList{
+	corev1.ResourceCPU:    resource.MustParse("10m"),
+	corev1.ResourceMemory: resource.MustParse("64Mi"),
+}
+
+// quotaPods is a set of identical pods the app runs at the same time
+type quotaPods struct {
+	count      int64
+	containers []corev1.ResourceRequirements
+}
+
+// addResourceQuota bounds the resources of the app namespace to what the app declares, so that a runaway app can't
+// starve the nodes it runs on. Apps run with prePull are left without a quota, the number of pods their DaemonSet runs
+// depends on the number of nodes.
+func addResourceQuota(cfg *apiv1.Config, appInstance *v1.AppInstance, resp router.Response) {
+	if !*cfg.AppResourceQuota || appInstance.Spec.GetPrePull() {
+		return
+	}
+
+	quota, limitRange := toResourceQuota(appInstance)
+	resp.Objects(quota, limitRange)
+}
+
+// toResourceQuota returns the ResourceQuota and LimitRange for the namespace of the app. The quota allows the pods of
+// all containers at their scale, with room for the extra pods of a rolling update, and one pod for each job and
+// router. Compute resources are only covered when every container and job declares them, through memory or a compute
+// class, because a quota on a resource rejects pods that don't declare it. The LimitRange gives the containers acorn
+// adds itself the resources they are counted with.
+func toResourceQuota(appInstance *v1.AppInstance) (*corev1.ResourceQuota, *corev1.LimitRange) {
+	var (
+		pods     []quotaPods
+		declared = map[corev1.ResourceName]bool{}
+	)
+	for _, r := range quotaResources {
+		declared[r.name] = true
+	}
+
+	workload := func(name string, container v1.Container, count int64) {
+		p := quotaPods{count: count}
+		if appInstance.Spec.GetDevMode() && hasContextDir(container) {
+			p.containers = append(p.containers, corev1.ResourceRequirements{Requests: helperResources, Limits: helperResources})
+		}
+		names := []string{name}
+		for sidecarName := range container.Sidecars {
+			names = append(names, sidecarName)
+		}
+		for _, name := range names {
+			requirements := appInstance.Status.Scheduling[name].Requirements
+			for _, r := range quotaResources {
+				if _, ok := quantityOf(requirements, r.resource, r.limit); !ok {
+					declared[r.name] = false
+				}
+			}
+			p.containers = append(p.containers, requirements)
+		}
+		pods = append(pods, p)
+	}
+
+	for name, container := range appInstance.Status.AppSpec.Containers {
+		if ports.IsLinked(appInstance, name) {
+			continue
+		}
+		workload(name, container, containerPods(appInstance, container))
+	}
+	for name, job := range appInstance.Status.AppSpec.Jobs {
+		workload(name, job, 1)
+	}
+	for name, appRouter := range appInstance.Status.AppSpec.Routers {
+		if ports.IsLinked(appInstance, name) || len(appRouter.Routes) == 0 {
+			continue
+		}
+		// A single replica, and one more during a rolling update
+		pods = append(pods, quotaPods{
+			count:      2,
+			containers: []corev1.ResourceRequirements{{Requests: helperResources, Limits: helperResources}},
+		})
+	}
+
+	hard := corev1.ResourceList{}
+	var podCount int64
+	for _, p := range pods {
+		podCount += p.count
+	}
+	hard[corev1.ResourcePods] = *resource.NewQuantity(podCount, resource.DecimalSI)
+
+	limitRange := corev1.LimitRangeItem{
+		Type:           corev1.LimitTypeContainer,
+		Default:        corev1.ResourceList{},
+		DefaultRequest: corev1.ResourceList{},
+	}
+	for _, r := range quotaResources {
+		if !declared[r.name] {
+			continue
+		}
+		total := resource.Quantity{}
+		for _, p := range pods {
+			for _, requirements := range p.containers {
+				q, _ := quantityOf(requirements, r.resource, r.limit)
+				for i := int64(0); i < p.count; i++ {
+					total.Add(q)
+				}
+			}
+		}
+		hard[r.name] = total
+		if r.limit {
+			limitRange.Default[r.resource] = helperResources[r.resource]
+		} else {
+			limitRange.DefaultRequest[r.resource] = helperResources[r.resource]
+		}
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceQuotaName,
+			Namespace: appInstance.Status.Namespace,
+			Labels:    labels.Managed(appInstance),
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: hard,
+		},
+	}
+	return quota, &corev1.LimitRange{
+		ObjectMeta: *quota.ObjectMeta.DeepCopy(),
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{limitRange},
+		},
+	}
+}
+
+// containerPods returns the number of pods of a container during a rolling update, its scale and the 25% extra pods
+// a Deployment surges to by default. Stateful containers are recreated instead, and only ever run one pod.
+func containerPods(appInstance *v1.AppInstance, container v1.Container) int64 {
+	if isStateful(appInstance, container) {
+		return 1
+	}
+	replicas := int64(1)
+	if container.Scale != nil {
+		replicas = int64(*container.Scale)
+	}
+	return replicas + (replicas+3)/4
+}
+
+// quantityOf returns the request or the limit of a resource, and whether it is declared
+func quantityOf(requirements corev1.ResourceRequirements, name corev1.ResourceName, limit bool) (resource.Quantity, bool) {
+	list := requirements.Requests
+	if limit {
+		list = requirements.Limits
+	}
+	q, ok := list[name]
+	return q, ok
+}
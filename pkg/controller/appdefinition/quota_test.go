@@ -0,0 +1,155 @@
+package appdefinition
+
+import (
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func quotaRequirements(cpu, memory string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse(memory),
+		},
+	}
+}
+
+func quotaApp(scheduling map[string]v1.Scheduling) *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+			UID:       "1234567890abcdef",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Containers: map[string]v1.Container{
+					"web": {
+						Image: "web",
+						Scale: &[]int32{2}[0],
+						Sidecars: map[string]v1.Container{
+							"proxy": {
+								Image: "proxy",
+							},
+						},
+					},
+				},
+				Jobs: map[string]v1.Container{
+					"migrate": {
+						Image: "migrate",
+					},
+				},
+			},
+			Scheduling: scheduling,
+		},
+	}
+}
+
+func findQuota(objs []kclient.Object) (quota *corev1.ResourceQuota, limitRange *corev1.LimitRange) {
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *corev1.ResourceQuota:
+			quota = o
+		case *corev1.LimitRange:
+			limitRange = o
+		}
+	}
+	return
+}
+
+func quotaHarness() *tester.Harness {
+	return &tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      system.ConfigName,
+					Namespace: system.Namespace,
+				},
+				Data: map[string]string{
+					"config": `{"appResourceQuota": true}`,
+				},
+			},
+		},
+	}
+}
+
+func TestResourceQuota(t *testing.T) {
+	resp, err := quotaHarness().InvokeFunc(t, quotaApp(map[string]v1.Scheduling{
+		"web":     {Requirements: quotaRequirements("250m", "256Mi")},
+		"proxy":   {Requirements: quotaRequirements("50m", "128Mi")},
+		"migrate": {Requirements: quotaRequirements("500m", "512Mi")},
+	}), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quota, limitRange := findQuota(resp.Collected)
+	if !assert.NotNil(t, quota) || !assert.NotNil(t, limitRange) {
+		return
+	}
+	assert.Equal(t, "app-target-ns", quota.Namespace)
+
+	// Two replicas of web and a third during a rolling update, and the job
+	hard := quota.Spec.Hard
+	assert.Equal(t, "4", hard.Pods().String())
+	assert.Equal(t, "1400m", hard.Name(corev1.ResourceRequestsCPU, resource.DecimalSI).String())
+	assert.Equal(t, "1664Mi", hard.Name(corev1.ResourceRequestsMemory, resource.BinarySI).String())
+	assert.Equal(t, "1664Mi", hard.Name(corev1.ResourceLimitsMemory, resource.BinarySI).String())
+
+	assert.Equal(t, helperResources, limitRange.Spec.Limits[0].DefaultRequest)
+	assert.Equal(t, corev1.ResourceList{corev1.ResourceMemory: helperResources[corev1.ResourceMemory]},
+		limitRange.Spec.Limits[0].Default)
+}
+
+func TestResourceQuotaUndeclaredMemory(t *testing.T) {
+	proxy := quotaRequirements("50m", "128Mi")
+	proxy.Limits = nil
+	delete(proxy.Requests, corev1.ResourceMemory)
+
+	resp, err := quotaHarness().InvokeFunc(t, quotaApp(map[string]v1.Scheduling{
+		"web":     {Requirements: quotaRequirements("250m", "256Mi")},
+		"proxy":   {Requirements: proxy},
+		"migrate": {Requirements: quotaRequirements("500m", "512Mi")},
+	}), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The proxy would be rejected by a quota on memory, so only the pods and cpu are covered
+	quota, _ := findQuota(resp.Collected)
+	if !assert.NotNil(t, quota) {
+		return
+	}
+	hard := quota.Spec.Hard
+	assert.Equal(t, "4", hard.Pods().String())
+	assert.Equal(t, "1400m", hard.Name(corev1.ResourceRequestsCPU, resource.DecimalSI).String())
+	assert.NotContains(t, hard, corev1.ResourceRequestsMemory)
+	assert.NotContains(t, hard, corev1.ResourceLimitsMemory)
+}
+
+func TestResourceQuotaDisabled(t *testing.T) {
+	resp, err := (&tester.Harness{Scheme: scheme.Scheme}).InvokeFunc(t, quotaApp(nil), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quota, limitRange := findQuota(resp.Collected)
+	assert.Nil(t, quota)
+	assert.Nil(t, limitRange)
+}
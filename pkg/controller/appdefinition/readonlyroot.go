@@ -0,0 +1,94 @@
+package appdefinition
+
+import (
+	"path"
+	"sort"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/config"
+	"github.com/acorn-io/baaah/pkg/router"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const defaultWritablePath = "/tmp"
+
+// applyReadOnlyRootFilesystem runs the containers of the pod with a read-only root filesystem if they ask for it or
+// the config sets it for all apps. Each writable path of those containers gets a scratch emptyDir volume, unless a
+// volume is already mounted there.
+func applyReadOnlyRootFilesystem(req router.Request, containerName string, container v1.Container, podSpec *corev1.PodSpec) error {
+	cfg, err := config.Get(req.Ctx, req.Client)
+	if err != nil {
+		return err
+	}
+
+	specs := map[string]v1.Container{
+		containerName: container,
+	}
+	for name, sidecar := range container.Sidecars {
+		specs[name] = sidecar
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, readOnlyRootContainers(specs, *cfg.ReadOnlyRootFilesystem, podSpec.Containers)...)
+	podSpec.Volumes = append(podSpec.Volumes, readOnlyRootContainers(specs, *cfg.ReadOnlyRootFilesystem, podSpec.InitContainers)...)
+	sort.Slice(podSpec.Volumes, func(i, j int) bool {
+		return podSpec.Volumes[i].Name < podSpec.Volumes[j].Name
+	})
+	return nil
+}
+
+func readOnlyRootContainers(specs map[string]v1.Container, all bool, containers []corev1.Container) (volumes []corev1.Volume) {
+	for i := range containers {
+		// containers without a spec, like the acorn-helper of dev mode, are left as they are
+		spec, ok := specs[containers[i].Name]
+		if !ok || !(all || spec.ReadOnlyRootFilesystem) {
+			continue
+		}
+
+		if containers[i].SecurityContext == nil {
+			containers[i].SecurityContext = &corev1.SecurityContext{}
+		}
+		containers[i].SecurityContext.ReadOnlyRootFilesystem = &[]bool{true}[0]
+
+		for _, writablePath := range writablePaths(spec) {
+			if isMounted(containers[i], writablePath) {
+				continue
+			}
+			volumeName := "scratch-" + pathHash(containers[i].Name, writablePath)
+			volumes = append(volumes, corev1.Volume{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			})
+			containers[i].VolumeMounts = append(containers[i].VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: writablePath,
+			})
+		}
+	}
+	return
+}
+
+func writablePaths(container v1.Container) (result []string) {
+	if len(container.WritablePaths) == 0 {
+		return []string{defaultWritablePath}
+	}
+	seen := map[string]bool{}
+	for _, writablePath := range container.WritablePaths {
+		writablePath = path.Join("/", writablePath)
+		if !seen[writablePath] {
+			seen[writablePath] = true
+			result = append(result, writablePath)
+		}
+	}
+	return
+}
+
+func isMounted(container corev1.Container, mountPath string) bool {
+	for _, mount := range container.VolumeMounts {
+		if mount.MountPath == mountPath {
+			return true
+		}
+	}
+	return false
+}
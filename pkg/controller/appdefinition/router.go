@@ -133,7 +133,7 @@ func toRouter(appInstance *v1.AppInstance, routerName string, router v1.Router)
 		},
 	}
 
-	if appInstance.Spec.Stop != nil && *appInstance.Spec.Stop {
+	if appInstance.Spec.GetStopped() {
 		dep.Spec.Replicas = new(int32)
 	}
 
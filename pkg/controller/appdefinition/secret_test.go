@@ -4,11 +4,16 @@ import (
 	"testing"
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/controller/secrets"
+	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/scheme"
 	"github.com/acorn-io/baaah/pkg/router/tester"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestSecretDirsToMounts(t *testing.T) {
@@ -65,3 +70,179 @@ func TestSecretDirsToMounts(t *testing.T) {
 func TestSecretRedeploy(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/secret", DeploySpec)
 }
+
+func TestSecretModeToMounts(t *testing.T) {
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "app",
+		},
+		Status: v1.AppInstanceStatus{
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Containers: map[string]v1.Container{
+					"test": {
+						Files: map[string]v1.File{
+							"/root/.ssh/id_rsa": {
+								Mode: "0600",
+								Secret: v1.SecretReference{
+									Name: "ssh-key",
+									Key:  "ssh-privatekey",
+								},
+							},
+						},
+						Dirs: map[string]v1.VolumeMount{
+							"/keys": {
+								Secret: v1.VolumeSecretMount{
+									Name: "keys",
+									Mode: "0400",
+								},
+							},
+						},
+					},
+				},
+				Secrets: map[string]v1.Secret{
+					"ssh-key": {Type: "ssh-auth"},
+					"keys":    {},
+				},
+			},
+		},
+	}
+
+	dep := ToDeploymentsTest(t, app, testTag, nil)[1].(*appsv1.Deployment)
+	mounts := map[string]string{}
+	for _, mount := range dep.Spec.Template.Spec.Containers[0].VolumeMounts {
+		mounts[mount.MountPath] = mount.Name
+	}
+	assert.Equal(t, "secret--ssh-key-0600", mounts["/root/.ssh/id_rsa"])
+	assert.Equal(t, "secret--keys-0400", mounts["/keys"])
+
+	modes := map[string]int32{}
+	for _, vol := range dep.Spec.Template.Spec.Volumes {
+		if vol.Secret != nil && vol.Secret.DefaultMode != nil {
+			modes[vol.Name] = *vol.Secret.DefaultMode
+		}
+	}
+	assert.Equal(t, int32(0600), modes["secret--ssh-key-0600"])
+	assert.Equal(t, int32(0400), modes["secret--keys-0400"])
+}
+
+// podAnnotations returns the pod template annotations of the deployment rendered for input
+func podAnnotations(t *testing.T, harness *tester.Harness, input kclient.Object) map[string]string {
+	t.Helper()
+	resp, err := harness.InvokeFunc(t, input.DeepCopyObject().(kclient.Object), DeploySpec)
+	require.NoError(t, err)
+	for _, obj := range resp.Collected {
+		if dep, ok := obj.(*appsv1.Deployment); ok {
+			return dep.Spec.Template.Annotations
+		}
+	}
+	t.Fatal("no deployment rendered")
+	return nil
+}
+
+// setExisting replaces the existing object of the harness with the same name as obj
+func setExisting(harness *tester.Harness, obj kclient.Object) {
+	for i, existing := range harness.Existing {
+		if existing.GetName() == obj.GetName() && existing.GetNamespace() == obj.GetNamespace() {
+			harness.Existing[i] = obj
+			return
+		}
+	}
+	harness.Existing = append(harness.Existing, obj)
+}
+
+// publishSecret runs CreateSecrets for app and returns the published secret with the given name, along with the
+// objects it created so that the next run finds them
+func publishSecret(t *testing.T, existing []kclient.Object, app *v1.AppInstance, name string) (*corev1.Secret, []kclient.Object) {
+	t.Helper()
+	resp, err := (&tester.Harness{Scheme: scheme.Scheme, Existing: existing}).InvokeFunc(t, app, secrets.CreateSecrets)
+	require.NoError(t, err)
+	require.True(t, app.Status.Condition(v1.AppInstanceConditionSecrets).Success, app.Status.Condition(v1.AppInstanceConditionSecrets).Message)
+	for _, obj := range resp.Collected {
+		if secret, ok := obj.(*corev1.Secret); ok && secret.Name == name && secret.Namespace == app.Status.Namespace {
+			return secret, append(existing, resp.Client.Created...)
+		}
+	}
+	t.Fatalf("secret %s not published", name)
+	return nil, nil
+}
+
+func TestSecretRevisionChangesWithData(t *testing.T) {
+	harness, input, err := tester.FromDir(scheme.Scheme, "testdata/secret")
+	require.NoError(t, err)
+	// the expected output of the fixture only holds for the original secret data
+	harness.ExpectedOutput = nil
+
+	before := podAnnotations(t, harness, input)
+	require.NotEmpty(t, before[labels.AcornSecretRevPrefix+"secret_file_redeploy"])
+
+	for _, obj := range harness.Existing {
+		if secret, ok := obj.(*corev1.Secret); ok && secret.Name == "secret_file_redeploy" {
+			secret.Data = map[string][]byte{"a": []byte("rotated")}
+		}
+	}
+	after := podAnnotations(t, harness, input)
+
+	assert.NotEqual(t, before[labels.AcornSecretRevPrefix+"secret_file_redeploy"], after[labels.AcornSecretRevPrefix+"secret_file_redeploy"])
+	assert.Equal(t, before[labels.AcornSecretRevPrefix+"secret_env_redeploy"], after[labels.AcornSecretRevPrefix+"secret_env_redeploy"])
+	// secrets that don't redeploy on change have no revision
+	assert.NotContains(t, after, labels.AcornSecretRevPrefix+"secret_file_noaction")
+}
+
+func TestSecretRevisionIgnoresMetadata(t *testing.T) {
+	harness, input, err := tester.FromDir(scheme.Scheme, "testdata/secret")
+	require.NoError(t, err)
+	harness.ExpectedOutput = nil
+
+	before := podAnnotations(t, harness, input)
+	require.NotEmpty(t, before[labels.AcornSecretRevPrefix+"secret_file_redeploy"])
+
+	// relabeling a published secret bumps its resource version, but must not redeploy the containers that use it
+	for _, obj := range harness.Existing {
+		if secret, ok := obj.(*corev1.Secret); ok && secret.Name == "secret_file_redeploy" {
+			secret.Labels = labels.Merge(secret.Labels, map[string]string{"team": "payments"})
+			secret.Annotations = labels.Merge(secret.Annotations, map[string]string{"owner": "payments"})
+			secret.ResourceVersion = "2"
+		}
+	}
+	after := podAnnotations(t, harness, input)
+
+	assert.Equal(t, before[labels.AcornSecretRevPrefix+"secret_file_redeploy"], after[labels.AcornSecretRevPrefix+"secret_file_redeploy"])
+}
+
+func TestSecretRevisionIgnoresPublishedRelabel(t *testing.T) {
+	harness, input, err := tester.FromDir(scheme.Scheme, "testdata/secret")
+	require.NoError(t, err)
+	harness.ExpectedOutput = nil
+
+	// only the secret is generated from this copy of the app, the deployment is rendered from the input
+	app := input.DeepCopyObject().(*v1.AppInstance)
+	app.Status.AppSpec.Containers = nil
+	app.Status.AppSpec.Secrets = map[string]v1.Secret{
+		"secret_file_redeploy": {
+			Type: "opaque",
+			Data: map[string]string{"a": "1"},
+		},
+	}
+
+	published, backing := publishSecret(t, nil, app, "secret_file_redeploy")
+	setExisting(harness, published)
+	before := podAnnotations(t, harness, input)
+	require.NotEmpty(t, before[labels.AcornSecretRevPrefix+"secret_file_redeploy"])
+
+	// a label added to the app for the secret is applied to the published secret, without changing its data
+	app.Spec.Labels = []v1.ScopedLabel{
+		{ResourceType: v1.LabelTypeSecret, ResourceName: "secret_file_redeploy", Key: "team", Value: "payments"},
+	}
+	relabeled, _ := publishSecret(t, backing, app, "secret_file_redeploy")
+	require.Equal(t, "payments", relabeled.Labels["team"])
+	assert.Equal(t, published.Data, relabeled.Data)
+
+	relabeled.ResourceVersion = "2"
+	setExisting(harness, relabeled)
+	after := podAnnotations(t, harness, input)
+
+	assert.Equal(t, before[labels.AcornSecretRevPrefix+"secret_file_redeploy"], after[labels.AcornSecretRevPrefix+"secret_file_redeploy"])
+}
@@ -12,6 +12,7 @@ import (
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/condition"
 	"github.com/acorn-io/acorn/pkg/config"
+	"github.com/acorn-io/acorn/pkg/controller/networkpolicy"
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/ports"
 	"github.com/acorn-io/acorn/pkg/volume"
@@ -318,6 +319,45 @@ func VolumeStatus(req router.Request, resp router.Response) error {
 	return nil
 }
 
+// NetworkPolicyStatus summarizes the NetworkPolicies generated for the app in its status, so the traffic allowed to the
+// app can be seen without reading the policies. The policies for ingresses and services of linked apps can be in other
+// namespaces, so they are found by their app labels in all namespaces.
+func NetworkPolicyStatus(req router.Request, resp router.Response) error {
+	app := req.Object.(*v1.AppInstance)
+	netPols := &networkingv1.NetworkPolicyList{}
+
+	err := req.List(netPols, &kclient.ListOptions{
+		LabelSelector: klabels.SelectorFromSet(map[string]string{
+			labels.AcornManaged:      "true",
+			labels.AcornAppName:      app.Name,
+			labels.AcornAppNamespace: app.Namespace,
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(netPols.Items, func(i, j int) bool {
+		if netPols.Items[i].Namespace != netPols.Items[j].Namespace {
+			return netPols.Items[i].Namespace < netPols.Items[j].Namespace
+		}
+		return netPols.Items[i].Name < netPols.Items[j].Name
+	})
+
+	app.Status.NetworkPolicies = nil
+	for i := range netPols.Items {
+		netPol := &netPols.Items[i]
+		app.Status.NetworkPolicies = append(app.Status.NetworkPolicies, v1.NetworkPolicyStatus{
+			Name:        netPol.Name,
+			Namespace:   netPol.Namespace,
+			Description: networkpolicy.Describe(netPol),
+		})
+	}
+
+	resp.Objects(app)
+	return nil
+}
+
 func podsStatus(req router.Request, namespace string, sel klabels.Selector) (bool, map[string][]string, error) {
 	var (
 		isTransition bool
@@ -452,7 +492,7 @@ func AppStatus(req router.Request, resp router.Response) error {
 		cond.Success()
 	}
 
-	if !isTransition && app.Spec.Stop != nil && *app.Spec.Stop {
+	if !isTransition && app.Spec.GetStopped() {
 		allZero := true
 		for _, v := range app.Status.ContainerStatus {
 			if v.ReadyDesired != 0 {
@@ -462,9 +502,11 @@ func AppStatus(req router.Request, resp router.Response) error {
 		}
 		if allZero {
 			app.Status.Stopped = true
+			app.Status.Suspended = app.Spec.GetSuspended()
 		}
 	} else {
 		app.Status.Stopped = false
+		app.Status.Suspended = false
 	}
 
 	resp.Objects(app)
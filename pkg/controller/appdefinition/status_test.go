@@ -1,14 +1,22 @@
 package appdefinition
 
 import (
+	"context"
 	"testing"
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/condition"
+	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/scheme"
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/router/tester"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestCheckStatus(t *testing.T) {
@@ -36,3 +44,75 @@ func TestCheckStatus(t *testing.T) {
 
 	assert.True(t, called, "router handler call expected")
 }
+
+func testNetworkPolicy(name, namespace, appName string, spec networkingv1.NetworkPolicySpec) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				labels.AcornManaged:      "true",
+				labels.AcornAppName:      appName,
+				labels.AcornAppNamespace: "acorn",
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func TestNetworkPolicyStatus(t *testing.T) {
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "acorn",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-namespace",
+		},
+	}
+	port := intstr.FromInt(8080)
+	tcp := corev1.ProtocolTCP
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		testNetworkPolicy("app", "app-namespace", "app", networkingv1.NetworkPolicySpec{
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{labels.AcornAppNamespace: "acorn"},
+					},
+				}},
+			}},
+		}),
+		// policies of linked services live in the namespace of the service
+		testNetworkPolicy("acorn-app-web", "linked-namespace", "app", networkingv1.NetworkPolicySpec{
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0", Except: []string{"10.42.0.0/24"}}},
+					{NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"kubernetes.io/metadata.name": "acorn-system"},
+					}},
+				},
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port}},
+			}},
+		}),
+		testNetworkPolicy("other", "app-namespace", "other", networkingv1.NetworkPolicySpec{}),
+	).Build()
+
+	resp := &tester.Response{}
+	err := NetworkPolicyStatus(router.Request{Ctx: context.Background(), Client: c, Object: app}, resp)
+	require.NoError(t, err)
+
+	assert.Equal(t, []v1.NetworkPolicyStatus{
+		{
+			Name:        "app",
+			Namespace:   "app-namespace",
+			Description: "allows ingress from [project acorn] on all ports",
+		},
+		{
+			Name:        "acorn-app-web",
+			Namespace:   "linked-namespace",
+			Description: "allows ingress from [0.0.0.0/0 except [10.42.0.0/24], namespace acorn-system] on [tcp/8080]",
+		},
+	}, app.Status.NetworkPolicies)
+	assert.Len(t, resp.Collected, 1)
+}
@@ -2,6 +2,7 @@ package appdefinition
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -11,16 +12,21 @@ import (
 	"github.com/acorn-io/baaah/pkg/name"
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/config"
 	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/tracing"
 	"github.com/acorn-io/acorn/pkg/volume"
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/typed"
 	"github.com/acorn-io/baaah/pkg/uncached"
 	name2 "github.com/rancher/wrangler/pkg/name"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/version"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -29,7 +35,15 @@ const (
 	AcornHelperPath = "/.acorn"
 )
 
-func addPVCs(req router.Request, appInstance *v1.AppInstance, resp router.Response) error {
+// minReadWriteOncePodVersion is the first Kubernetes version where the ReadWriteOncePod access mode is enabled by default
+var minReadWriteOncePodVersion = version.MustParseGeneric("1.27.0")
+
+func addPVCs(req router.Request, appInstance *v1.AppInstance, resp router.Response) (err error) {
+	req, span := tracing.Start(req, "addPVCs")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	pvcs, err := toPVCs(req, appInstance)
 	if err != nil {
 		return err
@@ -45,12 +59,39 @@ func translateAccessModes(accessModes []v1.AccessMode) []corev1.PersistentVolume
 
 	result := make([]corev1.PersistentVolumeAccessMode, 0, len(accessModes))
 	for _, accessMode := range accessModes {
-		newMode := strings.ToUpper(string(accessMode[0:1])) + string(accessMode[1:])
-		result = append(result, corev1.PersistentVolumeAccessMode(newMode))
+		switch strings.ToLower(string(accessMode)) {
+		case "readwriteoncepod", "rwop":
+			result = append(result, corev1.ReadWriteOncePod)
+		default:
+			newMode := strings.ToUpper(string(accessMode[0:1])) + string(accessMode[1:])
+			result = append(result, corev1.PersistentVolumeAccessMode(newMode))
+		}
 	}
 	return result
 }
 
+// checkReadWriteOncePodSupported returns an error if any node in the cluster is running a version of Kubernetes
+// that does not enable the ReadWriteOncePod access mode by default.
+func checkReadWriteOncePodSupported(req router.Request, volumeName string) error {
+	var nodes corev1.NodeList
+	if err := req.List(&nodes, &kclient.ListOptions{}); err != nil {
+		return err
+	}
+
+	for _, node := range nodes.Items {
+		nodeVersion, err := version.ParseGeneric(node.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			// If the version can't be parsed then let Kubernetes decide if the access mode is supported
+			continue
+		}
+		if nodeVersion.LessThan(minReadWriteOncePodVersion) {
+			return fmt.Errorf("volume %s uses access mode %s which requires Kubernetes %s or newer, but node %s is running %s",
+				volumeName, v1.AccessModeReadWriteOncePod, minReadWriteOncePodVersion, node.Name, node.Status.NodeInfo.KubeletVersion)
+		}
+	}
+	return nil
+}
+
 func lookupExistingPV(req router.Request, appInstance *v1.AppInstance, volumeName string) (string, error) {
 	var pvc corev1.PersistentVolumeClaim
 	if err := req.Get(&pvc, appInstance.Status.Namespace, volumeName); err == nil {
@@ -133,6 +174,13 @@ func toPVCs(req router.Request, appInstance *v1.AppInstance) (result []kclient.O
 
 		volumeRequest = volume.CopyVolumeDefaults(volumeRequest, volumeBinding, appInstance.Status.Defaults.Volumes[vol])
 
+		accessModes := translateAccessModes(volumeRequest.AccessModes)
+		if slices.Contains(accessModes, corev1.ReadWriteOncePod) {
+			if err := checkReadWriteOncePodSupported(req, vol); err != nil {
+				return nil, err
+			}
+		}
+
 		pvc := corev1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      vol,
@@ -142,7 +190,7 @@ func toPVCs(req router.Request, appInstance *v1.AppInstance) (result []kclient.O
 					volumeRequest.Annotations, appInstance.Spec.Annotations),
 			},
 			Spec: corev1.PersistentVolumeClaimSpec{
-				AccessModes: translateAccessModes(volumeRequest.AccessModes),
+				AccessModes: accessModes,
 				Resources: corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{},
 				},
@@ -178,6 +226,14 @@ func toPVCs(req router.Request, appInstance *v1.AppInstance) (result []kclient.O
 			if volumeBinding.Size != "" {
 				pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *v1.MustParseResourceQuantity(volumeBinding.Size)
 			}
+
+			if volumeBinding.Class != "" {
+				migration, err := bindMigration(req, appInstance, vol, volumeBinding, &pvc)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, migration...)
+			}
 		} else {
 			if volumeRequest.Class != "" {
 				// Specifically allowing volume classes that are inactive.
@@ -192,12 +248,21 @@ func toPVCs(req router.Request, appInstance *v1.AppInstance) (result []kclient.O
 			if err != nil {
 				return nil, err
 			}
+			migration, pvName, err := accessModeMigration(req, appInstance, vol, volumeBinding, pvName, &pvc)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, migration...)
 			pvc.Spec.VolumeName = pvName
 
 			if volumeRequest.Size == "" {
 				pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *v1.DefaultSize
 			} else {
-				pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *v1.MustParseResourceQuantity(volumeRequest.Size)
+				size, err := nonZeroSize(req, vol, volumeRequest.Size)
+				if err != nil {
+					return nil, err
+				}
+				pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *size
 			}
 		}
 
@@ -206,6 +271,28 @@ func toPVCs(req router.Request, appInstance *v1.AppInstance) (result []kclient.O
 	return
 }
 
+// nonZeroSize parses the requested size of a volume. A size of 0 is replaced by the configured volumeSizeDefault, or
+// rejected if there is none, because provisioners either refuse or misinterpret a PVC requesting 0 bytes.
+func nonZeroSize(req router.Request, vol string, size v1.Quantity) (*resource.Quantity, error) {
+	quantity := v1.MustParseResourceQuantity(size)
+	if !quantity.IsZero() {
+		return quantity, nil
+	}
+
+	cfg, err := config.Get(req.Ctx, req.Client)
+	if err != nil {
+		return nil, err
+	}
+	if *cfg.VolumeSizeDefault == "" {
+		return nil, fmt.Errorf("volume %s has a size of 0, non-ephemeral volumes must request a positive size", vol)
+	}
+	defaultSize, err := resource.ParseQuantity(*cfg.VolumeSizeDefault)
+	if err != nil {
+		return nil, fmt.Errorf("volume %s has a size of 0 and volumeSizeDefault is invalid: %w", vol, err)
+	}
+	return &defaultSize, nil
+}
+
 func volumeLabels(appInstance *v1.AppInstance, volume string, volumeRequest v1.VolumeRequest) map[string]string {
 	labelMap := map[string]string{
 		labels.AcornAppName:      appInstance.Name,
@@ -262,7 +349,7 @@ func addVolumeReferencesForContainer(app *v1.AppInstance, volumeReferences map[v
 		} else if volume.Secret.Name == "" {
 			volumeReferences[volumeReference{name: volume.Volume}] = true
 		} else {
-			volumeReferences[volumeReference{secretName: volume.Secret.Name}] = true
+			volumeReferences[volumeReference{secretName: volume.Secret.Name, mode: volume.Secret.Mode}] = true
 		}
 	}
 
@@ -322,7 +409,39 @@ func secretPodVolName(secretName string) string {
 	return strings.ReplaceAll(name.SafeConcatName("secret-", secretName), ".", "-")
 }
 
-func toVolumes(appInstance *v1.AppInstance, container v1.Container, interpolator *secrets.Interpolator) (result []corev1.Volume, _ error) {
+// maxEphemeralPercentage caps the percentage size of an ephemeral volume, so that the volume can't claim the ephemeral
+// storage the node needs for images, logs and the writable layers of containers
+const maxEphemeralPercentage = 80
+
+// ephemeralSizeLimit resolves the size of an ephemeral volume to its size limit. A percentage is taken of the smallest
+// allocatable ephemeral storage of the nodes, so the limit fits on any node, or of the default volume size if no node
+// reports it.
+func ephemeralSizeLimit(req router.Request, size v1.Quantity) (*resource.Quantity, error) {
+	percentage, ok := size.Percentage()
+	if !ok {
+		return v1.MustParseResourceQuantity(size), nil
+	}
+	percentage = math.Min(percentage, maxEphemeralPercentage)
+
+	var nodes corev1.NodeList
+	if err := req.List(&nodes, &kclient.ListOptions{}); err != nil {
+		return nil, err
+	}
+
+	baseline := v1.DefaultSize
+	found := false
+	for _, node := range nodes.Items {
+		allocatable, ok := node.Status.Allocatable[corev1.ResourceEphemeralStorage]
+		if ok && (!found || allocatable.Cmp(*baseline) < 0) {
+			baseline = &allocatable
+			found = true
+		}
+	}
+
+	return resource.NewQuantity(int64(float64(baseline.Value())*percentage/100), resource.BinarySI), nil
+}
+
+func toVolumes(req router.Request, appInstance *v1.AppInstance, container v1.Container, interpolator *secrets.Interpolator) (result []corev1.Volume, _ error) {
 	volumeReferences := map[volumeReference]bool{}
 	addVolumeReferencesForContainer(appInstance, volumeReferences, container)
 	for _, entry := range typed.Sorted(container.Sidecars) {
@@ -349,11 +468,15 @@ func toVolumes(appInstance *v1.AppInstance, container v1.Container, interpolator
 
 		name, bind := toVolumeName(appInstance, volume.name)
 		if vr, ok := isEphemeral(appInstance, volume.name); ok && !bind {
+			sizeLimit, err := ephemeralSizeLimit(req, vr.Size)
+			if err != nil {
+				return nil, err
+			}
 			result = append(result, corev1.Volume{
 				Name: sanitizeVolumeName(volume.name),
 				VolumeSource: corev1.VolumeSource{
 					EmptyDir: &corev1.EmptyDirVolumeSource{
-						SizeLimit: v1.MustParseResourceQuantity(vr.Size),
+						SizeLimit: sizeLimit,
 					},
 				},
 			})
@@ -9,10 +9,15 @@ import (
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/system"
 	"github.com/acorn-io/baaah/pkg/router/tester"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestVolumeController(t *testing.T) {
@@ -165,3 +170,279 @@ func TestVolumeLabelsAnnotations(t *testing.T) {
 	assert.Contains(t, pvc2.Annotations, "globalfromacornfilea")
 	assert.NotContains(t, pvc2.Annotations, "vol1fromacornfilea")
 }
+
+func TestVolumeLabelsProtectManaged(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Spec: v1.AppInstanceSpec{
+			Labels: []v1.ScopedLabel{
+				{ResourceType: "volume", Key: "cost-center", Value: "1234"},
+				{ResourceType: "volume", Key: labels.AcornAppName, Value: "other-app"},
+			},
+			Image: "image",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "image",
+			},
+			AppSpec: v1.AppSpec{
+				Volumes: map[string]v1.VolumeRequest{
+					"volume1": {
+						Labels: map[string]string{
+							"team":                 "storage",
+							labels.AcornManaged:    "false",
+							labels.AcornVolumeName: "other-volume",
+						},
+						AccessModes: []v1.AccessMode{v1.AccessModeReadWriteOnce},
+					},
+				},
+			},
+		},
+	}, DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pvc *corev1.PersistentVolumeClaim
+	for _, i := range resp.Collected {
+		if i.GetName() == "volume1" {
+			pvc = i.(*corev1.PersistentVolumeClaim)
+		}
+	}
+	if !assert.NotNil(t, pvc) {
+		return
+	}
+
+	// user labels land on the pvc, but can't replace the acorn.io labels that identify it
+	assert.Equal(t, "storage", pvc.Labels["team"])
+	assert.Equal(t, "1234", pvc.Labels["cost-center"])
+	assert.Equal(t, "true", pvc.Labels[labels.AcornManaged])
+	assert.Equal(t, "volume1", pvc.Labels[labels.AcornVolumeName])
+	assert.Equal(t, "app-name", pvc.Labels[labels.AcornAppName])
+	assert.Equal(t, "app-ns", pvc.Labels[labels.AcornAppNamespace])
+}
+
+func TestTranslateAccessModes(t *testing.T) {
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, translateAccessModes(nil))
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{
+		corev1.ReadWriteMany,
+		corev1.ReadOnlyMany,
+		corev1.ReadWriteOncePod,
+		corev1.ReadWriteOncePod,
+	}, translateAccessModes([]v1.AccessMode{
+		v1.AccessModeReadWriteMany,
+		v1.AccessModeReadOnlyMany,
+		v1.AccessModeReadWriteOncePod,
+		"rwop",
+	}))
+}
+
+func readWriteOncePodApp() *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Spec: v1.AppInstanceSpec{
+			Image: "image",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "image",
+			},
+			AppSpec: v1.AppSpec{
+				Volumes: map[string]v1.VolumeRequest{
+					"data": {
+						AccessModes: []v1.AccessMode{"rwop"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func node(name, kubeletVersion string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{
+				KubeletVersion: kubeletVersion,
+			},
+		},
+	}
+}
+
+func TestReadWriteOncePodVolume(t *testing.T) {
+	h := tester.Harness{
+		Scheme:   scheme.Scheme,
+		Existing: []kclient.Object{node("node1", "v1.27.3+k3s1")},
+	}
+	resp, err := h.InvokeFunc(t, readWriteOncePodApp(), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pvc *corev1.PersistentVolumeClaim
+	for _, obj := range resp.Collected {
+		if obj.GetName() == "data" {
+			pvc = obj.(*corev1.PersistentVolumeClaim)
+		}
+	}
+	if assert.NotNil(t, pvc) {
+		assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod}, pvc.Spec.AccessModes)
+	}
+}
+
+func TestReadWriteOncePodUnsupportedCluster(t *testing.T) {
+	h := tester.Harness{
+		Scheme:   scheme.Scheme,
+		Existing: []kclient.Object{node("node1", "v1.27.3"), node("node2", "v1.26.5")},
+	}
+	_, err := h.InvokeFunc(t, readWriteOncePodApp(), DeploySpec)
+	assert.EqualError(t, err, "volume data uses access mode readWriteOncePod which requires Kubernetes 1.27.0 or newer, but node node2 is running v1.26.5")
+}
+
+func zeroSizeVolumeApp() *v1.AppInstance {
+	app := readWriteOncePodApp()
+	app.Status.AppSpec.Volumes = map[string]v1.VolumeRequest{
+		"data": {
+			Size: "0",
+		},
+	}
+	return app
+}
+
+func TestZeroSizeVolume(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	_, err := h.InvokeFunc(t, zeroSizeVolumeApp(), DeploySpec)
+	assert.EqualError(t, err, "volume data has a size of 0, non-ephemeral volumes must request a positive size")
+}
+
+func TestZeroSizeVolumeDefault(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      system.ConfigName,
+					Namespace: system.Namespace,
+				},
+				Data: map[string]string{
+					"config": `{"volumeSizeDefault": "20G"}`,
+				},
+			},
+		},
+	}
+	resp, err := h.InvokeFunc(t, zeroSizeVolumeApp(), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pvc *corev1.PersistentVolumeClaim
+	for _, obj := range resp.Collected {
+		if obj.GetName() == "data" {
+			pvc = obj.(*corev1.PersistentVolumeClaim)
+		}
+	}
+	if assert.NotNil(t, pvc) {
+		assert.Equal(t, "20G", pvc.Spec.Resources.Requests.Storage().String())
+	}
+}
+
+func ephemeralStorageNode(name, allocatable string) *corev1.Node {
+	n := node(name, "v1.27.3")
+	n.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceEphemeralStorage: resource.MustParse(allocatable),
+	}
+	return n
+}
+
+func TestEphemeralSizeLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  v1.Quantity
+		nodes []kclient.Object
+		want  string
+	}{
+		{
+			name: "fixed size",
+			size: "5G",
+			want: "5G",
+		},
+		{
+			name:  "percentage of smallest node",
+			size:  "25%",
+			nodes: []kclient.Object{ephemeralStorageNode("large", "200Gi"), ephemeralStorageNode("small", "100Gi")},
+			want:  "25Gi",
+		},
+		{
+			name:  "percentage is capped",
+			size:  "100%",
+			nodes: []kclient.Object{ephemeralStorageNode("node1", "100Gi")},
+			want:  "80Gi",
+		},
+		{
+			name:  "percentage of default size without node capacity",
+			size:  "50%",
+			nodes: []kclient.Object{node("node1", "v1.27.3")},
+			want:  "5G",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tester.NewRequest(t, scheme.Scheme, readWriteOncePodApp(), tt.nodes...)
+			sizeLimit, err := ephemeralSizeLimit(req, tt.size)
+			require.NoError(t, err)
+			assert.Zero(t, sizeLimit.Cmp(resource.MustParse(tt.want)), "got %s, want %s", sizeLimit, tt.want)
+		})
+	}
+}
+
+func TestEphemeralPercentageVolume(t *testing.T) {
+	app := readWriteOncePodApp()
+	app.Status.AppSpec.Volumes = map[string]v1.VolumeRequest{
+		"scratch": {
+			Class: v1.VolumeRequestTypeEphemeral,
+			Size:  "10%",
+		},
+	}
+	app.Status.AppSpec.Containers = map[string]v1.Container{
+		"web": {
+			Image: "image",
+			Dirs: map[string]v1.VolumeMount{
+				"/scratch": {Volume: "scratch"},
+			},
+		},
+	}
+
+	h := tester.Harness{
+		Scheme:   scheme.Scheme,
+		Existing: []kclient.Object{ephemeralStorageNode("node1", "50Gi")},
+	}
+	resp, err := h.InvokeFunc(t, app, DeploySpec)
+	require.NoError(t, err)
+
+	var dep *appsv1.Deployment
+	for _, obj := range resp.Collected {
+		if d, ok := obj.(*appsv1.Deployment); ok && d.Name == "web" {
+			dep = d
+		}
+	}
+	require.NotNil(t, dep)
+	require.Len(t, dep.Spec.Template.Spec.Volumes, 1)
+	emptyDir := dep.Spec.Template.Spec.Volumes[0].EmptyDir
+	require.NotNil(t, emptyDir)
+	assert.Equal(t, "5Gi", emptyDir.SizeLimit.String())
+}
@@ -0,0 +1,260 @@
+package appdefinition
+
+import (
+	"fmt"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/acorn-io/baaah/pkg/router"
+	name2 "github.com/rancher/wrangler/pkg/name"
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	migrationSourcePath = "/source"
+	migrationTargetPath = "/target"
+)
+
+func migrationSourceName(volume string) string {
+	return name2.SafeConcatName(volume, "migrate", "source")
+}
+
+func migrationJobName(volume string) string {
+	return name2.SafeConcatName(volume, "migrate")
+}
+
+// bindMigration checks whether a bound volume is being moved to a different volume class. If it is, and the binding
+// opted in with migrate, pvc is changed to provision a new volume in the target class and the objects needed to copy
+// the data from the existing volume are returned. Without the opt-in, binding across classes is an error.
+func bindMigration(req router.Request, appInstance *v1.AppInstance, vol string, volumeBinding v1.VolumeBinding, pvc *corev1.PersistentVolumeClaim) ([]kclient.Object, error) {
+	pv := new(corev1.PersistentVolume)
+	if err := req.Get(pv, "", volumeBinding.Volume); apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	sourceClass, targetClass := pv.Labels[labels.AcornVolumeClass], pvc.Labels[labels.AcornVolumeClass]
+	if sourceClass == "" || sourceClass == targetClass {
+		return nil, nil
+	}
+
+	if !volumeBinding.Migrate {
+		return nil, fmt.Errorf("%s is bound to volume %s of volume class %s, binding it to volume class %s requires migrate=true",
+			vol, volumeBinding.Volume, sourceClass, targetClass)
+	}
+
+	sourceSize := *v1.MinSize
+	if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		sourceSize = capacity
+	}
+
+	// The new volume is provisioned by the target class, so it must not be bound to the existing one
+	pvc.Spec.VolumeName = ""
+	if volumeBinding.Size == "" {
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = sourceSize
+	}
+
+	sourceLabels := labels.Merge(pvc.Labels, map[string]string{
+		labels.AcornVolumeClass: sourceClass,
+	})
+	return copyVolume(req, appInstance, vol, pv, sourceLabels, sourceSize, pvc.Name)
+}
+
+// copyVolume returns a claim that binds the existing volume pv and a job that copies its contents into the claim
+// targetClaim.
+func copyVolume(req router.Request, appInstance *v1.AppInstance, vol string, pv *corev1.PersistentVolume, sourceLabels map[string]string, sourceSize resource.Quantity, targetClaim string) ([]kclient.Object, error) {
+	source := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      migrationSourceName(vol),
+			Namespace: appInstance.Status.Namespace,
+			Labels:    sourceLabels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      pv.Spec.AccessModes,
+			VolumeName:       pv.Name,
+			StorageClassName: &pv.Spec.StorageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: sourceSize,
+				},
+			},
+		},
+	}
+
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyOnFailure,
+		Containers: []corev1.Container{
+			{
+				Name:            "migrate",
+				Image:           system.DefaultImage(),
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				Command:         []string{"sh", "-c", fmt.Sprintf("cp -a %s/. %s/", migrationSourcePath, migrationTargetPath)},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      "source",
+						MountPath: migrationSourcePath,
+						ReadOnly:  true,
+					},
+					{
+						Name:      "target",
+						MountPath: migrationTargetPath,
+					},
+				},
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: "source",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: source.Name,
+						ReadOnly:  true,
+					},
+				},
+			},
+			{
+				Name: "target",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: targetClaim,
+					},
+				},
+			},
+		},
+	}
+	if err := applyPodSecurity(req, &podSpec); err != nil {
+		return nil, err
+	}
+
+	jobLabels := labels.ManagedByApp(appInstance.Namespace, appInstance.Name, labels.AcornVolumeName, vol)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      migrationJobName(vol),
+			Namespace: appInstance.Status.Namespace,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+
+	return []kclient.Object{source, job}, nil
+}
+
+// accessModeMigration checks whether the access modes requested for a volume differ from those of the existing volume
+// pvName that backs it. The access modes of a claim can't be changed once it is created, so the volume has to be
+// recreated for the change to take effect, which requires the migrate opt-in. With it, the existing volume is retained
+// and its claim deleted. Once the claim is gone, pvc provisions a new volume and the objects that copy the data of the
+// existing volume into it are returned until the copy is done. The returned name is the volume pvc should bind to.
+func accessModeMigration(req router.Request, appInstance *v1.AppInstance, vol string, volumeBinding v1.VolumeBinding, pvName string, pvc *corev1.PersistentVolumeClaim) ([]kclient.Object, string, error) {
+	source := new(corev1.PersistentVolumeClaim)
+	if err := req.Get(source, appInstance.Status.Namespace, migrationSourceName(vol)); err == nil {
+		// A copy that was started keeps going until its job is done, even after the new volume is bound
+		return continueCopy(req, appInstance, vol, source, pvName, pvc)
+	} else if !apierrors.IsNotFound(err) {
+		return nil, "", err
+	}
+
+	if pvName == "" {
+		return nil, "", nil
+	}
+	pv := new(corev1.PersistentVolume)
+	if err := req.Get(pv, "", pvName); apierrors.IsNotFound(err) {
+		return nil, pvName, nil
+	} else if err != nil {
+		return nil, "", err
+	}
+	if sameAccessModes(pv.Spec.AccessModes, pvc.Spec.AccessModes) {
+		return nil, pvName, nil
+	}
+
+	if !volumeBinding.Migrate {
+		return nil, "", fmt.Errorf("%s has access modes %v, access modes can't be changed once a volume is created so changing them to %v requires "+
+			"recreating the volume with migrate=true (e.g. -v %s,migrate=true), which copies its data into a new volume",
+			vol, pv.Spec.AccessModes, pvc.Spec.AccessModes, vol)
+	}
+
+	// The data must outlive the claim that is deleted below
+	if pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+		pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+		if err := req.Client.Update(req.Ctx, pv); err != nil {
+			return nil, "", err
+		}
+	}
+
+	existing := new(corev1.PersistentVolumeClaim)
+	if err := req.Get(existing, appInstance.Status.Namespace, pvc.Name); err == nil && existing.Spec.VolumeName == pv.Name {
+		if existing.DeletionTimestamp.IsZero() {
+			if err := req.Client.Delete(req.Ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+				return nil, "", err
+			}
+		}
+		return nil, "", fmt.Errorf("waiting for the claim of %s to be deleted so that it can be recreated with access modes %v", vol, pvc.Spec.AccessModes)
+	} else if err != nil && !apierrors.IsNotFound(err) {
+		return nil, "", err
+	}
+
+	// The volume still refers to the deleted claim, which keeps it from being bound by the claim that copies it
+	if pv.Spec.ClaimRef != nil && pv.Spec.ClaimRef.Name != migrationSourceName(vol) {
+		pv.Spec.ClaimRef = nil
+		if err := req.Client.Update(req.Ctx, pv); err != nil {
+			return nil, "", err
+		}
+	}
+
+	sourceSize := *v1.MinSize
+	if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		sourceSize = capacity
+	}
+	objs, err := copyVolume(req, appInstance, vol, pv, pvc.Labels, sourceSize, pvc.Name)
+	return objs, "", err
+}
+
+// continueCopy returns the objects of a copy started by accessModeMigration until its job has succeeded, after which
+// they are pruned. The original volume is retained either way.
+func continueCopy(req router.Request, appInstance *v1.AppInstance, vol string, source *corev1.PersistentVolumeClaim, pvName string, pvc *corev1.PersistentVolumeClaim) ([]kclient.Object, string, error) {
+	if pvName == source.Spec.VolumeName {
+		// the original volume is found by its labels while the new one is not bound yet
+		pvName = ""
+	}
+
+	job := new(batchv1.Job)
+	if err := req.Get(job, appInstance.Status.Namespace, migrationJobName(vol)); err == nil && job.Status.Succeeded > 0 {
+		return nil, pvName, nil
+	} else if err != nil && !apierrors.IsNotFound(err) {
+		return nil, "", err
+	}
+
+	pv := new(corev1.PersistentVolume)
+	if err := req.Get(pv, "", source.Spec.VolumeName); err != nil {
+		return nil, "", err
+	}
+
+	objs, err := copyVolume(req, appInstance, vol, pv, source.Labels, source.Spec.Resources.Requests[corev1.ResourceStorage], pvc.Name)
+	return objs, pvName, err
+}
+
+func sameAccessModes(a, b []corev1.PersistentVolumeAccessMode) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, accessMode := range a {
+		if !slices.Contains(b, accessMode) {
+			return false
+		}
+	}
+	return true
+}
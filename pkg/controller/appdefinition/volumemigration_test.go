@@ -0,0 +1,274 @@
+package appdefinition
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	adminv1 "github.com/acorn-io/acorn/pkg/apis/internal.admin.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/acorn-io/baaah/pkg/uncached"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func migrationHarness() tester.Harness {
+	return tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			&adminv1.ClusterVolumeClassInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "fast",
+				},
+				StorageClassName: "fast-sc",
+			},
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv-data",
+					Labels: map[string]string{
+						labels.AcornVolumeClass: "slow",
+					},
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: "slow-sc",
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Capacity: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("5Gi"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func migrationApp(migrate bool) *v1.AppInstance {
+	app := readWriteOncePodApp()
+	app.Spec.Volumes = []v1.VolumeBinding{
+		{
+			Volume:  "pv-data",
+			Target:  "data",
+			Class:   "fast",
+			Migrate: migrate,
+		},
+	}
+	app.Status.AppSpec.Volumes = map[string]v1.VolumeRequest{
+		"data": {},
+	}
+	return app
+}
+
+func TestBindMigrationRequiresOptIn(t *testing.T) {
+	h := migrationHarness()
+	_, err := h.InvokeFunc(t, migrationApp(false), DeploySpec)
+	assert.EqualError(t, err, "data is bound to volume pv-data of volume class slow, binding it to volume class fast requires migrate=true")
+}
+
+func TestBindMigration(t *testing.T) {
+	h := migrationHarness()
+	resp, err := h.InvokeFunc(t, migrationApp(true), DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		target, source *corev1.PersistentVolumeClaim
+		job            *batchv1.Job
+	)
+	for _, obj := range resp.Collected {
+		switch obj.GetName() {
+		case bindName("data"):
+			target = obj.(*corev1.PersistentVolumeClaim)
+		case migrationSourceName("data"):
+			source = obj.(*corev1.PersistentVolumeClaim)
+		case migrationJobName("data"):
+			job = obj.(*batchv1.Job)
+		}
+	}
+	require.NotNil(t, target)
+	require.NotNil(t, source)
+	require.NotNil(t, job)
+
+	// The app volume is a new volume in the target class, sized like the original
+	assert.Empty(t, target.Spec.VolumeName)
+	assert.Equal(t, "fast-sc", *target.Spec.StorageClassName)
+	assert.Equal(t, "5Gi", target.Spec.Resources.Requests.Storage().String())
+
+	// The original volume is bound by its own claim so that it can be copied
+	assert.Equal(t, "pv-data", source.Spec.VolumeName)
+	assert.Equal(t, "slow-sc", *source.Spec.StorageClassName)
+	assert.Equal(t, "slow", source.Labels[labels.AcornVolumeClass])
+
+	podSpec := job.Spec.Template.Spec
+	require.Len(t, podSpec.Volumes, 2)
+	assert.Equal(t, source.Name, podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.True(t, podSpec.Volumes[0].PersistentVolumeClaim.ReadOnly)
+	assert.Equal(t, target.Name, podSpec.Volumes[1].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, []string{"sh", "-c", "cp -a /source/. /target/"}, podSpec.Containers[0].Command)
+}
+
+func TestBindSameClassNoMigration(t *testing.T) {
+	h := migrationHarness()
+	app := migrationApp(false)
+	app.Spec.Volumes[0].Class = "slow"
+	h.Existing = append(h.Existing, &adminv1.ClusterVolumeClassInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slow",
+		},
+		StorageClassName: "slow-sc",
+	})
+
+	resp, err := h.InvokeFunc(t, app, DeploySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, obj := range resp.Collected {
+		assert.NotEqual(t, migrationJobName("data"), obj.GetName())
+	}
+}
+
+// uncachedClient lets the fake client serve the uncached reads of the controller
+type uncachedClient struct {
+	kclient.Client
+}
+
+func (c uncachedClient) Get(ctx context.Context, key kclient.ObjectKey, obj kclient.Object) error {
+	if holder, ok := obj.(*uncached.Holder); ok {
+		obj = holder.Object
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c uncachedClient) List(ctx context.Context, list kclient.ObjectList, opts ...kclient.ListOption) error {
+	if holder, ok := list.(*uncached.HolderList); ok {
+		list = holder.ObjectList
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+// accessModeApp requests the data volume as ReadWriteMany, while accessModeObjects hold it as ReadWriteOnce
+func accessModeApp(migrate bool) *v1.AppInstance {
+	app := readWriteOncePodApp()
+	app.Spec.Volumes = []v1.VolumeBinding{
+		{
+			Target:  "data",
+			Migrate: migrate,
+		},
+	}
+	app.Status.AppSpec.Volumes = map[string]v1.VolumeRequest{
+		"data": {
+			AccessModes: []v1.AccessMode{v1.AccessModeReadWriteMany},
+		},
+	}
+	return app
+}
+
+func accessModeObjects() []kclient.Object {
+	return []kclient.Object{
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "data",
+				Namespace: "app-target-ns",
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				VolumeName:  "pv-data",
+			},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pv-data",
+				Labels: map[string]string{
+					labels.AcornManaged:      "true",
+					labels.AcornAppName:      "app-name",
+					labels.AcornAppNamespace: "app-ns",
+					labels.AcornVolumeName:   "data",
+				},
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				StorageClassName:              "local",
+				AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				ClaimRef: &corev1.ObjectReference{
+					Name:      "data",
+					Namespace: "app-target-ns",
+				},
+				Capacity: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("5Gi"),
+				},
+			},
+		},
+	}
+}
+
+func TestAccessModeChangeRequiresOptIn(t *testing.T) {
+	h := tester.Harness{
+		Scheme:   scheme.Scheme,
+		Existing: accessModeObjects(),
+	}
+	_, err := h.InvokeFunc(t, accessModeApp(false), DeploySpec)
+	assert.EqualError(t, err, "data has access modes [ReadWriteOnce], access modes can't be changed once a volume is created so changing them "+
+		"to [ReadWriteMany] requires recreating the volume with migrate=true (e.g. -v data,migrate=true), which copies its data into a new volume")
+}
+
+func TestAccessModeMigration(t *testing.T) {
+	ctx := context.Background()
+	c := uncachedClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(accessModeObjects()...).Build()}
+	app := accessModeApp(true)
+	req := router.Request{Ctx: ctx, Client: c, Object: app}
+
+	// The existing claim is deleted first, keeping its volume
+	_, err := toPVCs(req, app)
+	assert.EqualError(t, err, "waiting for the claim of data to be deleted so that it can be recreated with access modes [ReadWriteMany]")
+
+	pv := new(corev1.PersistentVolume)
+	require.NoError(t, c.Get(ctx, router.Key("", "pv-data"), pv))
+	assert.Equal(t, corev1.PersistentVolumeReclaimRetain, pv.Spec.PersistentVolumeReclaimPolicy)
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, router.Key("app-target-ns", "data"), new(corev1.PersistentVolumeClaim))))
+
+	// Then a new volume is provisioned and the data of the old one copied into it
+	objs, err := toPVCs(req, app)
+	require.NoError(t, err)
+	require.Len(t, objs, 3)
+
+	source := objs[0].(*corev1.PersistentVolumeClaim)
+	assert.Equal(t, migrationSourceName("data"), source.Name)
+	assert.Equal(t, "pv-data", source.Spec.VolumeName)
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, source.Spec.AccessModes)
+	assert.Equal(t, "5Gi", source.Spec.Resources.Requests.Storage().String())
+
+	job := objs[1].(*batchv1.Job)
+	assert.Equal(t, migrationJobName("data"), job.Name)
+	assert.Equal(t, "data", job.Spec.Template.Spec.Volumes[1].PersistentVolumeClaim.ClaimName)
+
+	target := objs[2].(*corev1.PersistentVolumeClaim)
+	assert.Equal(t, "data", target.Name)
+	assert.Empty(t, target.Spec.VolumeName)
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, target.Spec.AccessModes)
+
+	require.NoError(t, c.Get(ctx, router.Key("", "pv-data"), pv))
+	assert.Nil(t, pv.Spec.ClaimRef)
+
+	// The copy keeps going until its job is done
+	require.NoError(t, c.Create(ctx, source))
+	require.NoError(t, c.Create(ctx, job))
+	objs, err = toPVCs(req, app)
+	require.NoError(t, err)
+	assert.Len(t, objs, 3)
+
+	job.Status.Succeeded = 1
+	require.NoError(t, c.Status().Update(ctx, job))
+	objs, err = toPVCs(req, app)
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Empty(t, objs[0].(*corev1.PersistentVolumeClaim).Spec.VolumeName)
+}
@@ -2,12 +2,14 @@ package controller
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	adminv1 "github.com/acorn-io/acorn/pkg/apis/internal.admin.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/autoupgrade"
 	"github.com/acorn-io/acorn/pkg/config"
+	"github.com/acorn-io/acorn/pkg/controller/health"
 	"github.com/acorn-io/acorn/pkg/crds"
 	"github.com/acorn-io/acorn/pkg/dns"
 	"github.com/acorn-io/acorn/pkg/imagesystem"
@@ -19,6 +21,7 @@ import (
 	"github.com/acorn-io/baaah/pkg/router"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -31,9 +34,14 @@ type Controller struct {
 	client client.Client
 	Scheme *runtime.Scheme
 	apply  apply.Apply
+	queues *health.QueueDepths
 }
 
 func New() (*Controller, error) {
+	// The metrics provider has to be set before the router creates its work queues
+	queues := health.NewQueueDepths()
+	workqueue.SetProvider(queues)
+
 	router, err := baaah.DefaultRouter("acorn-controller", scheme.Scheme)
 	if err != nil {
 		return nil, err
@@ -56,6 +64,7 @@ func New() (*Controller, error) {
 		return nil, err
 	}
 
+	// The backend of the router reconciles each kind on 5 workers, so the handlers must be safe to call concurrently
 	routes(router, registryTransport)
 
 	return &Controller{
@@ -63,9 +72,15 @@ func New() (*Controller, error) {
 		client: client,
 		Scheme: scheme.Scheme,
 		apply:  apply,
+		queues: queues,
 	}, nil
 }
 
+// Health returns a handler reporting the reconcile backlog of the controller
+func (c *Controller) Health() http.Handler {
+	return health.NewBacklog(c.Router.Backend(), c.queues)
+}
+
 func (c *Controller) Start(ctx context.Context) error {
 	if err := crds.Create(ctx, c.Scheme, v1.SchemeGroupVersion); err != nil {
 		return err
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/controller/secrets"
+	"github.com/sirupsen/logrus"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Status is the reconcile backlog reported by the health endpoint
+type Status struct {
+	QueueDepth        int            `json:"queueDepth"`
+	Queues            map[string]int `json:"queues,omitempty"`
+	PendingSecretApps int            `json:"pendingSecretApps"`
+}
+
+// Backlog serves the reconcile backlog of the controller over HTTP
+type Backlog struct {
+	client      kclient.Reader
+	queues      *QueueDepths
+	pendingApps func() []string
+}
+
+func NewBacklog(client kclient.Reader, queues *QueueDepths) *Backlog {
+	return &Backlog{
+		client:      client,
+		queues:      queues,
+		pendingApps: secrets.PendingApps,
+	}
+}
+
+// Status computes the current backlog. Apps recorded as pending on secrets are only counted if they still exist.
+func (b *Backlog) Status(ctx context.Context) (Status, error) {
+	apps := &v1.AppInstanceList{}
+	if err := b.client.List(ctx, apps); err != nil {
+		return Status{}, err
+	}
+
+	existing := make(map[string]bool, len(apps.Items))
+	for _, app := range apps.Items {
+		existing[app.Namespace+"/"+app.Name] = true
+	}
+
+	result := Status{
+		QueueDepth: b.queues.Total(),
+		Queues:     b.queues.Depths(),
+	}
+	for _, key := range b.pendingApps() {
+		if existing[key] {
+			result.PendingSecretApps++
+		}
+	}
+	return result, nil
+}
+
+func (b *Backlog) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	status, err := b.Status(req.Context())
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(status); err != nil {
+		logrus.Errorf("failed to write health status: %v", err)
+	}
+}
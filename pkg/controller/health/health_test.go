@@ -0,0 +1,54 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBacklog(t *testing.T) {
+	queues := NewQueueDepths()
+	apps := queues.NewDepthMetric("acorn-controller/AppInstance")
+	secrets := queues.NewDepthMetric("acorn-controller/Secret")
+	for i := 0; i < 3; i++ {
+		apps.Inc()
+	}
+	apps.Dec()
+	secrets.Inc()
+
+	backlog := &Backlog{
+		client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			&v1.AppInstance{ObjectMeta: metav1.ObjectMeta{Name: "blocked", Namespace: "acorn"}},
+			&v1.AppInstance{ObjectMeta: metav1.ObjectMeta{Name: "blocked", Namespace: "other"}},
+			&v1.AppInstance{ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "acorn"}},
+		).Build(),
+		queues: queues,
+		pendingApps: func() []string {
+			// acorn/deleted no longer exists and is not counted
+			return []string{"acorn/blocked", "acorn/deleted", "other/blocked"}
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	backlog.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var status Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, Status{
+		QueueDepth: 3,
+		Queues: map[string]int{
+			"acorn-controller/AppInstance": 2,
+			"acorn-controller/Secret":      1,
+		},
+		PendingSecretApps: 2,
+	}, status)
+}
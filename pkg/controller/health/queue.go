@@ -0,0 +1,93 @@
+package health
+
+import (
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// QueueDepths is a workqueue.MetricsProvider that keeps track of the number of items waiting in every named work
+// queue. It must be registered with workqueue.SetProvider before the controller creates its queues, and only the
+// first provider registered in a process takes effect.
+type QueueDepths struct {
+	lock   sync.Mutex
+	depths map[string]int
+}
+
+func NewQueueDepths() *QueueDepths {
+	return &QueueDepths{
+		depths: map[string]int{},
+	}
+}
+
+// Depths returns the number of items waiting in each queue, keyed by queue name
+func (q *QueueDepths) Depths() map[string]int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	result := make(map[string]int, len(q.depths))
+	for name, depth := range q.depths {
+		result[name] = depth
+	}
+	return result
+}
+
+// Total returns the number of items waiting across all queues
+func (q *QueueDepths) Total() (result int) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for _, depth := range q.depths {
+		result += depth
+	}
+	return result
+}
+
+func (q *QueueDepths) add(name string, delta int) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.depths[name] += delta
+}
+
+func (q *QueueDepths) NewDepthMetric(name string) workqueue.GaugeMetric {
+	q.add(name, 0)
+	return depthGauge{name: name, queues: q}
+}
+
+func (q *QueueDepths) NewAddsMetric(string) workqueue.CounterMetric {
+	return noopMetric{}
+}
+
+func (q *QueueDepths) NewLatencyMetric(string) workqueue.HistogramMetric {
+	return noopMetric{}
+}
+
+func (q *QueueDepths) NewWorkDurationMetric(string) workqueue.HistogramMetric {
+	return noopMetric{}
+}
+
+func (q *QueueDepths) NewUnfinishedWorkSecondsMetric(string) workqueue.SettableGaugeMetric {
+	return noopMetric{}
+}
+
+func (q *QueueDepths) NewLongestRunningProcessorSecondsMetric(string) workqueue.SettableGaugeMetric {
+	return noopMetric{}
+}
+
+func (q *QueueDepths) NewRetriesMetric(string) workqueue.CounterMetric {
+	return noopMetric{}
+}
+
+type depthGauge struct {
+	name   string
+	queues *QueueDepths
+}
+
+func (d depthGauge) Inc() { d.queues.add(d.name, 1) }
+func (d depthGauge) Dec() { d.queues.add(d.name, -1) }
+
+type noopMetric struct{}
+
+func (noopMetric) Inc()            {}
+func (noopMetric) Observe(float64) {}
+func (noopMetric) Set(float64)     {}
@@ -0,0 +1,101 @@
+package namespace
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// TerminatingThreshold is how long a namespace can be terminating before it is reported as stuck
+const TerminatingThreshold = 5 * time.Minute
+
+// blockingConditions are the conditions the namespace controller sets on a terminating namespace when it can't
+// delete its content, with messages naming the remaining resources and finalizers
+var blockingConditions = []corev1.NamespaceConditionType{
+	corev1.NamespaceDeletionDiscoveryFailure,
+	corev1.NamespaceDeletionGVParsingFailure,
+	corev1.NamespaceDeletionContentFailure,
+	corev1.NamespaceContentRemaining,
+	corev1.NamespaceFinalizersRemaining,
+}
+
+// TerminatingFor returns how long the namespace has been terminating, and false if it isn't terminating
+func TerminatingFor(ns *corev1.Namespace) (time.Duration, bool) {
+	if ns.Status.Phase != corev1.NamespaceTerminating || ns.DeletionTimestamp == nil {
+		return 0, false
+	}
+	return time.Since(ns.DeletionTimestamp.Time), true
+}
+
+// StuckTerminatingError returns an error describing what blocks the deletion of a namespace that has been
+// terminating for longer than TerminatingThreshold, or nil if the namespace isn't stuck.
+func StuckTerminatingError(ns *corev1.Namespace) error {
+	terminatingFor, terminating := TerminatingFor(ns)
+	if !terminating || terminatingFor < TerminatingThreshold {
+		return nil
+	}
+	// the deletion time is reported rather than the duration, so the error doesn't change on every reconcile
+	return fmt.Errorf("namespace [%s] has been terminating since %s, blocked by: %s", ns.Name,
+		ns.DeletionTimestamp.UTC().Format(time.RFC3339), strings.Join(TerminatingBlockers(ns), "; "))
+}
+
+// TerminatingBlockers describes what keeps a terminating namespace from being deleted: the finalizers of the
+// namespace itself and the content the namespace controller is still waiting on
+func TerminatingBlockers(ns *corev1.Namespace) (result []string) {
+	var finalizers []string
+	for _, finalizer := range ns.Spec.Finalizers {
+		finalizers = append(finalizers, string(finalizer))
+	}
+	finalizers = append(finalizers, ns.Finalizers...)
+	if len(finalizers) > 0 {
+		result = append(result, fmt.Sprintf("finalizers %v", finalizers))
+	}
+
+	for _, conditionType := range blockingConditions {
+		for _, cond := range ns.Status.Conditions {
+			if cond.Type == conditionType && cond.Status == corev1.ConditionTrue && cond.Message != "" {
+				result = append(result, cond.Message)
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		result = append(result, "unknown, check the conditions of the namespace")
+	}
+	return
+}
+
+// ReportStuckTerminating warns about the namespaces of deleted apps that are stuck terminating, since there is no
+// app left to report it on.
+func ReportStuckTerminating(req router.Request, resp router.Response) error {
+	ns := req.Object.(*corev1.Namespace)
+	if ns.Labels[labels.AcornAppName] == "" || ns.Labels[labels.AcornAppNamespace] == "" {
+		return nil
+	}
+
+	terminatingFor, terminating := TerminatingFor(ns)
+	if !terminating {
+		return nil
+	}
+	if terminatingFor < TerminatingThreshold {
+		resp.RetryAfter(TerminatingThreshold - terminatingFor)
+		return nil
+	}
+
+	// the status of an app that still exists reports its namespace being stuck
+	err := req.Client.Get(req.Ctx, router.Key(ns.Labels[labels.AcornAppNamespace], ns.Labels[labels.AcornAppName]), &v1.AppInstance{})
+	if err == nil || !apierror.IsNotFound(err) {
+		return err
+	}
+
+	logrus.Warnf("namespace of app [%s/%s] is stuck terminating: %v", ns.Labels[labels.AcornAppNamespace],
+		ns.Labels[labels.AcornAppName], StuckTerminatingError(ns))
+	return nil
+}
@@ -0,0 +1,82 @@
+package networkpolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/acorn-io/acorn/pkg/labels"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Describe returns a one-line description of the traffic a NetworkPolicy allows, such as
+// "allows ingress from [project acorn, namespace kube-system] on [tcp/8080]".
+func Describe(netPol *networkingv1.NetworkPolicy) string {
+	if len(netPol.Spec.Ingress) == 0 {
+		return "denies all ingress"
+	}
+
+	rules := make([]string, 0, len(netPol.Spec.Ingress))
+	for _, rule := range netPol.Spec.Ingress {
+		from := "anywhere"
+		if len(rule.From) > 0 {
+			peers := make([]string, 0, len(rule.From))
+			for _, peer := range rule.From {
+				peers = append(peers, describePeer(peer))
+			}
+			from = "[" + strings.Join(peers, ", ") + "]"
+		}
+
+		on := "all ports"
+		if len(rule.Ports) > 0 {
+			ports := make([]string, 0, len(rule.Ports))
+			for _, port := range rule.Ports {
+				ports = append(ports, describePort(port))
+			}
+			on = "[" + strings.Join(ports, ", ") + "]"
+		}
+
+		rules = append(rules, fmt.Sprintf("allows ingress from %s on %s", from, on))
+	}
+	return strings.Join(rules, "; ")
+}
+
+func describePeer(peer networkingv1.NetworkPolicyPeer) string {
+	if peer.IPBlock != nil {
+		if len(peer.IPBlock.Except) > 0 {
+			return fmt.Sprintf("%s except [%s]", peer.IPBlock.CIDR, strings.Join(peer.IPBlock.Except, ", "))
+		}
+		return peer.IPBlock.CIDR
+	}
+
+	var result []string
+	if peer.NamespaceSelector != nil {
+		matchLabels := peer.NamespaceSelector.MatchLabels
+		switch {
+		case len(matchLabels) == 0 && len(peer.NamespaceSelector.MatchExpressions) == 0:
+			result = append(result, "all namespaces")
+		case len(matchLabels) == 1 && matchLabels[labels.AcornAppNamespace] != "":
+			result = append(result, "project "+matchLabels[labels.AcornAppNamespace])
+		case len(matchLabels) == 1 && matchLabels["kubernetes.io/metadata.name"] != "":
+			result = append(result, "namespace "+matchLabels["kubernetes.io/metadata.name"])
+		default:
+			result = append(result, "namespaces "+metav1.FormatLabelSelector(peer.NamespaceSelector))
+		}
+	}
+	if peer.PodSelector != nil {
+		result = append(result, "pods "+metav1.FormatLabelSelector(peer.PodSelector))
+	}
+	return strings.Join(result, " ")
+}
+
+func describePort(port networkingv1.NetworkPolicyPort) string {
+	protocol := corev1.ProtocolTCP
+	if port.Protocol != nil {
+		protocol = *port.Protocol
+	}
+	if port.Port == nil {
+		return strings.ToLower(string(protocol))
+	}
+	return strings.ToLower(string(protocol)) + "/" + port.Port.String()
+}
@@ -0,0 +1,114 @@
+package networkpolicy
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/baaah/pkg/router"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type DriftStatus string
+
+const (
+	DriftStatusInSync   = DriftStatus("in-sync")
+	DriftStatusMissing  = DriftStatus("missing")
+	DriftStatusModified = DriftStatus("modified")
+)
+
+// Drift is the result of comparing a NetworkPolicy that should exist for an app with the one in the cluster.
+type Drift struct {
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Status    DriftStatus `json:"status"`
+}
+
+// collector is a router.Response that only keeps the objects produced by a handler
+type collector struct {
+	objects []kclient.Object
+}
+
+func (c *collector) DisablePrune()                 {}
+func (c *collector) RetryAfter(time.Duration)      {}
+func (c *collector) Objects(obj ...kclient.Object) { c.objects = append(c.objects, obj...) }
+
+// Expected computes the NetworkPolicies the controller would create for the app, its ingresses and its
+// LoadBalancer services, using the same handlers the controller runs.
+func Expected(ctx context.Context, c kclient.Client, app *v1.AppInstance) ([]*networkingv1.NetworkPolicy, error) {
+	resp := &collector{}
+	if err := NetworkPolicyForApp(router.Request{Ctx: ctx, Client: c, Object: app}, resp); err != nil {
+		return nil, err
+	}
+
+	selector := klabels.SelectorFromSet(map[string]string{
+		labels.AcornAppName:      app.Name,
+		labels.AcornAppNamespace: app.Namespace,
+		labels.AcornManaged:      "true",
+	})
+
+	ingresses := &networkingv1.IngressList{}
+	if err := c.List(ctx, ingresses, &kclient.ListOptions{
+		Namespace:     app.Status.Namespace,
+		LabelSelector: selector,
+	}); err != nil {
+		return nil, err
+	}
+	for i := range ingresses.Items {
+		if err := NetworkPolicyForIngress(router.Request{Ctx: ctx, Client: c, Object: &ingresses.Items[i]}, resp); err != nil {
+			return nil, err
+		}
+	}
+
+	services := &corev1.ServiceList{}
+	if err := c.List(ctx, services, &kclient.ListOptions{
+		Namespace:     app.Status.Namespace,
+		LabelSelector: selector,
+	}); err != nil {
+		return nil, err
+	}
+	for i := range services.Items {
+		if err := NetworkPolicyForService(router.Request{Ctx: ctx, Client: c, Object: &services.Items[i]}, resp); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]*networkingv1.NetworkPolicy, 0, len(resp.objects))
+	for _, obj := range resp.objects {
+		if netPol, ok := obj.(*networkingv1.NetworkPolicy); ok {
+			result = append(result, netPol)
+		}
+	}
+	return result, nil
+}
+
+// Diff compares the expected NetworkPolicies against the cluster and reports whether each one is missing or
+// has a spec that differs from what the controller would generate.
+func Diff(ctx context.Context, c kclient.Client, expected []*networkingv1.NetworkPolicy) ([]Drift, error) {
+	result := make([]Drift, 0, len(expected))
+	for _, want := range expected {
+		drift := Drift{
+			Name:      want.Name,
+			Namespace: want.Namespace,
+			Status:    DriftStatusInSync,
+		}
+
+		existing := &networkingv1.NetworkPolicy{}
+		if err := c.Get(ctx, router.Key(want.Namespace, want.Name), existing); apierror.IsNotFound(err) {
+			drift.Status = DriftStatusMissing
+		} else if err != nil {
+			return nil, err
+		} else if !equality.Semantic.DeepEqual(want.Spec, existing.Spec) {
+			drift.Status = DriftStatusModified
+		}
+
+		result = append(result, drift)
+	}
+	return result, nil
+}
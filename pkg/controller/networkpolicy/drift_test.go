@@ -0,0 +1,77 @@
+package networkpolicy
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDiffMissingPolicy(t *testing.T) {
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "acorn",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-namespace",
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-publish",
+			Namespace: "app-namespace",
+			Labels: map[string]string{
+				labels.AcornManaged:       "true",
+				labels.AcornAppName:       "app",
+				labels.AcornAppNamespace:  "acorn",
+				labels.AcornContainerName: "web",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeLoadBalancer,
+			Selector: map[string]string{labels.AcornContainerName: "web"},
+			Ports: []corev1.ServicePort{{
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(svc).Build()
+
+	expected, err := Expected(ctx, c, app)
+	require.NoError(t, err)
+	require.Len(t, expected, 2)
+
+	// Only the app policy exists, and someone has dropped its ingress rules
+	appPolicy := expected[0].DeepCopy()
+	appPolicy.Spec.Ingress = nil
+	require.NoError(t, c.Create(ctx, appPolicy))
+
+	drift, err := Diff(ctx, c, expected)
+	require.NoError(t, err)
+	assert.Equal(t, []Drift{
+		{Name: "app", Namespace: "app-namespace", Status: DriftStatusModified},
+		{Name: expected[1].Name, Namespace: "app-namespace", Status: DriftStatusMissing},
+	}, drift)
+
+	// Restoring the app policy leaves only the missing service policy as drift
+	appPolicy.Spec = expected[0].Spec
+	require.NoError(t, c.Update(ctx, appPolicy))
+
+	drift, err = Diff(ctx, c, expected)
+	require.NoError(t, err)
+	assert.Equal(t, DriftStatusInSync, drift[0].Status)
+	assert.Equal(t, DriftStatusMissing, drift[1].Status)
+}
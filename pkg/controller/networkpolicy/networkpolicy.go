@@ -48,6 +48,15 @@ func NetworkPolicyForApp(req router.Request, resp router.Response) error {
 			},
 		})
 	}
+	if key, value, found := strings.Cut(*cfg.AllowTrafficFromNamespaceLabel, "="); found {
+		allowedNamespaceSelectors = append(allowedNamespaceSelectors, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					key: value,
+				},
+			},
+		})
+	}
 
 	// create the NetworkPolicy for the whole app
 	// this allows traffic only from within the project
@@ -56,7 +65,9 @@ func NetworkPolicyForApp(req router.Request, resp router.Response) error {
 			Name:      app.Name,
 			Namespace: podNamespace,
 			Labels: map[string]string{
-				labels.AcornManaged: "true",
+				labels.AcornManaged:      "true",
+				labels.AcornAppName:      app.Name,
+				labels.AcornAppNamespace: app.Namespace,
 			},
 		},
 		Spec: networkingv1.NetworkPolicySpec{
@@ -145,10 +156,10 @@ func NetworkPolicyForIngress(req router.Request, resp router.Response) error {
 
 		// build the namespaceSelector for the NetPol
 		var namespaceSelector metav1.LabelSelector
-		if *cfg.IngressControllerNamespace != "" {
+		if ingressControllerNamespace := config.IngressControllerNamespace(cfg, ingressClassName(ingress)); ingressControllerNamespace != "" {
 			namespaceSelector = metav1.LabelSelector{
 				MatchLabels: map[string]string{
-					"kubernetes.io/metadata.name": *cfg.IngressControllerNamespace,
+					"kubernetes.io/metadata.name": ingressControllerNamespace,
 				},
 			}
 		}
@@ -180,7 +191,9 @@ func NetworkPolicyForIngress(req router.Request, resp router.Response) error {
 				Name:      netPolName,
 				Namespace: svc.Namespace,
 				Labels: map[string]string{
-					labels.AcornManaged: "true",
+					labels.AcornManaged:      "true",
+					labels.AcornAppName:      appName,
+					labels.AcornAppNamespace: projectName,
 				},
 			},
 			Spec: networkingv1.NetworkPolicySpec{
@@ -240,15 +253,18 @@ func NetworkPolicyForService(req router.Request, resp router.Response) error {
 	ipBlock := networkingv1.IPBlock{
 		CIDR: "0.0.0.0/0",
 	}
-	// get pod CIDRs from the nodes so that we can only allow traffic from IP addresses outside the cluster
-	nodes := corev1.NodeList{}
-	if err = req.Client.List(req.Ctx, &nodes); err != nil {
-		return fmt.Errorf("failed to list nodes: %w", err)
-	}
-	for _, node := range nodes.Items {
-		for _, cidr := range node.Spec.PodCIDRs {
-			if !slices.Contains(ipBlock.Except, cidr) {
-				ipBlock.Except = append(ipBlock.Except, cidr)
+	// public services allow traffic from any address, including the pods of the cluster
+	if service.Annotations[labels.AcornServicePublic] != "true" {
+		// get pod CIDRs from the nodes so that we can only allow traffic from IP addresses outside the cluster
+		nodes := corev1.NodeList{}
+		if err = req.Client.List(req.Ctx, &nodes); err != nil {
+			return fmt.Errorf("failed to list nodes: %w", err)
+		}
+		for _, node := range nodes.Items {
+			for _, cidr := range node.Spec.PodCIDRs {
+				if !slices.Contains(ipBlock.Except, cidr) {
+					ipBlock.Except = append(ipBlock.Except, cidr)
+				}
 			}
 		}
 	}
@@ -270,7 +286,9 @@ func NetworkPolicyForService(req router.Request, resp router.Response) error {
 			Name:      name.SafeConcatName(projectName, appName, service.Name, containerName),
 			Namespace: service.Namespace,
 			Labels: map[string]string{
-				labels.AcornManaged: "true",
+				labels.AcornManaged:      "true",
+				labels.AcornAppName:      appName,
+				labels.AcornAppNamespace: projectName,
 			},
 		},
 		Spec: networkingv1.NetworkPolicySpec{
@@ -305,3 +323,10 @@ func NetworkPolicyForService(req router.Request, resp router.Response) error {
 
 	return nil
 }
+
+func ingressClassName(ingress *networkingv1.Ingress) string {
+	if ingress.Spec.IngressClassName == nil {
+		return ""
+	}
+	return *ingress.Spec.IngressClassName
+}
@@ -11,10 +11,18 @@ func TestNetworkPolicyForApp(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/networkpolicy/appinstance", NetworkPolicyForApp)
 }
 
+func TestNetworkPolicyForAppNamespaceLabel(t *testing.T) {
+	tester.DefaultTest(t, scheme.Scheme, "testdata/networkpolicy/appinstancelabel", NetworkPolicyForApp)
+}
+
 func TestNetworkPolicyForIngress(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/networkpolicy/ingress", NetworkPolicyForIngress)
 }
 
+func TestNetworkPolicyForIngressClass(t *testing.T) {
+	tester.DefaultTest(t, scheme.Scheme, "testdata/networkpolicy/ingressclass", NetworkPolicyForIngress)
+}
+
 func TestNetworkPolicyForIngressExternalName(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/networkpolicy/externalname", NetworkPolicyForIngress)
 }
@@ -22,3 +30,7 @@ func TestNetworkPolicyForIngressExternalName(t *testing.T) {
 func TestNetworkPolicyForService(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/networkpolicy/service", NetworkPolicyForService)
 }
+
+func TestNetworkPolicyForServicePublic(t *testing.T) {
+	tester.DefaultTest(t, scheme.Scheme, "testdata/networkpolicy/servicepublic", NetworkPolicyForService)
+}
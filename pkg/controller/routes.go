@@ -21,6 +21,7 @@ import (
 	"github.com/acorn-io/acorn/pkg/controller/tls"
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/acorn-io/acorn/pkg/tracing"
 	"github.com/acorn-io/acorn/pkg/volume"
 	"github.com/acorn-io/baaah/pkg/router"
 	appsv1 "k8s.io/api/apps/v1"
@@ -54,18 +55,20 @@ func routes(router *router.Router, registryTransport http.RoundTripper) {
 	appRouter.HandlerFunc(defaults.Calculate)
 	appRouter.HandlerFunc(scheduling.Calculate)
 	appRouter = appRouter.Middleware(appdefinition.CheckStatus)
-	appRouter.Middleware(appdefinition.ImagePulled, appdefinition.CheckDependencies).HandlerFunc(appdefinition.DeploySpec)
-	appRouter.Middleware(appdefinition.ImagePulled).HandlerFunc(secrets.CreateSecrets)
+	appRouter.Middleware(appdefinition.ImagePulled, appdefinition.CheckDependencies).HandlerFunc(tracing.Handler("DeploySpec", appdefinition.DeploySpec))
+	appRouter.Middleware(appdefinition.ImagePulled).HandlerFunc(tracing.Handler("CreateSecrets", secrets.CreateSecrets))
 	appRouter.HandlerFunc(appdefinition.AppStatus)
 	appRouter.HandlerFunc(appdefinition.AppEndpointsStatus)
 	appRouter.HandlerFunc(appdefinition.JobStatus)
-	appRouter.HandlerFunc(appdefinition.VolumeStatus)
+	appRouter.HandlerFunc(tracing.Handler("VolumeStatus", appdefinition.VolumeStatus))
 	appRouter.HandlerFunc(appdefinition.AcornStatus)
+	appRouter.HandlerFunc(appdefinition.NetworkPolicyStatus)
 	appRouter.HandlerFunc(appdefinition.ReadyStatus)
-	appRouter.HandlerFunc(networkpolicy.NetworkPolicyForApp)
+	appRouter.HandlerFunc(tracing.Handler("NetworkPolicyForApp", networkpolicy.NetworkPolicyForApp))
 	appRouter.HandlerFunc(appdefinition.AddAcornProjectLabel)
 	appRouter.HandlerFunc(appdefinition.UpdateObservedFields)
 
+	router.Type(&v1.AppInstance{}).Middleware(appdefinition.RequireNamespace).HandlerFunc(appdefinition.NamespaceTerminatingStatus)
 	router.Type(&v1.AppInstance{}).HandlerFunc(appdefinition.CLIStatus)
 
 	router.Type(&v1.ServiceInstance{}).HandlerFunc(service.RenderServices)
@@ -82,6 +85,7 @@ func routes(router *router.Router, registryTransport http.RoundTripper) {
 	router.Type(&corev1.PersistentVolumeClaim{}).Selector(managedSelector).HandlerFunc(pvc.MarkAndSave)
 	router.Type(&corev1.PersistentVolume{}).Selector(managedSelector).HandlerFunc(appdefinition.ReleaseVolume)
 	router.Type(&corev1.Namespace{}).Selector(managedSelector).HandlerFunc(namespace.DeleteOrphaned)
+	router.Type(&corev1.Namespace{}).Selector(managedSelector).HandlerFunc(namespace.ReportStuckTerminating)
 	router.Type(&appsv1.DaemonSet{}).Namespace(system.ImagesNamespace).HandlerFunc(gc.GCOrphans)
 	router.Type(&appsv1.Deployment{}).Namespace(system.ImagesNamespace).HandlerFunc(gc.GCOrphans)
 	router.Type(&corev1.Service{}).Selector(managedSelector).HandlerFunc(gc.GCOrphans)
@@ -90,9 +94,12 @@ func routes(router *router.Router, registryTransport http.RoundTripper) {
 	router.Type(&netv1.Ingress{}).Selector(managedSelector).Namespace(system.ImagesNamespace).HandlerFunc(gc.GCOrphans)
 	router.Type(&netv1.Ingress{}).Selector(managedSelector).Middleware(ingress.RequireLBs).Handler(ingress.NewDNSHandler())
 	router.Type(&corev1.Secret{}).Selector(managedSelector).Middleware(tls.RequireSecretTypeTLS).HandlerFunc(tls.RenewCert) // renew (expired) TLS certificates, including the on-acorn.io wildcard cert
+	router.Type(&corev1.Secret{}).Selector(managedSelector).HandlerFunc(secrets.ExpireUnused)
+	router.Type(&corev1.Secret{}).Selector(managedSelector).HandlerFunc(secrets.GCBackingSecrets)
+	router.Type(&corev1.Secret{}).Selector(managedSelector).HandlerFunc(secrets.RefreshDockerTokens)
 	router.Type(&storagev1.StorageClass{}).HandlerFunc(volume.SyncVolumeClasses)
-	router.Type(&corev1.Service{}).Selector(managedSelector).HandlerFunc(networkpolicy.NetworkPolicyForService)
-	router.Type(&netv1.Ingress{}).Selector(managedSelector).HandlerFunc(networkpolicy.NetworkPolicyForIngress)
+	router.Type(&corev1.Service{}).Selector(managedSelector).HandlerFunc(tracing.Handler("NetworkPolicyForService", networkpolicy.NetworkPolicyForService))
+	router.Type(&netv1.Ingress{}).Selector(managedSelector).HandlerFunc(tracing.Handler("NetworkPolicyForIngress", networkpolicy.NetworkPolicyForIngress))
 	router.Type(&netv1.NetworkPolicy{}).Selector(managedSelector).HandlerFunc(gc.GCOrphans)
 
 	configRouter := router.Type(&corev1.ConfigMap{}).Namespace(system.Namespace).Name(system.ConfigName)
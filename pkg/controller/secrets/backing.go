@@ -0,0 +1,46 @@
+package secrets
+
+import (
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// GCBackingSecrets deletes a secret generated for an app in the namespace for backing secrets once the app is deleted.
+// Secrets generated in the project namespace of the app go away with the project, but the namespace for backing
+// secrets is shared by every project, so its secrets are matched to their app by the app name and app namespace labels,
+// and by the app UID label so that an app created again with the same name doesn't keep the secrets of the deleted one.
+// Secrets generated before the UID label was added are matched by name only. Deleting a project deletes its apps,
+// which deletes their backing secrets here too.
+func GCBackingSecrets(req router.Request, _ router.Response) error {
+	secret := req.Object.(*corev1.Secret)
+	appNamespace := secret.Labels[labels.AcornAppNamespace]
+	if appNamespace == "" || appNamespace == secret.Namespace || secret.Labels[labels.AcornSecretGenerated] != "true" ||
+		!secret.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	app := &v1.AppInstance{}
+	if err := req.Get(app, appNamespace, secret.Labels[labels.AcornAppName]); err == nil && app.DeletionTimestamp.IsZero() &&
+		sameApp(secret, app) {
+		return nil
+	} else if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	logrus.Infof("deleting backing secret %s/%s of deleted app %s/%s", secret.Namespace, secret.Name, appNamespace,
+		secret.Labels[labels.AcornAppName])
+	if err := req.Client.Delete(req.Ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// sameApp returns true if the secret was generated for the app, and not for a deleted app of the same name
+func sameApp(secret *corev1.Secret, app *v1.AppInstance) bool {
+	uid := secret.Labels[labels.AcornAppUID]
+	return uid == "" || uid == string(app.UID)
+}
@@ -0,0 +1,90 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func backingSecret(name, appNamespace, appName string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "acorn-secrets",
+			Labels: map[string]string{
+				labels.AcornAppName:         appName,
+				labels.AcornAppNamespace:    appNamespace,
+				labels.AcornManaged:         "true",
+				labels.AcornSecretName:      "db",
+				labels.AcornSecretGenerated: "true",
+			},
+		},
+	}
+}
+
+func TestGCBackingSecrets(t *testing.T) {
+	kept, recreated := backingSecret("db-kept", "project-one", "app"), backingSecret("db-recreated-app", "project-one", "app")
+	kept.Labels[labels.AcornAppUID] = "app-uid"
+	// an app created again with the same name doesn't keep the secret of the deleted app
+	recreated.Labels[labels.AcornAppUID] = "deleted-app-uid"
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		kept,
+		recreated,
+		// secrets generated before the UID label was added are matched by name
+		backingSecret("db-kept-unlabeled", "project-one", "app"),
+		backingSecret("db-deleted-app", "project-one", "deleted-app"),
+		// the app of the same name in another project doesn't keep the secret
+		backingSecret("db-deleted-project", "project-two", "app"),
+		&v1.AppInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app",
+				Namespace: "project-one",
+				UID:       "app-uid",
+			},
+		},
+	).Build()
+
+	for _, name := range []string{"db-kept", "db-kept-unlabeled", "db-deleted-app", "db-deleted-project", "db-recreated-app"} {
+		secret := &corev1.Secret{}
+		require.NoError(t, c.Get(context.Background(), router.Key("acorn-secrets", name), secret))
+		require.NoError(t, GCBackingSecrets(router.Request{
+			Client: c,
+			Ctx:    context.Background(),
+			Object: secret,
+		}, &tester.Response{}))
+	}
+
+	for _, name := range []string{"db-kept", "db-kept-unlabeled"} {
+		assert.NoError(t, c.Get(context.Background(), router.Key("acorn-secrets", name), &corev1.Secret{}), name)
+	}
+	for _, name := range []string{"db-deleted-app", "db-deleted-project", "db-recreated-app"} {
+		err := c.Get(context.Background(), router.Key("acorn-secrets", name), &corev1.Secret{})
+		assert.True(t, apierrors.IsNotFound(err), "%s: %v", name, err)
+	}
+}
+
+func TestGCBackingSecretsIgnoresProjectSecrets(t *testing.T) {
+	// secrets generated in the project namespace have no app namespace label and are left to the project
+	secret := backingSecret("db", "", "deleted-app")
+	secret.Namespace = "project-one"
+	delete(secret.Labels, labels.AcornAppNamespace)
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	require.NoError(t, GCBackingSecrets(router.Request{
+		Client: c,
+		Ctx:    context.Background(),
+		Object: secret,
+	}, &tester.Response{}))
+	assert.NoError(t, c.Get(context.Background(), router.Key("project-one", "db"), &corev1.Secret{}))
+}
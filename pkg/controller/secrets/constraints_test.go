@@ -0,0 +1,283 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"unicode/utf8"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBasicConstraints_Gen(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "basic",
+						Params: v1.GenericMap{
+							"characters":           "ABCDEFabcdef0123456789!@#",
+							"disallowedCharacters": "@#",
+							"minLength":            int64(24),
+							"pattern":              `^[A-Za-z0-9!]+$`,
+						},
+						Data: map[string]string{
+							"username": "",
+							"password": "",
+						},
+					},
+				},
+			},
+		},
+	}, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, resp.Client.Created, 1)
+	secret := resp.Client.Created[0].(*corev1.Secret)
+	assert.Len(t, secret.Data["password"], 24)
+	assert.Regexp(t, `^[A-Fa-f0-9!]+$`, string(secret.Data["password"]))
+}
+
+func TestBasicConstraintsUnsatisfiable_Gen(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "basic",
+						Params: v1.GenericMap{
+							"pattern": `^[A-Z]+$`,
+						},
+						Data: map[string]string{
+							"username": "",
+							"password": "",
+						},
+					},
+				},
+			},
+		},
+	}
+	resp, err := h.InvokeFunc(t, app, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, resp.Client.Created, 0)
+	cond := app.Status.Condition(v1.AppInstanceConditionSecrets)
+	assert.True(t, cond.Error)
+	assert.Contains(t, cond.Message, "failed to generate a value meeting the constraints after 10 attempts")
+	assert.Contains(t, cond.Message, "does not match pattern [^[A-Z]+$]")
+}
+
+func TestTokenConstraints_Gen(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"token": {
+						Type: "token",
+						Params: v1.GenericMap{
+							"characters":           "abcdef",
+							"length":               int64(32),
+							"disallowedCharacters": "abc",
+							"pattern":              `^[d-f]{32}$`,
+						},
+					},
+				},
+			},
+		},
+	}, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, resp.Client.Created, 1)
+	secret := resp.Client.Created[0].(*corev1.Secret)
+	assert.Regexp(t, `^[d-f]{32}$`, string(secret.Data["token"]))
+}
+
+func TestTokenConstraintsNoCharactersLeft(t *testing.T) {
+	secretRef := v1.Secret{
+		Type: "token",
+		Params: v1.GenericMap{
+			"characters":           "abc",
+			"length":               int64(8),
+			"disallowedCharacters": "cba",
+		},
+	}
+	assert.EqualError(t, secrets.ValidateParams(secretRef),
+		"disallowedCharacters param [cba] removes every character the value can be generated from")
+
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{"token": secretRef},
+			},
+		},
+	}
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Object: app,
+	}
+	_, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "token")
+	var genErr *secrets.ErrSecretGeneration
+	require.ErrorAs(t, err, &genErr)
+	assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+}
+
+func TestTokenMultibyteCharacters_Gen(t *testing.T) {
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"token": {
+						Type: "token",
+						Params: v1.GenericMap{
+							"characters":           "äöüß",
+							"length":               int64(16),
+							"disallowedCharacters": "ß",
+							"minLength":            int64(16),
+						},
+					},
+				},
+			},
+		},
+	}
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Object: app,
+	}
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "token")
+	require.NoError(t, err)
+
+	token := string(secret.Data["token"])
+	assert.True(t, utf8.ValidString(token))
+	assert.Regexp(t, `^[äöü]{16}$`, token)
+}
+
+func TestTokenNumeric_Gen(t *testing.T) {
+	appSecrets := map[string]v1.Secret{
+		"pin": {
+			Type: "token",
+			Params: v1.GenericMap{
+				"numeric":    true,
+				"characters": "abcdef",
+				"length":     int64(8),
+			},
+			Data: map[string]string{
+				"token": "not-a-pin",
+			},
+		},
+	}
+	for i := 0; i < 20; i++ {
+		appSecrets[fmt.Sprintf("otp-%d", i)] = v1.Secret{
+			Type: "token",
+			Params: v1.GenericMap{
+				"numeric": true,
+				"length":  int64(256),
+			},
+		}
+	}
+
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: appSecrets,
+			},
+		},
+	}, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, resp.Client.Created, len(appSecrets))
+	counts := map[rune]int{}
+	for _, obj := range resp.Client.Created {
+		secret := obj.(*corev1.Secret)
+		token := string(secret.Data["token"])
+		if secret.Labels[labels.AcornSecretName] == "pin" {
+			// supplied values are kept even if they are not numeric
+			assert.Equal(t, "not-a-pin", token)
+			continue
+		}
+		assert.Regexp(t, `^[0-9]{256}$`, token)
+		for _, c := range token {
+			counts[c]++
+		}
+	}
+
+	// 5120 digits give 512 of each digit on average with a standard deviation of about 21, so a digit outside of
+	// 362-662 means the digits are not picked uniformly
+	assert.Len(t, counts, 10)
+	for c, count := range counts {
+		assert.InDeltaf(t, 512, count, 150, "digit %c was generated %d times", c, count)
+	}
+}
@@ -0,0 +1,246 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func dockerApp(appSecrets map[string]v1.Secret) *v1.AppInstance {
+	app := regenerateTokenApp("")
+	app.Status.AppSpec.Secrets = appSecrets
+	return app
+}
+
+// dockerAuths returns the decoded username and password of each registry in the .dockerconfigjson of the secret
+func dockerAuths(t *testing.T, secret *corev1.Secret) map[string]string {
+	t.Helper()
+
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	require.NoError(t, json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config))
+
+	result := map[string]string{}
+	for registry, entry := range config.Auths {
+		auth, err := base64.StdEncoding.DecodeString(entry.Auth)
+		require.NoError(t, err)
+		result[registry] = string(auth)
+	}
+	return result
+}
+
+func TestDocker_Gen(t *testing.T) {
+	app := dockerApp(map[string]v1.Secret{
+		"pull": {
+			Type: "docker",
+			Params: v1.GenericMap{
+				"registry": "ghcr.io",
+				"username": "user",
+				"password": "pass",
+			},
+		},
+	})
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Object: app,
+	}
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pull")
+	require.NoError(t, err)
+	assert.Equal(t, v1.SecretTypeDocker, secret.Type)
+	assert.Equal(t, map[string]string{"ghcr.io": "user:pass"}, dockerAuths(t, secret))
+}
+
+func TestDockerFromBasicSecret(t *testing.T) {
+	app := dockerApp(map[string]v1.Secret{
+		"creds": {
+			Type: "basic",
+			Data: map[string]string{
+				"username": "user",
+				"password": "pass",
+			},
+		},
+		"pull": {
+			Type: "docker",
+			Params: v1.GenericMap{
+				"registry": "ghcr.io",
+				"secret":   "creds",
+			},
+		},
+	})
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Object: app,
+	}
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pull")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ghcr.io": "user:pass"}, dockerAuths(t, secret))
+	assert.Equal(t, []string{"creds"}, secretDependencies(app, secEntry{name: "pull", secret: app.Status.AppSpec.Secrets["pull"]}))
+}
+
+func TestDockerMultipleRegistries(t *testing.T) {
+	app := dockerApp(map[string]v1.Secret{
+		"creds": {
+			Type: "basic",
+			Data: map[string]string{
+				"username": "sidecar-user",
+				"password": "sidecar-pass",
+			},
+		},
+		"pull": {
+			Type: "docker",
+			Data: map[string]string{
+				corev1.DockerConfigJsonKey: `{"auths":{"index.docker.io":{"auth":"` + base64.StdEncoding.EncodeToString([]byte("hub-user:hub-pass")) + `"}},"credsStore":"none"}`,
+			},
+			Params: v1.GenericMap{
+				"registry": "ghcr.io",
+				"username": "base-user",
+				"password": "base-pass",
+				"registries": []any{
+					map[string]any{
+						"registry": "quay.io",
+						"secret":   "creds",
+					},
+					map[string]any{
+						"registry": "registry.example.com",
+						"username": "example-user",
+						"password": "example-pass",
+					},
+				},
+			},
+		},
+	})
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Object: app,
+	}
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pull")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"index.docker.io":      "hub-user:hub-pass",
+		"ghcr.io":              "base-user:base-pass",
+		"quay.io":              "sidecar-user:sidecar-pass",
+		"registry.example.com": "example-user:example-pass",
+	}, dockerAuths(t, secret))
+
+	// Fields of the supplied config other than auths are kept
+	var config map[string]any
+	require.NoError(t, json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config))
+	assert.Equal(t, "none", config["credsStore"])
+}
+
+func TestDockerToken(t *testing.T) {
+	app := dockerApp(map[string]v1.Secret{
+		"pull": {
+			Type: "docker",
+			Params: v1.GenericMap{
+				"registry": "123456789012.dkr.ecr.us-east-1.amazonaws.com",
+				"username": "AWS",
+				"job":      "gen-job",
+				"tokenTTL": "12h",
+			},
+		},
+	})
+	completed := time.Now().Add(-time.Hour).Truncate(time.Second)
+	objects := jobOutput("ecr-token\n")
+	objects[0].(*batchv1.Job).Status.CompletionTime = &metav1.Time{Time: completed}
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme, Objects: objects},
+		Object: app,
+	}
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pull")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"123456789012.dkr.ecr.us-east-1.amazonaws.com": "AWS:ecr-token"}, dockerAuths(t, secret))
+
+	// The job is run again when 80% of the lifetime of the token has passed
+	assert.Equal(t, "gen-job", secret.Annotations[labels.AcornSecretTokenJobs])
+	assert.Equal(t, completed.Add(12*time.Hour*4/5).UTC().Format(time.RFC3339), secret.Annotations[labels.AcornSecretTokenRefresh])
+
+	// While the job runs again, the current token is kept
+	objects[0].(*batchv1.Job).Status = batchv1.JobStatus{}
+	refreshed, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pull")
+	require.NoError(t, err)
+	assert.Equal(t, secret.Data, refreshed.Data)
+}
+
+func TestDockerTokenInvalidTTL(t *testing.T) {
+	genErr := generationError(t, "pull", map[string]v1.Secret{
+		"pull": {
+			Type: "docker",
+			Params: v1.GenericMap{
+				"registry": "ghcr.io",
+				"username": "user",
+				"job":      "gen-job",
+			},
+		},
+	})
+	assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+}
+
+func TestDockerEmpty(t *testing.T) {
+	app := dockerApp(map[string]v1.Secret{
+		"pull": {
+			Type: "docker",
+		},
+	})
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Object: app,
+	}
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pull")
+	require.NoError(t, err)
+	assert.Empty(t, dockerAuths(t, secret))
+}
+
+func TestDockerMissingCredentials(t *testing.T) {
+	genErr := generationError(t, "pull", map[string]v1.Secret{
+		"pull": {
+			Type: "docker",
+			Params: v1.GenericMap{
+				"registry": "ghcr.io",
+			},
+		},
+	})
+	assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+
+	genErr = generationError(t, "pull", map[string]v1.Secret{
+		"pull": {
+			Type: "docker",
+			Params: v1.GenericMap{
+				"registries": []any{
+					map[string]any{
+						"username": "user",
+						"password": "pass",
+					},
+				},
+			},
+		},
+	})
+	assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+}
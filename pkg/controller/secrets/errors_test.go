@@ -0,0 +1,101 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/jobs"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func generationError(t *testing.T, secretName string, appSecrets map[string]v1.Secret) *secrets.ErrSecretGeneration {
+	t.Helper()
+
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: appSecrets,
+			},
+		},
+	}
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Object: app,
+	}
+
+	_, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, secretName)
+	var genErr *secrets.ErrSecretGeneration
+	require.ErrorAs(t, err, &genErr)
+	return genErr
+}
+
+func TestGenerationErrorInvalidParams(t *testing.T) {
+	genErr := generationError(t, "pass", map[string]v1.Secret{
+		"pass": {
+			Type: "token",
+			Params: v1.GenericMap{
+				"length":    int64(8),
+				"minLength": "many",
+			},
+		},
+	})
+	assert.Equal(t, "pass", genErr.Name)
+	assert.Equal(t, "token", genErr.Type)
+	assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+}
+
+func TestGenerationErrorJob(t *testing.T) {
+	genErr := generationError(t, "out", map[string]v1.Secret{
+		"out": {
+			Type: "generated",
+			Params: v1.GenericMap{
+				"job": "gen-job",
+			},
+		},
+	})
+	assert.Equal(t, "out", genErr.Name)
+	assert.Equal(t, "generated", genErr.Type)
+	assert.Equal(t, secrets.GenerationReasonJob, genErr.Reason)
+	assert.ErrorIs(t, genErr, jobs.ErrJobNotDone)
+}
+
+func TestGenerationErrorFromDependency(t *testing.T) {
+	genErr := generationError(t, "tpl", map[string]v1.Secret{
+		"out": {
+			Type: "generated",
+			Params: v1.GenericMap{
+				"job": "gen-job",
+			},
+		},
+		"tpl": {
+			Type: "template",
+			Data: map[string]string{
+				"template": "${secret://out/content}",
+			},
+		},
+	})
+	assert.Equal(t, "tpl", genErr.Name)
+	assert.Equal(t, "template", genErr.Type)
+	assert.Equal(t, secrets.GenerationReasonJob, genErr.Reason)
+
+	var depErr *secrets.ErrSecretGeneration
+	require.ErrorAs(t, genErr.Cause, &depErr)
+	assert.Equal(t, "out", depErr.Name)
+}
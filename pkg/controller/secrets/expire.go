@@ -0,0 +1,109 @@
+package secrets
+
+import (
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExpireUnused deletes a generated secret with a TTL once no app has used it for longer than the TTL. The secret is in
+// use while the app it was generated for declares it and has a running pod. The secret is checked ten times per TTL,
+// and each check that finds it used records the time of the last use, so use keeps resetting the clock. Until the
+// secret is first used, the clock starts when it was created. The secret of an app that is stopped or suspended for
+// longer than the TTL is deleted too, and a new value is generated when the app is started again.
+func ExpireUnused(req router.Request, resp router.Response) error {
+	secret := req.Object.(*corev1.Secret)
+	value := secret.Annotations[labels.AcornSecretTTL]
+	if value == "" || secret.Labels[labels.AcornSecretGenerated] != "true" {
+		return nil
+	}
+
+	ttl, err := secrets.ParseTTL(value)
+	if err != nil {
+		logrus.Warnf("not expiring secret %s/%s: %v", secret.Namespace, secret.Name, err)
+		return nil
+	}
+
+	used, err := usedByRunningApp(req, secret)
+	if err != nil {
+		return err
+	}
+
+	var (
+		now      = time.Now()
+		interval = ttl / 10
+		lastUsed = lastUsedTime(secret)
+	)
+	if used {
+		if now.Sub(lastUsed) >= interval {
+			secret = secret.DeepCopy()
+			secret.Annotations[labels.AcornSecretLastUsed] = now.UTC().Format(time.RFC3339)
+			if err := req.Client.Update(req.Ctx, secret); err != nil {
+				return err
+			}
+		}
+		resp.RetryAfter(interval)
+		return nil
+	}
+
+	if unused := now.Sub(lastUsed); unused < ttl {
+		resp.RetryAfter(ttl - unused)
+		return nil
+	}
+
+	logrus.Infof("deleting secret %s/%s, unused for longer than its TTL of %s", secret.Namespace, secret.Name, ttl)
+	if err := req.Client.Delete(req.Ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// lastUsedTime returns when the secret was last used by a running app, or when it was created if it wasn't used yet
+func lastUsedTime(secret *corev1.Secret) time.Time {
+	if lastUsed, err := time.Parse(time.RFC3339, secret.Annotations[labels.AcornSecretLastUsed]); err == nil &&
+		lastUsed.After(secret.CreationTimestamp.Time) {
+		return lastUsed
+	}
+	return secret.CreationTimestamp.Time
+}
+
+// usedByRunningApp returns true if the app the secret was generated for still declares the secret and has a running
+// pod
+func usedByRunningApp(req router.Request, secret *corev1.Secret) (bool, error) {
+	appNamespace := secret.Labels[labels.AcornAppNamespace]
+	if appNamespace == "" {
+		appNamespace = secret.Namespace
+	}
+
+	app := &v1.AppInstance{}
+	if err := req.Get(app, appNamespace, secret.Labels[labels.AcornAppName]); apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if _, declared := app.Status.AppSpec.Secrets[secret.Labels[labels.AcornSecretName]]; !declared || app.Status.Namespace == "" {
+		return false, nil
+	}
+
+	var pods corev1.PodList
+	if err := req.List(&pods, &kclient.ListOptions{
+		Namespace:     app.Status.Namespace,
+		LabelSelector: klabels.SelectorFromSet(labels.Managed(app)),
+	}); err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			return true, nil
+		}
+	}
+	return false, nil
+}
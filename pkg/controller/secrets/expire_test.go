@@ -0,0 +1,154 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func expiringSecret(name, appName string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "app-namespace",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			Labels: map[string]string{
+				labels.AcornAppName:         appName,
+				labels.AcornManaged:         "true",
+				labels.AcornSecretName:      "db",
+				labels.AcornSecretGenerated: "true",
+			},
+			Annotations: map[string]string{
+				labels.AcornSecretTTL: "1h",
+			},
+		},
+	}
+}
+
+func expiringSecretApp(name string) *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "app-namespace",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: name + "-created-namespace",
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"db": {Type: "token"},
+				},
+			},
+		},
+	}
+}
+
+// expireUnused runs ExpireUnused for the secret with the given name, as stored by the client
+func expireUnused(t *testing.T, c kclient.Client, name string) *tester.Response {
+	t.Helper()
+	secret := &corev1.Secret{}
+	if err := c.Get(context.Background(), router.Key("app-namespace", name), secret); err != nil {
+		t.Fatal(err)
+	}
+	resp := &tester.Response{}
+	if err := ExpireUnused(router.Request{
+		Client: c,
+		Ctx:    context.Background(),
+		Object: secret,
+	}, resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestExpireUnused(t *testing.T) {
+	runningApp, undeclaringApp := expiringSecretApp("running-app"), expiringSecretApp("undeclaring-app")
+	undeclaringApp.Status.AppSpec.Secrets = nil
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		expiringSecret("db-used", "running-app"),
+		expiringSecret("db-undeclared", "undeclaring-app"),
+		expiringSecret("db-deleted", "deleted-app"),
+		runningApp,
+		undeclaringApp,
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: runningApp.Status.Namespace,
+				Labels:    labels.Managed(runningApp),
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		},
+	).Build()
+
+	// the secret declared by an app with a running pod is kept, and its use resets the clock
+	resp := expireUnused(t, c, "db-used")
+	secret := &corev1.Secret{}
+	if err := c.Get(context.Background(), router.Key("app-namespace", "db-used"), secret); err != nil {
+		t.Fatal(err)
+	}
+	lastUsed, err := time.Parse(time.RFC3339, secret.Annotations[labels.AcornSecretLastUsed])
+	if assert.NoError(t, err) {
+		assert.WithinDuration(t, time.Now(), lastUsed, time.Minute)
+	}
+	assert.Equal(t, 6*time.Minute, resp.Delay)
+
+	// the secrets that no app declared for longer than their TTL are deleted
+	for _, name := range []string{"db-undeclared", "db-deleted"} {
+		expireUnused(t, c, name)
+		err = c.Get(context.Background(), router.Key("app-namespace", name), &corev1.Secret{})
+		assert.True(t, apierrors.IsNotFound(err), "%s: %v", name, err)
+	}
+}
+
+func TestExpireUnusedStoppedApp(t *testing.T) {
+	stoppedApp, suspendedApp := expiringSecretApp("stopped-app"), expiringSecretApp("suspended-app")
+	stoppedApp.Spec.Stop = &[]bool{true}[0]
+	suspendedApp.Spec.Suspend = &[]bool{true}[0]
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		expiringSecret("db-stopped", "stopped-app"),
+		expiringSecret("db-suspended", "suspended-app"),
+		stoppedApp,
+		suspendedApp,
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: stoppedApp.Status.Namespace,
+				Labels:    labels.Managed(stoppedApp),
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodSucceeded,
+			},
+		},
+	).Build()
+
+	// apps without running pods don't use their secrets, so they expire once the TTL has passed since the last use
+	for _, name := range []string{"db-stopped", "db-suspended"} {
+		expireUnused(t, c, name)
+		err := c.Get(context.Background(), router.Key("app-namespace", name), &corev1.Secret{})
+		assert.True(t, apierrors.IsNotFound(err), "%s: %v", name, err)
+	}
+}
+
+func TestExpireUnusedRecentlyUsed(t *testing.T) {
+	secret := expiringSecret("db-unused", "deleted-app")
+	secret.Annotations[labels.AcornSecretLastUsed] = time.Now().Add(-30 * time.Minute).UTC().Format(time.RFC3339)
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	// the secret was created longer ago than its TTL, but it was used since
+	resp := expireUnused(t, c, "db-unused")
+	assert.NoError(t, c.Get(context.Background(), router.Key("app-namespace", "db-unused"), &corev1.Secret{}))
+	assert.InDelta(t, float64(30*time.Minute), float64(resp.Delay), float64(time.Minute))
+}
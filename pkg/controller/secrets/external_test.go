@@ -0,0 +1,122 @@
+package secrets
+
+import (
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func externalSecretApp() *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"db": {
+						Type: "external",
+						Params: v1.GenericMap{
+							"secretStore":     "vault",
+							"secretStoreKind": "ClusterSecretStore",
+							"data": map[string]interface{}{
+								"password": map[string]interface{}{
+									"key":      "database/creds",
+									"property": "password",
+								},
+								"username": "database/user",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExternal_Gen(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			&apiextensionv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "externalsecrets.external-secrets.io",
+				},
+			},
+		},
+	}
+	resp, err := h.InvokeFunc(t, externalSecretApp(), CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.Len(t, resp.Client.Created, 1)
+	externalSecret := resp.Client.Created[0].(*unstructured.Unstructured)
+	assert.Equal(t, "ExternalSecret", externalSecret.GetKind())
+	assert.Equal(t, "external-secrets.io/v1beta1", externalSecret.GetAPIVersion())
+	assert.Equal(t, "app-name-db", externalSecret.GetName())
+	assert.Equal(t, "app-ns", externalSecret.GetNamespace())
+	assert.Equal(t, map[string]interface{}{
+		"refreshInterval": "1h",
+		"secretStoreRef": map[string]interface{}{
+			"name": "vault",
+			"kind": "ClusterSecretStore",
+		},
+		"target": map[string]interface{}{
+			"name":           "app-name-db",
+			"creationPolicy": "Owner",
+			"template": map[string]interface{}{
+				"type": "Opaque",
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						labels.AcornAppName:         "app-name",
+						labels.AcornManaged:         "true",
+						labels.AcornSecretName:      "db",
+						labels.AcornSecretGenerated: "true",
+						labels.AcornPublicName:      "app-name.db",
+					},
+					"annotations": map[string]interface{}{},
+				},
+			},
+		},
+		"data": []interface{}{
+			map[string]interface{}{
+				"secretKey": "password",
+				"remoteRef": map[string]interface{}{
+					"key":      "database/creds",
+					"property": "password",
+				},
+			},
+			map[string]interface{}{
+				"secretKey": "username",
+				"remoteRef": map[string]interface{}{
+					"key": "database/user",
+				},
+			},
+		},
+	}, externalSecret.Object["spec"])
+
+	// The synced secret doesn't exist yet, so the app waits on it
+	app := resp.Collected[0].(*v1.AppInstance)
+	cond := app.Status.Condition(v1.AppInstanceConditionSecrets)
+	assert.False(t, cond.Success)
+	assert.Contains(t, cond.Message, "waiting: [db: waiting for the external-secrets operator to sync secret [db]]")
+}
+
+func TestExternalOperatorMissing_Gen(t *testing.T) {
+	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-external-operator-missing", CreateSecrets)
+}
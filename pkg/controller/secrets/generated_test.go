@@ -0,0 +1,318 @@
+package secrets
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/jobs"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestDotenv_Gen(t *testing.T) {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"job-name": "gen-job",
+		},
+	}
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+		Existing: []kclient.Object{
+			&batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gen-job",
+					Namespace: "app-target-ns",
+				},
+				Spec: batchv1.JobSpec{
+					Selector: selector,
+				},
+				Status: batchv1.JobStatus{
+					Succeeded: 1,
+				},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gen-job-abcde",
+					Namespace: "app-target-ns",
+					Labels:    selector.MatchLabels,
+				},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{
+									Message: strings.Join([]string{
+										"# generated credentials",
+										"",
+										"export USERNAME=admin",
+										`PASSWORD="p@ss \"word\""`,
+										"TOKEN='abc # not a comment'",
+										"REGION=us-east-1 # inline comment",
+									}, "\n"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"creds": {
+						Type: "generated",
+						Params: v1.GenericMap{
+							"job":    "gen-job",
+							"format": "dotenv",
+						},
+					},
+				},
+			},
+		},
+	}, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.Len(t, resp.Client.Created, 1)
+	secret := resp.Client.Created[0].(*corev1.Secret)
+	assert.Equal(t, map[string][]byte{
+		"USERNAME": []byte("admin"),
+		"PASSWORD": []byte(`p@ss "word"`),
+		"TOKEN":    []byte("abc # not a comment"),
+		"REGION":   []byte("us-east-1"),
+	}, secret.Data)
+}
+
+// jobOutput returns the objects of a completed job named gen-job in app-target-ns that printed the output
+func jobOutput(output string) []kclient.Object {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"job-name": "gen-job",
+		},
+	}
+	return []kclient.Object{
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gen-job",
+				Namespace: "app-target-ns",
+			},
+			Spec: batchv1.JobSpec{
+				Selector: selector,
+			},
+			Status: batchv1.JobStatus{
+				Succeeded: 1,
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gen-job-abcde",
+				Namespace: "app-target-ns",
+				Labels:    selector.MatchLabels,
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								Message: output,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func generateWithOutput(params v1.GenericMap, output string) (*corev1.Secret, error) {
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"cert": {
+						Type:   "generated",
+						Params: params,
+					},
+				},
+			},
+		},
+	}
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme, Objects: jobOutput(output)},
+		Object: app,
+	}
+	return secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "cert")
+}
+
+// testCertPEM returns a PEM encoded self-signed certificate
+func testCertPEM(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestGeneratedDeclaredTLS(t *testing.T) {
+	cert := testCertPEM(t)
+	// values in json output are base64 encoded
+	output := `{"data": {"tls.crt": "` + base64.StdEncoding.EncodeToString(cert) + `", "tls.key": "a2V5"}}`
+
+	for _, declared := range []string{"tls", "kubernetes.io/tls"} {
+		secret, err := generateWithOutput(v1.GenericMap{
+			"job":    "gen-job",
+			"format": "json",
+			"type":   declared,
+		}, output)
+		require.NoError(t, err, declared)
+		assert.Equal(t, v1.SecretTypeTLS, secret.Type, declared)
+		assert.Equal(t, cert, secret.Data[corev1.TLSCertKey], declared)
+		assert.Equal(t, []byte("key"), secret.Data[corev1.TLSPrivateKeyKey], declared)
+	}
+
+	// without the param the secret is not typed
+	secret, err := generateWithOutput(v1.GenericMap{
+		"job":    "gen-job",
+		"format": "json",
+	}, output)
+	require.NoError(t, err)
+	assert.Equal(t, v1.SecretTypeGenerated, secret.Type)
+}
+
+func TestGeneratedTLSInvalidCert(t *testing.T) {
+	for name, cert := range map[string][]byte{
+		"not pem": []byte("cert"),
+		"corrupt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("cert")}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := `"data": {"tls.crt": "` + base64.StdEncoding.EncodeToString(cert) + `", "tls.key": "a2V5"}`
+
+			// The cert is validated whether the type is declared or comes from the output
+			_, err := generateWithOutput(v1.GenericMap{
+				"job":    "gen-job",
+				"format": "json",
+				"type":   "tls",
+			}, `{`+data+`}`)
+			var genErr *secrets.ErrSecretGeneration
+			require.ErrorAs(t, err, &genErr)
+			assert.Equal(t, secrets.GenerationReasonJob, genErr.Reason)
+			assert.ErrorContains(t, err, "invalid certificate in [tls.crt]")
+
+			_, err = generateWithOutput(v1.GenericMap{
+				"job":    "gen-job",
+				"format": "json",
+			}, `{"type": "tls", `+data+`}`)
+			require.ErrorAs(t, err, &genErr)
+			assert.Equal(t, secrets.GenerationReasonJob, genErr.Reason)
+			assert.ErrorContains(t, err, "invalid certificate in [tls.crt]")
+		})
+	}
+}
+
+func TestGeneratedDeclaredTLSMissingKeys(t *testing.T) {
+	_, err := generateWithOutput(v1.GenericMap{
+		"job":    "gen-job",
+		"format": "text",
+		"type":   "kubernetes.io/tls",
+	}, "-----BEGIN CERTIFICATE-----")
+
+	var genErr *secrets.ErrSecretGeneration
+	require.ErrorAs(t, err, &genErr)
+	assert.Equal(t, secrets.GenerationReasonJob, genErr.Reason)
+	assert.ErrorContains(t, err, "output for secret of type [tls] is missing keys [tls.crt, tls.key]")
+}
+
+func TestGeneratedDeclaredTypeMismatch(t *testing.T) {
+	_, err := generateWithOutput(v1.GenericMap{
+		"job":    "gen-job",
+		"format": "json",
+		"type":   "tls",
+	}, `{"type": "basic", "data": {"tls.crt": "Y2VydA==", "tls.key": "a2V5"}}`)
+
+	var genErr *secrets.ErrSecretGeneration
+	require.ErrorAs(t, err, &genErr)
+	assert.Equal(t, secrets.GenerationReasonJob, genErr.Reason)
+	assert.ErrorContains(t, err, "output type [basic] does not match the declared type [tls]")
+}
+
+func TestGeneratedDeclaredTypeInvalid(t *testing.T) {
+	genErr := generationError(t, "out", map[string]v1.Secret{
+		"out": {
+			Type: "generated",
+			Params: v1.GenericMap{
+				"job":  "gen-job",
+				"type": "kubernetes.io/dockercfg",
+			},
+		},
+	})
+	assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+}
+
+func TestGeneratedInvalidOutputScrubbed(t *testing.T) {
+	for name, output := range map[string]string{
+		"truncated":  `{"data": {"password": "aHVudGVyMi1zZWNyZXQ=`,
+		"not base64": `{"data": {"password": "hunter2-secret"}}`,
+		"not json":   `password = "hunter2-secret" ]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := generateWithOutput(v1.GenericMap{
+				"job":    "gen-job",
+				"format": "json",
+			}, output)
+
+			var genErr *secrets.ErrSecretGeneration
+			require.ErrorAs(t, err, &genErr)
+			assert.Equal(t, secrets.GenerationReasonJob, genErr.Reason)
+			assert.ErrorIs(t, err, jobs.ErrInvalidOutput)
+			assert.NotContains(t, err.Error(), "hunter2")
+			assert.NotContains(t, err.Error(), "aHVudGVyMi1zZWNyZXQ")
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package secrets
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func jwtApp(regenerate string, params v1.GenericMap) *v1.AppInstance {
+	app := regenerateTokenApp(regenerate)
+	app.Status.AppSpec.Secrets = map[string]v1.Secret{
+		"signing": {
+			Type: "jwt",
+			Annotations: map[string]string{
+				labels.AcornSecretRegenerate: regenerate,
+			},
+			Params: params,
+		},
+	}
+	return app
+}
+
+func TestJWT_Gen(t *testing.T) {
+	for _, algorithm := range []string{"RS256", "ES256", "EdDSA"} {
+		t.Run(algorithm, func(t *testing.T) {
+			app := jwtApp("", v1.GenericMap{
+				"algorithm": algorithm,
+				"keyId":     "key-1",
+			})
+			req := router.Request{
+				Ctx:    context.Background(),
+				Client: &tester.Client{SchemeObj: scheme.Scheme},
+				Object: app,
+			}
+
+			secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "signing")
+			require.NoError(t, err)
+			assert.Equal(t, v1.SecretTypeJWT, secret.Type)
+			assert.Equal(t, "key-1", string(secret.Data[secrets.JWTKeyIDKey]))
+
+			var keySet secrets.JWKS
+			require.NoError(t, json.Unmarshal(secret.Data[secrets.JWTJWKSKey], &keySet))
+			require.Len(t, keySet.Keys, 1)
+			assert.Equal(t, "key-1", keySet.Keys[0].KeyID)
+			assert.Equal(t, algorithm, keySet.Keys[0].Algorithm)
+			assert.Equal(t, "sig", keySet.Keys[0].Use)
+
+			block, _ := pem.Decode(secret.Data[secrets.JWTPrivateKeyKey])
+			require.NotNil(t, block)
+			privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			require.NoError(t, err)
+
+			switch key := privateKey.(type) {
+			case *rsa.PrivateKey:
+				assert.Equal(t, "RSA", keySet.Keys[0].KeyType)
+				assert.Equal(t, base64.RawURLEncoding.EncodeToString(key.N.Bytes()), keySet.Keys[0].N)
+				assert.Equal(t, "AQAB", keySet.Keys[0].E)
+			case *ecdsa.PrivateKey:
+				assert.Equal(t, "EC", keySet.Keys[0].KeyType)
+				assert.Equal(t, "P-256", keySet.Keys[0].Curve)
+				assert.Equal(t, base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))), keySet.Keys[0].X)
+				assert.Equal(t, base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))), keySet.Keys[0].Y)
+			case ed25519.PrivateKey:
+				assert.Equal(t, "OKP", keySet.Keys[0].KeyType)
+				assert.Equal(t, "Ed25519", keySet.Keys[0].Curve)
+				assert.Equal(t, base64.RawURLEncoding.EncodeToString(key.Public().(ed25519.PublicKey)), keySet.Keys[0].X)
+			default:
+				t.Fatalf("unexpected private key type %T", privateKey)
+			}
+		})
+	}
+}
+
+// fakeSource reads zeros, so every generated character is the first of the allowed characters, and generates keys from
+// crypto/rand, recording the algorithms it was asked for
+type fakeSource struct {
+	reads      int
+	algorithms []string
+}
+
+func (f *fakeSource) Read(p []byte) (int, error) {
+	f.reads++
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func (f *fakeSource) GenerateKey(algorithm string) (crypto.PrivateKey, crypto.PublicKey, error) {
+	f.algorithms = append(f.algorithms, algorithm)
+	return secrets.GenerateKeyFrom(rand.Reader, algorithm)
+}
+
+func TestSecretSource(t *testing.T) {
+	source := &fakeSource{}
+	defer secrets.SetSource(secrets.SetSource(source))
+
+	app := jwtApp("", v1.GenericMap{
+		"algorithm": "ES256",
+	})
+	app.Status.AppSpec.Secrets["pass"] = v1.Secret{
+		Type: "basic",
+	}
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Object: app,
+	}
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pass")
+	require.NoError(t, err)
+	assert.Equal(t, "bbbbbbbb", string(secret.Data["username"]))
+	assert.Equal(t, "bbbbbbbbbbbbbbbb", string(secret.Data["password"]))
+	assert.Positive(t, source.reads)
+
+	_, err = secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "signing")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ES256"}, source.algorithms)
+}
+
+func TestJWTDefaultKeyID(t *testing.T) {
+	app := jwtApp("", v1.GenericMap{
+		"algorithm": "ES256",
+	})
+	req := router.Request{
+		Ctx:    context.Background(),
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Object: app,
+	}
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "signing")
+	require.NoError(t, err)
+
+	var keySet secrets.JWKS
+	require.NoError(t, json.Unmarshal(secret.Data[secrets.JWTJWKSKey], &keySet))
+	require.Len(t, keySet.Keys, 1)
+
+	// The default key ID is the RFC 7638 thumbprint of the public key
+	sum := sha256.Sum256([]byte(`{"crv":"P-256","kty":"EC","x":"` + keySet.Keys[0].X + `","y":"` + keySet.Keys[0].Y + `"}`))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), keySet.Keys[0].KeyID)
+	assert.Equal(t, keySet.Keys[0].KeyID, string(secret.Data[secrets.JWTKeyIDKey]))
+}
+
+func TestJWTInvalidAlgorithm(t *testing.T) {
+	genErr := generationError(t, "signing", map[string]v1.Secret{
+		"signing": {
+			Type: "jwt",
+			Params: v1.GenericMap{
+				"algorithm": "HS256",
+			},
+		},
+	})
+	assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+}
+
+func TestJWTKeyFormat(t *testing.T) {
+	for _, test := range []struct {
+		algorithm, keyFormat, blockType string
+	}{
+		{algorithm: "RS256", keyFormat: "", blockType: "PRIVATE KEY"},
+		{algorithm: "RS256", keyFormat: "pkcs8", blockType: "PRIVATE KEY"},
+		{algorithm: "RS256", keyFormat: "pkcs1", blockType: "RSA PRIVATE KEY"},
+		{algorithm: "EdDSA", keyFormat: "pkcs8", blockType: "PRIVATE KEY"},
+	} {
+		t.Run(test.algorithm+"-"+test.keyFormat, func(t *testing.T) {
+			app := jwtApp("", v1.GenericMap{
+				"algorithm": test.algorithm,
+				"keyFormat": test.keyFormat,
+			})
+			req := router.Request{
+				Ctx:    context.Background(),
+				Client: &tester.Client{SchemeObj: scheme.Scheme},
+				Object: app,
+			}
+
+			secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "signing")
+			require.NoError(t, err)
+
+			block, _ := pem.Decode(secret.Data[secrets.JWTPrivateKeyKey])
+			require.NotNil(t, block)
+			assert.Equal(t, test.blockType, block.Type)
+			if test.keyFormat == "pkcs1" {
+				_, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+			} else {
+				_, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestJWTKeyFormatInvalid(t *testing.T) {
+	for _, params := range []v1.GenericMap{
+		{"algorithm": "EdDSA", "keyFormat": "pkcs1"},
+		{"algorithm": "ES256", "keyFormat": "pkcs1"},
+		{"keyFormat": "der"},
+	} {
+		genErr := generationError(t, "signing", map[string]v1.Secret{
+			"signing": {
+				Type:   "jwt",
+				Params: params,
+			},
+		})
+		assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+		assert.Error(t, secrets.ValidateParams(v1.Secret{Type: "jwt", Params: params}))
+	}
+}
+
+func TestJWTRotation(t *testing.T) {
+	oldKeys, err := json.Marshal(secrets.JWKS{
+		Keys: []secrets.JWK{
+			{KeyType: "OKP", Curve: "Ed25519", X: "Y3VycmVudA", KeyID: "current"},
+			{KeyType: "OKP", Curve: "Ed25519", X: "cHJldmlvdXM", KeyID: "previous"},
+		},
+	})
+	require.NoError(t, err)
+
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "signing-abcde",
+			Namespace: "app-ns",
+			Labels: map[string]string{
+				labels.AcornAppName:         "app-name",
+				labels.AcornManaged:         "true",
+				labels.AcornSecretName:      "signing",
+				labels.AcornSecretGenerated: "true",
+			},
+			Annotations: map[string]string{
+				labels.AcornSecretRegenerate: "1",
+			},
+		},
+		Data: map[string][]byte{
+			secrets.JWTPrivateKeyKey: []byte("old private key"),
+			secrets.JWTJWKSKey:       oldKeys,
+			secrets.JWTKeyIDKey:      []byte("current"),
+		},
+		Type: v1.SecretTypeJWT,
+	}
+
+	h := tester.Harness{
+		Scheme:   scheme.Scheme,
+		Existing: []kclient.Object{existing},
+	}
+	resp, err := h.InvokeFunc(t, jwtApp("2", v1.GenericMap{
+		"algorithm":  "EdDSA",
+		"keyId":      "new",
+		"retainKeys": int64(1),
+	}), CreateSecrets)
+	require.NoError(t, err)
+
+	var updated *corev1.Secret
+	for _, obj := range resp.Client.Updated {
+		if secret, ok := obj.(*corev1.Secret); ok {
+			updated = secret
+		}
+	}
+	require.NotNil(t, updated)
+	assert.Equal(t, "new", string(updated.Data[secrets.JWTKeyIDKey]))
+	assert.NotEqual(t, "old private key", string(updated.Data[secrets.JWTPrivateKeyKey]))
+
+	// The new key is first and only the most recent previous key is kept for verification
+	var keySet secrets.JWKS
+	require.NoError(t, json.Unmarshal(updated.Data[secrets.JWTJWKSKey], &keySet))
+	var kids []string
+	for _, key := range keySet.Keys {
+		kids = append(kids, key.KeyID)
+	}
+	assert.Equal(t, []string{"new", "current"}, kids)
+}
@@ -0,0 +1,44 @@
+package secrets
+
+import (
+	"sort"
+	"sync"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+)
+
+// pending records the apps whose secrets are missing or waiting on a generation job as of their last reconcile
+var pending = pendingApps{
+	apps: map[string]bool{},
+}
+
+type pendingApps struct {
+	lock sync.Mutex
+	apps map[string]bool
+}
+
+func (p *pendingApps) set(app *v1.AppInstance, blocked bool) {
+	key := app.Namespace + "/" + app.Name
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if blocked {
+		p.apps[key] = true
+	} else {
+		delete(p.apps, key)
+	}
+}
+
+// PendingApps returns the namespace/name keys of the apps that were blocked on missing secrets or pending secret
+// generation jobs the last time their secrets were reconciled. Apps that have since been deleted may still be included.
+func PendingApps() []string {
+	pending.lock.Lock()
+	defer pending.lock.Unlock()
+
+	result := make([]string, 0, len(pending.apps))
+	for key := range pending.apps {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
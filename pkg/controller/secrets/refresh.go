@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"strings"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RefreshDockerTokens runs the credential helper jobs of a docker secret again once its tokens are due for a refresh.
+// The jobs are deleted, so that the app creates them again, and the secret gets the new tokens when they finish.
+// Scheduled jobs are left to their schedule.
+func RefreshDockerTokens(req router.Request, resp router.Response) error {
+	secret := req.Object.(*corev1.Secret)
+	value := secret.Annotations[labels.AcornSecretTokenRefresh]
+	if value == "" || secret.Annotations[labels.AcornSecretTokenJobs] == "" {
+		return nil
+	}
+
+	refreshAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		logrus.Warnf("not refreshing the tokens of secret %s/%s: %v", secret.Namespace, secret.Name, err)
+		return nil
+	}
+	if wait := time.Until(refreshAt); wait > 0 {
+		resp.RetryAfter(wait)
+		return nil
+	}
+
+	appNamespace := secret.Labels[labels.AcornAppNamespace]
+	if appNamespace == "" {
+		appNamespace = secret.Namespace
+	}
+	app := &v1.AppInstance{}
+	if err := req.Get(app, appNamespace, secret.Labels[labels.AcornAppName]); apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, jobName := range strings.Split(secret.Annotations[labels.AcornSecretTokenJobs], ",") {
+		job := &batchv1.Job{}
+		if err := req.Get(job, app.Status.Namespace, jobName); apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		// A job that is still running, or finished after the refresh time, is already writing the new token
+		if job.Status.CompletionTime == nil || !job.Status.CompletionTime.Time.Before(refreshAt) {
+			continue
+		}
+
+		logrus.Infof("running job %s/%s again to refresh the token of secret %s/%s", job.Namespace, job.Name,
+			secret.Namespace, secret.Name)
+		if err := req.Client.Delete(req.Ctx, job, kclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil &&
+			!apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
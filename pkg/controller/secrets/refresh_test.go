@@ -0,0 +1,109 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// tokenObjects returns a docker secret whose token is due for a refresh at refreshAt, the app it was generated for,
+// and the credential helper job that finished at completed
+func tokenObjects(refreshAt, completed time.Time) []kclient.Object {
+	return []kclient.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pull-creds-abcde",
+				Namespace: "app-namespace",
+				Labels: map[string]string{
+					labels.AcornAppName:         "app",
+					labels.AcornManaged:         "true",
+					labels.AcornSecretName:      "pull-creds",
+					labels.AcornSecretGenerated: "true",
+				},
+				Annotations: map[string]string{
+					labels.AcornSecretTokenJobs:    "ecr-login",
+					labels.AcornSecretTokenRefresh: refreshAt.UTC().Format(time.RFC3339),
+				},
+			},
+		},
+		&v1.AppInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app",
+				Namespace: "app-namespace",
+			},
+			Status: v1.AppInstanceStatus{
+				Namespace: "app-created-namespace",
+			},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ecr-login",
+				Namespace: "app-created-namespace",
+			},
+			Status: batchv1.JobStatus{
+				Succeeded:      1,
+				CompletionTime: &metav1.Time{Time: completed},
+			},
+		},
+	}
+}
+
+// refreshDockerTokens runs RefreshDockerTokens for the docker secret, as stored by the client
+func refreshDockerTokens(t *testing.T, c kclient.Client) *tester.Response {
+	t.Helper()
+	secret := &corev1.Secret{}
+	if err := c.Get(context.Background(), router.Key("app-namespace", "pull-creds-abcde"), secret); err != nil {
+		t.Fatal(err)
+	}
+	resp := &tester.Response{}
+	if err := RefreshDockerTokens(router.Request{
+		Client: c,
+		Ctx:    context.Background(),
+		Object: secret,
+	}, resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestRefreshDockerTokens(t *testing.T) {
+	now := time.Now()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tokenObjects(now.Add(-time.Minute), now.Add(-time.Hour))...).Build()
+
+	// the token is due for a refresh, so the job that wrote it is deleted to run it again
+	refreshDockerTokens(t, c)
+	err := c.Get(context.Background(), router.Key("app-created-namespace", "ecr-login"), &batchv1.Job{})
+	assert.True(t, apierrors.IsNotFound(err), err)
+}
+
+func TestRefreshDockerTokensNotDue(t *testing.T) {
+	now := time.Now()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tokenObjects(now.Add(time.Hour), now.Add(-time.Hour))...).Build()
+
+	// the token is checked again when it is due for a refresh
+	resp := refreshDockerTokens(t, c)
+	assert.NoError(t, c.Get(context.Background(), router.Key("app-created-namespace", "ecr-login"), &batchv1.Job{}))
+	assert.InDelta(t, float64(time.Hour), float64(resp.Delay), float64(time.Minute))
+}
+
+func TestRefreshDockerTokensAlreadyRefreshed(t *testing.T) {
+	now := time.Now()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tokenObjects(now.Add(-time.Hour), now.Add(-time.Minute))...).Build()
+
+	// the job finished again after the refresh time, the secret is waiting to get its new token
+	refreshDockerTokens(t, c)
+	assert.NoError(t, c.Get(context.Background(), router.Key("app-created-namespace", "ecr-login"), &batchv1.Job{}))
+}
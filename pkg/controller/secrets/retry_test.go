@@ -0,0 +1,100 @@
+package secrets
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func opaqueSecretApp() *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "opaque",
+						Data: map[string]string{
+							"key": "value",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// failGetOrCreateSecret replaces getOrCreateSecret so that the first failures calls return err
+func failGetOrCreateSecret(t *testing.T, failures int, err error) *int {
+	t.Helper()
+	calls := 0
+	oldGetOrCreateSecret, oldRetry := getOrCreateSecret, transientRetry
+	t.Cleanup(func() {
+		getOrCreateSecret, transientRetry = oldGetOrCreateSecret, oldRetry
+	})
+
+	transientRetry.Duration = time.Millisecond
+	getOrCreateSecret = func(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, secretName string) (*corev1.Secret, error) {
+		calls++
+		if calls <= failures {
+			return nil, err
+		}
+		return oldGetOrCreateSecret(secrets, req, appInstance, secretName)
+	}
+	return &calls
+}
+
+func TestTransientErrorRetried(t *testing.T) {
+	calls := failGetOrCreateSecret(t, 2, apierrors.NewServiceUnavailable("try again"))
+
+	app := opaqueSecretApp()
+	resp, err := (&tester.Harness{Scheme: scheme.Scheme}).InvokeFunc(t, app, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 3, *calls)
+	assert.Len(t, resp.Collected, 2)
+	assert.True(t, app.Status.Condition(v1.AppInstanceConditionSecrets).Success)
+}
+
+func TestTransientErrorExhausted(t *testing.T) {
+	calls := failGetOrCreateSecret(t, 100, apierrors.NewTooManyRequests("slow down", 1))
+
+	app := opaqueSecretApp()
+	resp, err := (&tester.Harness{Scheme: scheme.Scheme, ExpectedDelay: transientRequeue}).InvokeFunc(t, app, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, transientRetry.Steps, *calls)
+	assert.Equal(t, transientRequeue, resp.Delay)
+	assert.Len(t, resp.Collected, 1)
+	cond := app.Status.Condition(v1.AppInstanceConditionSecrets)
+	assert.True(t, cond.Error)
+	assert.Contains(t, cond.Message, "errored: [pass: ")
+}
+
+func TestPermanentErrorNotRetried(t *testing.T) {
+	calls := failGetOrCreateSecret(t, 100, apierrors.NewForbidden(corev1.Resource("secrets"), "pass", errors.New("denied")))
+
+	_, err := (&tester.Harness{Scheme: scheme.Scheme}).InvokeFunc(t, opaqueSecretApp(), CreateSecrets)
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.Equal(t, 1, *calls)
+}
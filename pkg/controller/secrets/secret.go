@@ -5,17 +5,44 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/condition"
 	"github.com/acorn-io/acorn/pkg/jobs"
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/acorn/pkg/tracing"
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/typed"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// getOrCreateSecret is a variable so that tests can inject API failures
+	getOrCreateSecret = secrets.GetOrCreateSecret
+	// transientRetry controls how long transient API errors are retried before the secret is marked as errored
+	transientRetry = wait.Backoff{
+		Steps:    4,
+		Duration: 100 * time.Millisecond,
+		Factor:   2.0,
+		Jitter:   0.1,
+	}
+	// transientRequeue is how long to wait before reconciling the app again once transientRetry is exhausted
+	transientRequeue = 15 * time.Second
+	// maxConcurrentSecrets is the number of secrets of an app that are generated at the same time
+	maxConcurrentSecrets = 5
 )
 
 type secEntry struct {
@@ -23,27 +50,164 @@ type secEntry struct {
 	secret v1.Secret
 }
 
-func secretsOrdered(app *v1.AppInstance) (result []secEntry) {
-	var generated []secEntry
+type secretResult struct {
+	secret *corev1.Secret
+	err    error
+}
 
+// secretDependencies returns the names of the secrets that must be generated before the given secret. Secrets depend on
+// the secrets listed in their dependsOn, template secrets also depend on the secrets they reference, docker secrets
+// depend on the basic secret they read credentials from, and tls secrets depend on the secret holding their CA.
+// Generated secrets depend on every secret that is not itself generated or a template, because the job producing them
+// may consume any of those, unless that secret explicitly depends on the generated secret.
+func secretDependencies(app *v1.AppInstance, entry secEntry) []string {
+	result := slices.Clone(entry.secret.DependsOn)
+	switch entry.secret.Type {
+	case "template":
+		result = append(result, secrets.TemplateDependencies(entry.secret)...)
+	case "docker":
+		result = append(result, secrets.DockerDependencies(entry.secret)...)
+	case "tls":
+		result = append(result, secrets.TLSDependencies(entry.secret)...)
+	case "generated":
+		for _, other := range typed.Sorted(app.Status.AppSpec.Secrets) {
+			if other.Value.Type != "generated" && other.Value.Type != "template" && !slices.Contains(other.Value.DependsOn, entry.name) {
+				result = append(result, other.Key)
+			}
+		}
+	}
+	return result
+}
+
+// secretWaves groups the secrets of the app so that every secret is in a later wave than the secrets it depends on.
+// The secrets within a wave don't depend on each other and can be generated concurrently. An error is returned if the
+// dependencies of the secrets form a cycle. Secrets limited to profiles the app is not running with are left out.
+func secretWaves(app *v1.AppInstance) (result [][]secEntry, _ error) {
+	var remaining []secEntry
+	pending := map[string]bool{}
+	profiles := app.Spec.GetProfiles()
 	for _, entry := range typed.Sorted(app.Status.AppSpec.Secrets) {
-		if entry.Value.Type == "generated" || entry.Value.Type == "template" {
-			generated = append(generated, secEntry{name: entry.Key, secret: entry.Value})
-		} else {
-			result = append(result, secEntry{name: entry.Key, secret: entry.Value})
+		if !entry.Value.AppliesToProfiles(profiles) {
+			continue
+		}
+		remaining = append(remaining, secEntry{name: entry.Key, secret: entry.Value})
+		pending[entry.Key] = true
+	}
+
+	for len(remaining) > 0 {
+		var wave, next []secEntry
+		for _, entry := range remaining {
+			ready := true
+			for _, dep := range secretDependencies(app, entry) {
+				if dep != entry.name && pending[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				wave = append(wave, entry)
+			} else {
+				next = append(next, entry)
+			}
+		}
+
+		if len(wave) == 0 {
+			return nil, fmt.Errorf("secret dependency cycle: %s", strings.Join(findCycle(app, remaining), " -> "))
+		}
+
+		for _, entry := range wave {
+			delete(pending, entry.name)
+		}
+		result = append(result, wave)
+		remaining = next
+	}
+
+	return result, nil
+}
+
+// findCycle returns a dependency cycle among the given secrets, which must all be part of or depend on a cycle. The
+// first secret of the cycle is repeated at the end.
+func findCycle(app *v1.AppInstance, entries []secEntry) []string {
+	byName := map[string]secEntry{}
+	for _, entry := range entries {
+		byName[entry.name] = entry
+	}
+
+	var (
+		path    []string
+		visited = map[string]bool{}
+	)
+	current := entries[0]
+	for !visited[current.name] {
+		visited[current.name] = true
+		path = append(path, current.name)
+		for _, dep := range secretDependencies(app, current) {
+			if next, ok := byName[dep]; ok && dep != current.name {
+				current = next
+				break
+			}
 		}
 	}
-	return append(result, generated...)
+
+	cycle := path[slices.Index(path, current.name):]
+	return append(cycle, current.name)
+}
+
+// secretsOrdered returns the secrets of the app ordered so that every secret comes after the secrets it depends on
+func secretsOrdered(app *v1.AppInstance) (result []secEntry, _ error) {
+	waves, err := secretWaves(app)
+	if err != nil {
+		return nil, err
+	}
+	for _, wave := range waves {
+		result = append(result, wave...)
+	}
+	return result, nil
+}
+
+// generateWave gets or creates the secrets of a wave concurrently. Each secret is generated with its own copy of the
+// secrets generated so far, so template lookups never race, and the new secrets are merged back when it is done.
+func generateWave(allSecrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, wave []secEntry, results map[string]secretResult) {
+	var (
+		lock sync.Mutex
+		eg   errgroup.Group
+	)
+	eg.SetLimit(maxConcurrentSecrets)
+
+	for _, entry := range wave {
+		secretName := entry.name
+		eg.Go(func() error {
+			lock.Lock()
+			known := maps.Clone(allSecrets)
+			lock.Unlock()
+
+			secret, err := getOrCreateSecretWithRetry(known, req, appInstance, secretName)
+
+			lock.Lock()
+			defer lock.Unlock()
+			results[secretName] = secretResult{secret: secret, err: err}
+			for name, sec := range known {
+				if _, ok := allSecrets[name]; !ok {
+					allSecrets[name] = sec
+				}
+			}
+			return nil
+		})
+	}
+
+	_ = eg.Wait()
 }
 
 func CreateSecrets(req router.Request, resp router.Response) (err error) {
 	var (
-		missing     []string
-		errored     []string
-		waiting     []string
-		appInstance = req.Object.(*v1.AppInstance)
-		allSecrets  = map[string]*corev1.Secret{}
-		cond        = condition.Setter(appInstance, resp, v1.AppInstanceConditionSecrets)
+		missing      []string
+		errored      []string
+		waiting      []string
+		republishing []string
+		replaced     []string
+		appInstance  = req.Object.(*v1.AppInstance)
+		allSecrets   = map[string]*corev1.Secret{}
+		cond         = condition.Setter(appInstance, resp, v1.AppInstanceConditionSecrets)
 	)
 
 	defer func() {
@@ -52,6 +216,8 @@ func CreateSecrets(req router.Request, resp router.Response) (err error) {
 			return
 		}
 
+		pending.set(appInstance, len(missing)+len(waiting) > 0)
+
 		buf := strings.Builder{}
 		if len(missing) > 0 {
 			sort.Strings(missing)
@@ -80,15 +246,74 @@ func CreateSecrets(req router.Request, resp router.Response) (err error) {
 
 		if buf.Len() > 0 {
 			cond.Error(errors.New(buf.String()))
+		} else if len(republishing) > 0 {
+			sort.Strings(republishing)
+			cond.Unknown("republishing: [" + strings.Join(republishing, ", ") + "]")
+		} else if len(replaced) > 0 {
+			sort.Strings(replaced)
+			cond.Set(v1.Condition{
+				Success: true,
+				Message: "replaced invalid certificates: [" + strings.Join(replaced, ", ") + "]",
+			})
 		} else {
 			cond.Success()
 		}
 	}()
 
-	for _, entry := range secretsOrdered(appInstance) {
+	// the defaults are already declared if the app image was parsed in this reconcile, adding them again is a no-op
+	if err := secrets.AddProjectDefaults(req, appInstance); err != nil {
+		return err
+	}
+
+	errored = append(errored, undeclaredSecrets(appInstance)...)
+
+	waves, err := secretWaves(appInstance)
+	if err != nil {
+		errored = append(errored, err.Error())
+		return nil
+	}
+
+	published, err := publishedSecrets(req, appInstance)
+	if err != nil {
+		return err
+	}
+	// a published secret that is gone after the secrets were ready was deleted by someone else
+	wasReady := appInstance.Status.Condition(v1.AppInstanceConditionSecrets).Success
+
+	var (
+		ordered []secEntry
+		results = map[string]secretResult{}
+		failed  = map[string]bool{}
+	)
+	for _, wave := range waves {
+		var ready []secEntry
+		for _, entry := range wave {
+			if dependsOnFailed(appInstance, entry, failed) {
+				// the secret is generated in a later reconcile, once the secrets it depends on are generated
+				failed[entry.name] = true
+				continue
+			}
+			ready = append(ready, entry)
+		}
+
+		generateWave(allSecrets, req, appInstance, ready, results)
+		ordered = append(ordered, ready...)
+		for _, entry := range ready {
+			if results[entry.name].err != nil {
+				failed[entry.name] = true
+			}
+		}
+	}
+
+	for _, entry := range ordered {
 		secretName := entry.name
-		secret, err := secrets.GetOrCreateSecret(allSecrets, req, appInstance, secretName)
-		if apierrors.IsNotFound(err) {
+		secret, err := results[secretName].secret, results[secretName].err
+		if isTransient(err) {
+			// the retries in this reconcile are exhausted, the secret is generated again in a later one
+			errored = append(errored, fmt.Sprintf("%s: %v", secretName, err))
+			resp.RetryAfter(transientRequeue)
+			continue
+		} else if apierrors.IsNotFound(err) {
 			if status := (*apierrors.StatusError)(nil); errors.As(err, &status) && status.ErrStatus.Details != nil {
 				missing = append(missing, status.ErrStatus.Details.Name)
 			} else {
@@ -98,18 +323,28 @@ func CreateSecrets(req router.Request, resp router.Response) (err error) {
 		} else if apiError := apierrors.APIStatus(nil); errors.As(err, &apiError) {
 			cond.Error(err)
 			return err
-		} else if errors.Is(err, jobs.ErrJobNotDone) || errors.Is(err, jobs.ErrJobNoOutput) {
+		} else if isWaiting(err) {
 			waiting = append(waiting, fmt.Sprintf("%s: %v", secretName, err))
 			continue
 		} else if err != nil {
-			if strings.HasPrefix(err.Error(), "waiting") {
-				waiting = append(waiting, fmt.Sprintf("%s: %v", secretName, err))
-			} else {
-				errored = append(errored, fmt.Sprintf("%s: %v", secretName, err))
-			}
+			errored = append(errored, fmt.Sprintf("%s: %v", secretName, err))
 			continue
 		}
 
+		if secret.Annotations[labels.AcornSecretReplacedInvalid] != "" {
+			replaced = append(replaced, secretName)
+		}
+
+		if !entry.secret.IsPublished() {
+			// the secret only exists for other secrets to use, so it is generated but not created in the app namespace
+			continue
+		}
+
+		if wasReady && !published[secretName] {
+			// the secret is created again when the objects below are applied
+			republishing = append(republishing, secretName)
+		}
+
 		labelMap := map[string]string{
 			labels.AcornAppName:      appInstance.Name,
 			labels.AcornAppNamespace: appInstance.Namespace,
@@ -135,3 +370,130 @@ func CreateSecrets(req router.Request, resp router.Response) (err error) {
 
 	return nil
 }
+
+// dependsOnFailed returns true if the secret depends on a secret that could not be generated. The failed secrets
+// include the secrets that were skipped because of their own dependencies, so the check is transitive.
+func dependsOnFailed(app *v1.AppInstance, entry secEntry, failed map[string]bool) bool {
+	for _, dep := range secretDependencies(app, entry) {
+		if dep != entry.name && failed[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+// isWaiting returns true if the secret can't be generated yet because it is waiting on a job or another controller
+func isWaiting(err error) bool {
+	if errors.Is(err, jobs.ErrJobNotDone) || errors.Is(err, jobs.ErrJobNoOutput) {
+		return true
+	}
+	genErr := (*secrets.ErrSecretGeneration)(nil)
+	return errors.As(err, &genErr) && genErr.Reason == secrets.GenerationReasonWaiting
+}
+
+// publishedSecrets returns the names of the secrets that are published in the namespace of the app. Listing them
+// also triggers the app when one of them changes, so a published secret that is deleted is published again.
+func publishedSecrets(req router.Request, appInstance *v1.AppInstance) (map[string]bool, error) {
+	var secretList corev1.SecretList
+	err := req.List(&secretList, &kclient.ListOptions{
+		Namespace: appInstance.Status.Namespace,
+		LabelSelector: klabels.SelectorFromSet(map[string]string{
+			labels.AcornAppName:      appInstance.Name,
+			labels.AcornAppNamespace: appInstance.Namespace,
+			labels.AcornManaged:      "true",
+		}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(secretList.Items))
+	for _, secret := range secretList.Items {
+		result[secret.Name] = true
+	}
+	return result, nil
+}
+
+// undeclaredSecrets reports secrets that are mounted or referenced from the env of a container or job but are not
+// declared in the app's secrets, or are declared with publish set to false. Without this the pod would wait forever on
+// a secret volume that is never created. References to secrets of other apps (names containing a ".") are not checked.
+func undeclaredSecrets(appInstance *v1.AppInstance) (result []string) {
+	declared := map[string]bool{}
+	unpublished := map[string]bool{}
+	for secretName, secret := range appInstance.Status.AppSpec.Secrets {
+		declared[secretName] = true
+		unpublished[secretName] = !secret.IsPublished()
+	}
+	for _, binding := range appInstance.Spec.Secrets {
+		declared[binding.Target] = true
+		unpublished[binding.Target] = false
+	}
+
+	refs := map[string][]string{}
+	addRefs := func(workloadName string, container v1.Container) {
+		var names []string
+		for _, file := range container.Files {
+			names = append(names, file.Secret.Name)
+		}
+		for _, dir := range container.Dirs {
+			names = append(names, dir.Secret.Name)
+		}
+		for _, env := range appInstance.Status.AppSpec.ContainerEnvironment(container) {
+			names = append(names, env.Secret.Name)
+		}
+		for _, secretName := range names {
+			if secretName == "" || declared[secretName] && !unpublished[secretName] || strings.Contains(secretName, ".") {
+				continue
+			}
+			if !slices.Contains(refs[secretName], workloadName) {
+				refs[secretName] = append(refs[secretName], workloadName)
+			}
+		}
+	}
+
+	for _, workloads := range []map[string]v1.Container{appInstance.Status.AppSpec.Containers, appInstance.Status.AppSpec.Jobs} {
+		for _, entry := range typed.Sorted(workloads) {
+			addRefs(entry.Key, entry.Value)
+			for _, sidecar := range typed.Sorted(entry.Value.Sidecars) {
+				addRefs(entry.Key, sidecar.Value)
+			}
+		}
+	}
+
+	for _, entry := range typed.Sorted(refs) {
+		if unpublished[entry.Key] {
+			result = append(result, fmt.Sprintf("%s: secret is used by [%s] but is not published", entry.Key, strings.Join(entry.Value, ", ")))
+		} else {
+			result = append(result, fmt.Sprintf("%s: secret is used by [%s] but is not declared", entry.Key, strings.Join(entry.Value, ", ")))
+		}
+	}
+	return result
+}
+
+// isTransient returns true for API errors that are likely to succeed if the request is retried
+func isTransient(err error) bool {
+	return apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err)
+}
+
+func getOrCreateSecretWithRetry(allSecrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, secretName string) (secret *corev1.Secret, err error) {
+	req, span := tracing.Start(req, "getOrCreateSecret", attribute.String("acorn.secret", secretName))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	retryErr := retry.OnError(transientRetry, isTransient, func() error {
+		secret, err = getOrCreateSecret(allSecrets, req, appInstance, secretName)
+		if isTransient(err) {
+			return err
+		}
+		return nil
+	})
+	if retryErr != nil {
+		return nil, retryErr
+	}
+	return secret, err
+}
@@ -1,19 +1,43 @@
 package secrets
 
 import (
+	"context"
+	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/acorn-io/acorn/pkg/tracing"
+	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/acorn-io/baaah/pkg/uncached"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
+func TestMain(m *testing.M) {
+	// Generate one secret at a time so that the order of the objects created in the tester client is stable
+	maxConcurrentSecrets = 1
+	os.Exit(m.Run())
+}
+
 func TestSecretImageReference(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-image", CreateSecrets)
 }
@@ -70,6 +94,48 @@ func TestOpaque_Gen(t *testing.T) {
 	assert.True(t, len(secret.Data["key2"]) > 0)
 }
 
+func TestCreateSecretsTraced(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	_, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "opaque",
+						Data: map[string]string{
+							"key": "value",
+						},
+					},
+				},
+			},
+		},
+	}, tracing.Handler("CreateSecrets", CreateSecrets))
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	// Spans end in reverse order, the span of each secret is a child of the span of the reconcile
+	secretSpan, handlerSpan := spans[0], spans[1]
+	assert.Equal(t, "CreateSecrets", handlerSpan.Name)
+	assert.Contains(t, handlerSpan.Attributes, attribute.String("acorn.name", "app-name"))
+	assert.Equal(t, "getOrCreateSecret", secretSpan.Name)
+	assert.Contains(t, secretSpan.Attributes, attribute.String("acorn.secret", "pass"))
+	assert.Equal(t, handlerSpan.SpanContext.SpanID(), secretSpan.Parent.SpanID())
+	assert.Equal(t, handlerSpan.SpanContext.TraceID(), secretSpan.SpanContext.TraceID())
+}
+
 func TestBasic_Gen(t *testing.T) {
 	h := tester.Harness{
 		Scheme: scheme.Scheme,
@@ -374,3 +440,650 @@ func TestSecretLabelsAnnotations(t *testing.T) {
 	assert.Contains(t, secret.Annotations, "globalfromacornfilea")
 	assert.NotContains(t, secret.Annotations, "sec1fromacornfilea")
 }
+
+func TestUndeclaredSecretMount(t *testing.T) {
+	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-undeclared-mount", CreateSecrets)
+}
+
+func TestProjectDefaultSecrets(t *testing.T) {
+	// the default is declared for the app, and the secret the app declares itself wins over the default
+	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-project-defaults", CreateSecrets)
+}
+
+func TestUnknownSecretType(t *testing.T) {
+	// the secret is reported instead of being skipped silently
+	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-unknown-type", CreateSecrets)
+}
+
+func TestUnpublishedSecret(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"ca": {
+						Type:    "token",
+						Publish: new(bool),
+						Params: map[string]any{
+							"characters": "abc",
+							"length":     int64(8),
+						},
+					},
+					"leaf": {
+						Type: "template",
+						Data: map[string]string{
+							"template": "signed by ${secret://ca/token}",
+						},
+					},
+				},
+			},
+		},
+	}, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the backing secret of the CA is generated so the leaf can use it
+	require.Len(t, resp.Client.Created, 2)
+	ca := resp.Client.Created[0].(*corev1.Secret)
+	assert.Equal(t, "ca", ca.Labels[labels.AcornSecretName])
+	assert.NotEmpty(t, ca.Data["token"])
+
+	// only the leaf is published in the app namespace
+	require.Len(t, resp.Collected, 2)
+	leaf := resp.Collected[0].(*corev1.Secret)
+	assert.Equal(t, "leaf", leaf.Name)
+	assert.Equal(t, "app-target-ns", leaf.Namespace)
+	assert.Equal(t, "signed by "+string(ca.Data["token"]), string(leaf.Data["template"]))
+
+	app := resp.Collected[1].(*v1.AppInstance)
+	assert.True(t, app.Status.Condition(v1.AppInstanceConditionSecrets).Success)
+}
+
+func TestUnpublishedSecretMount(t *testing.T) {
+	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-unpublished-mount", CreateSecrets)
+}
+
+func regenerateTokenApp(regenerate string) *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "token",
+						Annotations: map[string]string{
+							labels.AcornSecretRegenerate: regenerate,
+						},
+						Params: v1.GenericMap{
+							"characters": "abc",
+							"length":     int64(8),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRegenerate_Gen(t *testing.T) {
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pass-abcde",
+			Namespace: "app-ns",
+			Labels: map[string]string{
+				labels.AcornAppName:         "app-name",
+				labels.AcornManaged:         "true",
+				labels.AcornSecretName:      "pass",
+				labels.AcornSecretGenerated: "true",
+			},
+			Annotations: map[string]string{
+				labels.AcornSecretRegenerate: "1",
+			},
+		},
+		Data: map[string][]byte{
+			"token": []byte("original"),
+		},
+		Type: v1.SecretTypeToken,
+	}
+
+	h := tester.Harness{
+		Scheme:   scheme.Scheme,
+		Existing: []kclient.Object{existing},
+	}
+
+	// The recorded value matches, so the existing token is kept
+	resp, err := h.InvokeFunc(t, regenerateTokenApp("1"), CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, resp.Client.Created)
+	for _, obj := range resp.Client.Updated {
+		if secret, ok := obj.(*corev1.Secret); ok {
+			assert.Equal(t, "original", string(secret.Data["token"]))
+		}
+	}
+
+	// Bumping the counter regenerates the token in the existing secret
+	resp, err = h.InvokeFunc(t, regenerateTokenApp("2"), CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, resp.Client.Created)
+
+	var updated *corev1.Secret
+	for _, obj := range resp.Client.Updated {
+		if secret, ok := obj.(*corev1.Secret); ok {
+			updated = secret
+		}
+	}
+	require.NotNil(t, updated)
+	assert.Equal(t, "pass-abcde", updated.Name)
+	assert.Equal(t, "2", updated.Annotations[labels.AcornSecretRegenerate])
+	assert.Regexp(t, "^[abc]{8}$", string(updated.Data["token"]))
+}
+
+// staleClient simulates reconciles racing on a cache that doesn't have the secrets created by each other yet, cached
+// lists are always empty. Created objects get a UID like they would from the API server.
+type staleClient struct {
+	kclient.Client
+}
+
+func (s staleClient) List(ctx context.Context, list kclient.ObjectList, opts ...kclient.ListOption) error {
+	if u, ok := list.(*uncached.HolderList); ok {
+		return s.Client.List(ctx, u.ObjectList, opts...)
+	}
+	return nil
+}
+
+func (s staleClient) Create(ctx context.Context, obj kclient.Object, opts ...kclient.CreateOption) error {
+	obj.SetUID(uuid.NewUUID())
+	return s.Client.Create(ctx, obj, opts...)
+}
+
+func TestConcurrentSecretCreation(t *testing.T) {
+	c := staleClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()}
+	req := router.Request{
+		Client: c,
+		Ctx:    context.Background(),
+	}
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "opaque",
+						Data: map[string]string{
+							"key": "value",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, 10)
+	)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pass")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	var remaining corev1.SecretList
+	require.NoError(t, c.Client.List(req.Ctx, &remaining, kclient.InNamespace("app-ns")))
+	if assert.Len(t, remaining.Items, 1) {
+		assert.Equal(t, "value", string(remaining.Items[0].Data["key"]))
+	}
+}
+
+func TestBackingSecretNamespace(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      system.ConfigName,
+			Namespace: system.Namespace,
+		},
+		Data: map[string]string{
+			"config": `{"backingSecretNamespace": "acorn-secrets"}`,
+		},
+	}).Build()
+	req := router.Request{
+		Client: c,
+		Ctx:    context.Background(),
+	}
+
+	// Apps with the same name in different namespaces share the backing secret namespace
+	created := map[string]string{}
+	for _, namespace := range []string{"app-ns", "other-ns"} {
+		app := &v1.AppInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-name",
+				Namespace: namespace,
+				UID:       types.UID(namespace + "-uid"),
+			},
+			Status: v1.AppInstanceStatus{
+				AppSpec: v1.AppSpec{
+					Secrets: map[string]v1.Secret{
+						"pass": {
+							Type: "opaque",
+							Data: map[string]string{
+								"key": namespace,
+							},
+						},
+					},
+				},
+			},
+		}
+
+		secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pass")
+		require.NoError(t, err)
+		assert.Equal(t, "acorn-secrets", secret.Namespace)
+		assert.Equal(t, namespace, secret.Labels[labels.AcornAppNamespace])
+		// the UID tells the backing secrets of a deleted app apart from those of a new app of the same name
+		assert.Equal(t, namespace+"-uid", secret.Labels[labels.AcornAppUID])
+		assert.Equal(t, namespace, string(secret.Data["key"]))
+		created[namespace] = secret.Name
+
+		// The secret is found again in the backing secret namespace instead of being created again
+		secret, err = secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pass")
+		require.NoError(t, err)
+		assert.Equal(t, created[namespace], secret.Name)
+	}
+
+	var backing corev1.SecretList
+	require.NoError(t, c.List(req.Ctx, &backing, kclient.InNamespace("acorn-secrets")))
+	assert.Len(t, backing.Items, 2)
+
+	for _, namespace := range []string{"app-ns", "other-ns"} {
+		var inApp corev1.SecretList
+		require.NoError(t, c.List(req.Ctx, &inApp, kclient.InNamespace(namespace)))
+		assert.Empty(t, inApp.Items)
+	}
+}
+
+func TestBoundSecretRequiredKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		secretType string
+		data       map[string][]byte
+		err        string
+	}{
+		{
+			name:       "tls missing key",
+			secretType: "tls",
+			data:       map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+			err:        "secret [bound] bound to secret [target] of type [tls] is missing keys [tls.key]",
+		},
+		{
+			name:       "basic missing keys",
+			secretType: "basic",
+			data:       map[string][]byte{"other": []byte("value")},
+			err:        "secret [bound] bound to secret [target] of type [basic] is missing keys [username, password]",
+		},
+		{
+			name:       "basic with keys",
+			secretType: "basic",
+			data:       map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+		},
+		{
+			name:       "opaque",
+			secretType: "opaque",
+			data:       map[string][]byte{"other": []byte("value")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &v1.AppInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app-name",
+					Namespace: "app-ns",
+				},
+				Spec: v1.AppInstanceSpec{
+					Secrets: []v1.SecretBinding{
+						{
+							Secret: "bound",
+							Target: "target",
+						},
+					},
+				},
+				Status: v1.AppInstanceStatus{
+					AppSpec: v1.AppSpec{
+						Secrets: map[string]v1.Secret{
+							"target": {
+								Type: tt.secretType,
+							},
+						},
+					},
+				},
+			}
+			req := router.Request{
+				Ctx: context.Background(),
+				Client: &tester.Client{
+					SchemeObj: scheme.Scheme,
+					Objects: []kclient.Object{
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "bound",
+								Namespace: "app-ns",
+							},
+							Data: tt.data,
+						},
+					},
+				},
+				Object: app,
+			}
+
+			secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "target")
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.data, secret.Data)
+		})
+	}
+}
+
+func TestSecretCompatLabels(t *testing.T) {
+	legacy := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pass-legacy",
+			Namespace: "app-ns",
+			Labels: map[string]string{
+				labels.AcornAppName:           "app-name",
+				labels.AcornManaged:           "true",
+				labels.AcornSecretGenerated:   "true",
+				"legacy.acorn.io/secret-name": "pass",
+			},
+		},
+		Data: map[string][]byte{
+			"token": []byte("legacy-token"),
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      system.ConfigName,
+			Namespace: system.Namespace,
+		},
+		Data: map[string]string{
+			"config": `{"secretCompatLabels": ["acorn.io/secret-name=legacy.acorn.io/secret-name"]}`,
+		},
+	}, legacy).Build()
+	req := router.Request{
+		Client: c,
+		Ctx:    context.Background(),
+	}
+
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "token",
+					},
+				},
+			},
+		},
+	}
+
+	// The legacy secret is found instead of a new one being generated, and gets the current labels
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "pass")
+	require.NoError(t, err)
+	assert.Equal(t, "pass-legacy", secret.Name)
+	assert.Equal(t, "legacy-token", string(secret.Data["token"]))
+	assert.Equal(t, "pass", secret.Labels[labels.AcornSecretName])
+
+	var all corev1.SecretList
+	require.NoError(t, c.List(req.Ctx, &all, kclient.InNamespace("app-ns")))
+	assert.Len(t, all.Items, 1)
+}
+
+func TestRepublishDeletedSecret(t *testing.T) {
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "opaque",
+						Data: map[string]string{
+							"key": "value",
+						},
+					},
+				},
+			},
+			Conditions: []v1.Condition{
+				{
+					Type:    v1.AppInstanceConditionSecrets,
+					Success: true,
+				},
+			},
+		},
+	}
+
+	// the published secret was deleted after the secrets of the app were ready
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	input := app.DeepCopy()
+	resp, err := h.InvokeFunc(t, input, CreateSecrets)
+	require.NoError(t, err)
+
+	var published *corev1.Secret
+	for _, obj := range resp.Collected {
+		if secret, ok := obj.(*corev1.Secret); ok && secret.Namespace == "app-target-ns" {
+			published = secret
+		}
+	}
+	require.NotNil(t, published, "deleted published secret was not created again")
+	assert.Equal(t, "pass", published.Name)
+	assert.Equal(t, []byte("value"), published.Data["key"])
+
+	cond := input.Status.Condition(v1.AppInstanceConditionSecrets)
+	assert.True(t, cond.Transitioning)
+	assert.Equal(t, "republishing: [pass]", cond.Message)
+
+	// once the secret exists again, the secrets of the app are ready
+	h.Existing = append(resp.Client.Created, published)
+	input = app.DeepCopy()
+	_, err = h.InvokeFunc(t, input, CreateSecrets)
+	require.NoError(t, err)
+
+	cond = input.Status.Condition(v1.AppInstanceConditionSecrets)
+	assert.True(t, cond.Success)
+	assert.Empty(t, cond.Message)
+}
+
+func TestDisallowedSecretTypes(t *testing.T) {
+	req := router.Request{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      system.ConfigName,
+				Namespace: system.Namespace,
+			},
+			Data: map[string]string{
+				"config": `{"disallowedSecretTypes": ["app-ns=generated"]}`,
+			},
+		}).Build(),
+		Ctx: context.Background(),
+	}
+	app := func(namespace string) *v1.AppInstance {
+		return &v1.AppInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-name",
+				Namespace: namespace,
+			},
+			Status: v1.AppInstanceStatus{
+				Namespace: "app-target-ns",
+				AppSpec: v1.AppSpec{
+					Secrets: map[string]v1.Secret{
+						"gen": {
+							Type: "generated",
+							Params: v1.GenericMap{
+								"job": "gen-job",
+							},
+						},
+						"pass": {
+							Type: "opaque",
+							Data: map[string]string{
+								"key": "value",
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	_, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app("app-ns"), "gen")
+	var genErr *secrets.ErrSecretGeneration
+	require.ErrorAs(t, err, &genErr)
+	assert.Equal(t, secrets.GenerationReasonDisallowed, genErr.Reason)
+	assert.Equal(t, "secret type [generated] is not allowed in project [app-ns]", err.Error())
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app("app-ns"), "pass")
+	require.NoError(t, err)
+	assert.Equal(t, "value", string(secret.Data["key"]))
+
+	// The type is only disallowed in the listed project
+	_, err = secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app("other-ns"), "gen")
+	require.ErrorAs(t, err, &genErr)
+	assert.NotEqual(t, secrets.GenerationReasonDisallowed, genErr.Reason)
+}
+
+func TestBasicCustomKeys_Gen(t *testing.T) {
+	h := tester.Harness{
+		Scheme: scheme.Scheme,
+	}
+	resp, err := h.InvokeFunc(t, &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"custom": {
+						Type: "basic",
+						Params: v1.GenericMap{
+							"usernameKey": "user",
+							"passwordKey": "pass",
+						},
+						Data: map[string]string{
+							"user": "admin",
+							"pass": "",
+						},
+					},
+					"default": {
+						Type: "basic",
+					},
+				},
+			},
+		},
+	}, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, resp.Client.Created, 2)
+	for _, obj := range resp.Client.Created {
+		secret := obj.(*corev1.Secret)
+		switch secret.Labels[labels.AcornSecretName] {
+		case "custom":
+			assert.Equal(t, v1.SecretTypeOpaque, secret.Type)
+			assert.Equal(t, "admin", string(secret.Data["user"]))
+			assert.Len(t, secret.Data["pass"], 16)
+			assert.NotContains(t, secret.Data, "username")
+			assert.NotContains(t, secret.Data, "password")
+		case "default":
+			assert.Equal(t, v1.SecretTypeBasic, secret.Type)
+			assert.Len(t, secret.Data["username"], 8)
+			assert.Len(t, secret.Data["password"], 16)
+		default:
+			t.Fatalf("unexpected secret %s", secret.Name)
+		}
+	}
+}
+
+func TestBasicCustomKeysSame(t *testing.T) {
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "basic",
+						Params: v1.GenericMap{
+							"usernameKey": "password",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	_, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, router.Request{
+		Client: &tester.Client{SchemeObj: scheme.Scheme},
+		Ctx:    context.Background(),
+	}, app, "pass")
+	var genErr *secrets.ErrSecretGeneration
+	require.ErrorAs(t, err, &genErr)
+	assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+	assert.Equal(t, "usernameKey and passwordKey must be different, both are [password]", err.Error())
+}
+
+func TestSecretProfiles(t *testing.T) {
+	// secrets limited to profiles the app is not running with are skipped, and not reported as missing
+	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-profiles-dev-mode", CreateSecrets)
+	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-profiles-dev", CreateSecrets)
+	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-profiles-prod", CreateSecrets)
+	tester.DefaultTest(t, scheme.Scheme, "testdata/secret-profiles-prod-optional", CreateSecrets)
+}
@@ -0,0 +1,341 @@
+package secrets
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert returns a new certificate signed by parent, or self-signed if parent is nil. The certificate is a CA only
+// if isCA is true.
+func newTestCert(t *testing.T, isCA bool, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// tlsApp returns an app with a tls secret named cert with the given params, and root and ca secrets bound to the
+// my-root and my-ca secrets
+func tlsApp(params v1.GenericMap) *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Spec: v1.AppInstanceSpec{
+			Secrets: []v1.SecretBinding{
+				{Secret: "my-root", Target: "root"},
+				{Secret: "my-ca", Target: "ca"},
+			},
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppImage: v1.AppImage{
+				ID: "test",
+			},
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"cert": {Type: "tls", Params: params},
+					"root": {Type: "opaque"},
+					"ca":   {Type: "opaque"},
+				},
+			},
+		},
+	}
+}
+
+func generateTLSSecret(t *testing.T, app *v1.AppInstance, objects ...kclient.Object) (*corev1.Secret, error) {
+	t.Helper()
+	req := router.Request{
+		Ctx: context.Background(),
+		Client: &tester.Client{
+			SchemeObj: scheme.Scheme,
+			Objects:   objects,
+		},
+		Object: app,
+	}
+	return secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, app, "cert")
+}
+
+func boundCA(name string, secretType corev1.SecretType, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "app-ns",
+		},
+		Type: secretType,
+		Data: data,
+	}
+}
+
+func parseTLSSecret(t *testing.T, secret *corev1.Secret) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestTLS_Gen(t *testing.T) {
+	secret, err := generateTLSSecret(t, tlsApp(v1.GenericMap{
+		"commonName": "web",
+		"sans":       []any{"web.example.com", "web", "10.0.0.10"},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, v1.SecretTypeTLS, secret.Type)
+
+	cert := parseTLSSecret(t, secret)
+	assert.Equal(t, "web", cert.Subject.CommonName)
+	assert.Equal(t, []string{"web.example.com", "web"}, cert.DNSNames)
+	assert.True(t, cert.IPAddresses[0].Equal(net.ParseIP("10.0.0.10")))
+
+	// the certificate is self-signed, so it is its own CA
+	assert.Equal(t, secret.Data[corev1.TLSCertKey], secret.Data[secrets.TLSCACertKey])
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(secret.Data[secrets.TLSCACertKey]))
+	_, err = cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "web.example.com"})
+	assert.NoError(t, err)
+}
+
+func TestTLSCIDRSANRejected(t *testing.T) {
+	_, err := generateTLSSecret(t, tlsApp(v1.GenericMap{"sans": []any{"web.example.com", "10.0.0.0/24"}}))
+	assert.EqualError(t, err, "invalid sans param entry [10.0.0.0/24], a certificate can't be valid for a CIDR, "+
+		"list each IP address as its own SAN instead for secret [cert]")
+	genErr := (*secrets.ErrSecretGeneration)(nil)
+	require.True(t, errors.As(err, &genErr))
+	assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+}
+
+func TestTLSNotBeforeSkew(t *testing.T) {
+	secret, err := generateTLSSecret(t, tlsApp(v1.GenericMap{"notBeforeSkew": "5m"}))
+	require.NoError(t, err)
+
+	cert := parseTLSSecret(t, secret)
+	assert.WithinDuration(t, time.Now().Add(-5*time.Minute), cert.NotBefore, 5*time.Second)
+	assert.WithinDuration(t, time.Now().Add(365*24*time.Hour), cert.NotAfter, 5*time.Second)
+
+	_, err = generateTLSSecret(t, tlsApp(v1.GenericMap{"notBeforeSkew": "-5m"}))
+	assert.EqualError(t, err, "invalid notBeforeSkew param [-5m], must be a duration such as 5m for secret [cert]")
+}
+
+func TestTLSSignedByCASecret(t *testing.T) {
+	ca := newTestCert(t, true, nil)
+	secret, err := generateTLSSecret(t, tlsApp(v1.GenericMap{"caSecret": "ca"}), boundCA("my-ca", v1.SecretTypeTLS, map[string][]byte{
+		secrets.TLSCACertKey: ca.certPEM,
+		secrets.TLSCAKeyKey:  ca.keyPEM,
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, ca.certPEM, secret.Data[secrets.TLSCACertKey])
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	_, err = parseTLSSecret(t, secret).Verify(x509.VerifyOptions{Roots: roots, DNSName: "cert"})
+	assert.NoError(t, err)
+}
+
+func TestTLSSignedByCAChain(t *testing.T) {
+	root := newTestCert(t, true, nil)
+	intermediate := newTestCert(t, true, root)
+	secret, err := generateTLSSecret(t, tlsApp(v1.GenericMap{"caSecrets": []any{"root", "ca"}}),
+		// the key of the root is not needed, only the intermediate signs
+		boundCA("my-root", v1.SecretTypeTLS, map[string][]byte{
+			secrets.TLSCACertKey: root.certPEM,
+		}),
+		boundCA("my-ca", v1.SecretTypeTLS, map[string][]byte{
+			secrets.TLSCACertKey: intermediate.certPEM,
+			secrets.TLSCAKeyKey:  intermediate.keyPEM,
+		}))
+	require.NoError(t, err)
+
+	// tls.crt has the leaf followed by the intermediate, ca.crt has the root
+	var chain []*x509.Certificate
+	for rest := secret.Data[corev1.TLSCertKey]; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		chain = append(chain, cert)
+	}
+	require.Len(t, chain, 2)
+	assert.Equal(t, intermediate.cert.Raw, chain[1].Raw)
+	assert.Equal(t, root.certPEM, secret.Data[secrets.TLSCACertKey])
+
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	roots.AddCert(root.cert)
+	intermediates.AddCert(chain[1])
+	_, err = chain[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, DNSName: "cert"})
+	assert.NoError(t, err)
+
+	// an intermediate that the root didn't sign is rejected
+	_, err = generateTLSSecret(t, tlsApp(v1.GenericMap{"caSecrets": []any{"root", "ca"}}),
+		boundCA("my-root", v1.SecretTypeTLS, map[string][]byte{
+			secrets.TLSCACertKey: newTestCert(t, true, nil).certPEM,
+		}),
+		boundCA("my-ca", v1.SecretTypeTLS, map[string][]byte{
+			secrets.TLSCACertKey: intermediate.certPEM,
+			secrets.TLSCAKeyKey:  intermediate.keyPEM,
+		}))
+	assert.ErrorContains(t, err, "CA in caSecret [ca] of secret [cert] is not signed by the CA in caSecret [root]")
+}
+
+func TestTLSCASecretInvalid(t *testing.T) {
+	ca := newTestCert(t, true, nil)
+	leaf := newTestCert(t, false, nil)
+	tests := []struct {
+		name string
+		ca   *corev1.Secret
+		err  string
+	}{
+		{
+			name: "not a tls secret",
+			ca: boundCA("my-ca", v1.SecretTypeBasic, map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("user"),
+				corev1.BasicAuthPasswordKey: []byte("pass"),
+			}),
+			err: "caSecret [ca] of secret [cert] is of type [basic], it must be a tls secret holding a CA",
+		},
+		{
+			name: "missing key",
+			ca: boundCA("my-ca", v1.SecretTypeTLS, map[string][]byte{
+				secrets.TLSCACertKey: ca.certPEM,
+			}),
+			err: "caSecret [ca] of secret [cert] is missing keys [ca.key], it must hold a CA that can sign certificates",
+		},
+		{
+			name: "mismatched key",
+			ca: boundCA("my-ca", v1.SecretTypeTLS, map[string][]byte{
+				secrets.TLSCACertKey: ca.certPEM,
+				secrets.TLSCAKeyKey:  leaf.keyPEM,
+			}),
+			err: "invalid CA in caSecret [ca] of secret [cert]: tls: private key does not match public key",
+		},
+		{
+			name: "not a CA",
+			ca: boundCA("my-ca", v1.SecretTypeTLS, map[string][]byte{
+				secrets.TLSCACertKey: leaf.certPEM,
+				secrets.TLSCAKeyKey:  leaf.keyPEM,
+			}),
+			err: "certificate in caSecret [ca] of secret [cert] is not a CA, it can't sign other certificates",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generateTLSSecret(t, tlsApp(v1.GenericMap{"caSecret": "ca"}), tt.ca)
+			assert.EqualError(t, err, tt.err)
+			genErr := (*secrets.ErrSecretGeneration)(nil)
+			require.True(t, errors.As(err, &genErr))
+			assert.Equal(t, secrets.GenerationReasonInvalidParams, genErr.Reason)
+		})
+	}
+}
+
+// generatedTLS returns the backing secret of the cert secret of tlsApp with the given data
+func generatedTLS(data map[string][]byte, annotations map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cert-abcde",
+			Namespace: "app-ns",
+			Labels: map[string]string{
+				labels.AcornAppName:         "app-name",
+				labels.AcornManaged:         "true",
+				labels.AcornSecretName:      "cert",
+				labels.AcornSecretGenerated: "true",
+			},
+			Annotations: annotations,
+		},
+		Type: v1.SecretTypeTLS,
+		Data: data,
+	}
+}
+
+func TestTLSReplacesInvalidCert(t *testing.T) {
+	app := tlsApp(nil)
+	app.Spec.Secrets = nil
+	app.Status.AppSpec.Secrets = map[string]v1.Secret{"cert": {Type: "tls"}}
+
+	corrupt := map[string][]byte{
+		corev1.TLSCertKey:       []byte("cert"),
+		corev1.TLSPrivateKeyKey: []byte("key"),
+	}
+	resp, err := (&tester.Harness{
+		Scheme:   scheme.Scheme,
+		Existing: []kclient.Object{generatedTLS(corrupt, nil)},
+	}).InvokeFunc(t, app, CreateSecrets)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Client.Updated, 1)
+	secret := resp.Client.Updated[0].(*corev1.Secret)
+	_, err = tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid certificate in [tls.crt]: no PEM data found", secret.Annotations[labels.AcornSecretReplacedInvalid])
+
+	cond := app.Status.Condition(v1.AppInstanceConditionSecrets)
+	assert.True(t, cond.Success)
+	assert.Equal(t, "replaced invalid certificates: [cert]", cond.Message)
+
+	// a certificate that is invalid again after it was replaced is not replaced again
+	_, err = generateTLSSecret(t, app, generatedTLS(corrupt, secret.Annotations))
+	assert.EqualError(t, err, "certificate of secret [cert] is invalid after it was replaced: invalid certificate in [tls.crt]: no PEM data found")
+
+	// the reason is kept while the replaced certificate is valid
+	secret, err = generateTLSSecret(t, app, generatedTLS(secret.Data, secret.Annotations))
+	require.NoError(t, err)
+	assert.Equal(t, "invalid certificate in [tls.crt]: no PEM data found", secret.Annotations[labels.AcornSecretReplacedInvalid])
+}
@@ -0,0 +1,254 @@
+package secrets
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSecretWaves(t *testing.T) {
+	app := &v1.AppInstance{
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"a":   {Type: "opaque"},
+					"b":   {Type: "basic"},
+					"c":   {Type: "token"},
+					"gen": {Type: "generated"},
+					"tpl": {
+						Type: "template",
+						Data: map[string]string{
+							"url": "${secret://a/key}@${secret://gen/content}",
+						},
+					},
+					"tpl-b": {
+						Type: "template",
+						Data: map[string]string{
+							"user": "${secret://b/username}",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, [][]string{
+		{"a", "b", "c"},
+		{"gen", "tpl-b"},
+		{"tpl"},
+	}, waveNames(t, app))
+}
+
+func TestSecretDependsOnOrdering(t *testing.T) {
+	app := &v1.AppInstance{
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"a": {Type: "opaque", DependsOn: []string{"gen"}},
+					"b": {Type: "basic"},
+					"gen": {
+						Type:      "generated",
+						DependsOn: []string{"tpl"},
+					},
+					"tpl": {
+						Type: "template",
+						Data: map[string]string{
+							"user": "${secret://b/username}",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, [][]string{
+		{"b"},
+		{"tpl"},
+		{"gen"},
+		{"a"},
+	}, waveNames(t, app))
+
+	ordered, err := secretsOrdered(app)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range ordered {
+		names = append(names, entry.name)
+	}
+	assert.Equal(t, []string{"b", "tpl", "gen", "a"}, names)
+}
+
+func TestSecretDependencyCycle(t *testing.T) {
+	app := &v1.AppInstance{
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"a":    {Type: "opaque", DependsOn: []string{"b"}},
+					"b":    {Type: "opaque", DependsOn: []string{"c"}},
+					"c":    {Type: "opaque", DependsOn: []string{"a"}},
+					"d":    {Type: "opaque", DependsOn: []string{"a"}},
+					"self": {Type: "opaque", DependsOn: []string{"self"}},
+				},
+			},
+		},
+	}
+
+	_, err := secretWaves(app)
+	assert.EqualError(t, err, "secret dependency cycle: a -> b -> c -> a")
+}
+
+func waveNames(t *testing.T, app *v1.AppInstance) (result [][]string) {
+	t.Helper()
+	waves, err := secretWaves(app)
+	require.NoError(t, err)
+	for _, wave := range waves {
+		var names []string
+		for _, entry := range wave {
+			names = append(names, entry.name)
+		}
+		result = append(result, names)
+	}
+	return result
+}
+
+func TestSecretGenerationConcurrency(t *testing.T) {
+	var (
+		lock      sync.Mutex
+		active    int
+		maxActive int
+		finished  = map[string]bool{}
+		startedAt = map[string][]string{}
+	)
+
+	oldGetOrCreateSecret, oldMaxConcurrentSecrets := getOrCreateSecret, maxConcurrentSecrets
+	t.Cleanup(func() {
+		getOrCreateSecret, maxConcurrentSecrets = oldGetOrCreateSecret, oldMaxConcurrentSecrets
+	})
+	maxConcurrentSecrets = 5
+	getOrCreateSecret = func(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, secretName string) (*corev1.Secret, error) {
+		lock.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		for name := range finished {
+			startedAt[secretName] = append(startedAt[secretName], name)
+		}
+		lock.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+
+		lock.Lock()
+		active--
+		finished[secretName] = true
+		lock.Unlock()
+		return &corev1.Secret{}, nil
+	}
+
+	app := &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"ca":   {Type: "opaque"},
+					"one":  {Type: "token"},
+					"two":  {Type: "token"},
+					"leaf": {Type: "template", Data: map[string]string{"ca": "${secret://ca/cert}"}},
+				},
+			},
+		},
+	}
+	if _, err := (&tester.Harness{Scheme: scheme.Scheme}).InvokeFunc(t, app, CreateSecrets); err != nil {
+		t.Fatal(err)
+	}
+
+	// The independent secrets were generated at the same time, but the leaf only started after the ca was done
+	assert.Equal(t, 3, maxActive)
+	assert.Contains(t, startedAt["leaf"], "ca")
+	assert.True(t, app.Status.Condition(v1.AppInstanceConditionSecrets).Success)
+}
+
+// waveFailureApp returns an app whose leaf secret is generated in a later wave than the ca secret it depends on, next to
+// a conn secret that only depends on the one secret, and replaces getOrCreateSecret so that generating the ca fails
+// with err. The returned map records the generated secrets.
+func waveFailureApp(t *testing.T, err error) (*v1.AppInstance, map[string]bool) {
+	t.Helper()
+	var (
+		lock   sync.Mutex
+		called = map[string]bool{}
+	)
+	oldGetOrCreateSecret := getOrCreateSecret
+	t.Cleanup(func() {
+		getOrCreateSecret = oldGetOrCreateSecret
+	})
+	getOrCreateSecret = func(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, secretName string) (*corev1.Secret, error) {
+		lock.Lock()
+		called[secretName] = true
+		lock.Unlock()
+		if secretName == "ca" {
+			return nil, err
+		}
+		return &corev1.Secret{}, nil
+	}
+
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			Namespace: "app-target-ns",
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"ca":   {Type: "opaque"},
+					"one":  {Type: "token"},
+					"leaf": {Type: "template", Data: map[string]string{"ca": "${secret://ca/cert}"}},
+					"conn": {Type: "template", Data: map[string]string{"token": "${secret://one/token}"}},
+				},
+			},
+		},
+	}, called
+}
+
+func TestSecretWaveFailureSkipsDependents(t *testing.T) {
+	app, called := waveFailureApp(t, errors.New("bad ca"))
+	resp, err := (&tester.Harness{Scheme: scheme.Scheme}).InvokeFunc(t, app, CreateSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the leaf that depends on the ca is skipped, but conn in the same wave is still generated and published, so
+	// applying the objects doesn't delete it
+	assert.Equal(t, map[string]bool{"ca": true, "one": true, "conn": true}, called)
+	var published []string
+	for _, obj := range resp.Collected {
+		if secret, ok := obj.(*corev1.Secret); ok {
+			published = append(published, secret.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{"one", "conn"}, published)
+	cond := app.Status.Condition(v1.AppInstanceConditionSecrets)
+	assert.True(t, cond.Error)
+	assert.Equal(t, "errored: [ca: bad ca]", cond.Message)
+}
+
+func TestSecretWaveAPIErrorReturns(t *testing.T) {
+	app, called := waveFailureApp(t, apierrors.NewForbidden(corev1.Resource("secrets"), "ca", errors.New("denied")))
+	_, err := (&tester.Harness{Scheme: scheme.Scheme}).InvokeFunc(t, app, CreateSecrets)
+	assert.True(t, apierrors.IsForbidden(err), err)
+	assert.False(t, called["leaf"])
+}
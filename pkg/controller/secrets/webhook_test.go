@@ -0,0 +1,143 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func webhookRequest(t *testing.T, webhookURL string) router.Request {
+	t.Helper()
+	return router.Request{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      system.ConfigName,
+				Namespace: system.Namespace,
+			},
+			Data: map[string]string{
+				"config": fmt.Sprintf(`{"secretWebhookURL": %q}`, webhookURL),
+			},
+		}).Build(),
+		Ctx: context.Background(),
+	}
+}
+
+func webhookApp(regenerate string) *v1.AppInstance {
+	return &v1.AppInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-name",
+			Namespace: "app-ns",
+		},
+		Status: v1.AppInstanceStatus{
+			AppSpec: v1.AppSpec{
+				Secrets: map[string]v1.Secret{
+					"pass": {
+						Type: "token",
+						Params: v1.GenericMap{
+							"characters": "abcdef",
+							"length":     int64(16),
+						},
+						Annotations: map[string]string{
+							labels.AcornSecretRegenerate: regenerate,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSecretWebhook(t *testing.T) {
+	notifications := make(chan map[string]any, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var notification map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+		notifications <- notification
+	}))
+	defer server.Close()
+
+	req := webhookRequest(t, server.URL)
+	next := func() map[string]any {
+		select {
+		case notification := <-notifications:
+			return notification
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the webhook notification")
+			return nil
+		}
+	}
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, webhookApp("1"), "pass")
+	require.NoError(t, err)
+	token := string(secret.Data["token"])
+
+	notification := next()
+	timestamp, err := time.Parse(time.RFC3339, notification["timestamp"].(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), timestamp, time.Minute)
+	// Only these fields are sent, so the value of the secret is never part of the notification
+	delete(notification, "timestamp")
+	assert.Equal(t, map[string]any{
+		"event":        "created",
+		"app":          "app-name",
+		"appNamespace": "app-ns",
+		"secret":       "pass",
+		"type":         "token",
+	}, notification)
+
+	// Reading the secret again doesn't notify
+	_, err = secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, webhookApp("1"), "pass")
+	require.NoError(t, err)
+
+	secret, err = secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, req, webhookApp("2"), "pass")
+	require.NoError(t, err)
+	assert.NotEqual(t, token, string(secret.Data["token"]))
+	assert.Equal(t, "regenerated", next()["event"])
+
+	select {
+	case notification := <-notifications:
+		t.Fatalf("unexpected notification %v", notification)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSecretWebhookFailure(t *testing.T) {
+	called := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called <- struct{}{}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	secret, err := secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, webhookRequest(t, server.URL), webhookApp("1"), "pass")
+	require.NoError(t, err)
+	assert.Len(t, secret.Data["token"], 16)
+
+	select {
+	case <-called:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the webhook to be called")
+	}
+
+	// An unreachable webhook doesn't fail the secret either
+	_, err = secrets.GetOrCreateSecret(map[string]*corev1.Secret{}, webhookRequest(t, "http://127.0.0.1:1"), webhookApp("1"), "pass")
+	require.NoError(t, err)
+}
@@ -102,6 +102,89 @@ func TestBindNoProtocol(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/service/bind-no-protocol", RenderServices)
 }
 
+func TestServiceSessionAffinity(t *testing.T) {
+	tester.DefaultTest(t, scheme.Scheme, "testdata/service/session-affinity", RenderServices)
+}
+
 func TestRouter(t *testing.T) {
 	tester.DefaultTest(t, scheme.Scheme, "testdata/router", RenderServices)
 }
+
+func TestIngressPaths(t *testing.T) {
+	harness, input, err := tester.FromDir(scheme.Scheme, "testdata/ingress/paths")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := harness.Invoke(t, input, router.HandlerFunc(RenderServices))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ingress *v1.Ingress
+	for _, obj := range resp.Collected {
+		if i, ok := obj.(*v1.Ingress); ok {
+			ingress = i
+		}
+	}
+	if ingress == nil {
+		t.Fatal("no ingress created")
+	}
+
+	prefix, exact := v1.PathTypePrefix, v1.PathTypeExact
+	paths := []v1.HTTPIngressPath{
+		{Path: "/", PathType: &prefix, Backend: backend("web", 80)},
+		{Path: "/api", PathType: &prefix, Backend: backend("web", 8080)},
+		{Path: "/healthz", PathType: &exact, Backend: backend("web", 9000)},
+	}
+
+	// the paths of all the ports are in one rule for each hostname of the service
+	if assert.Len(t, ingress.Spec.Rules, 2) {
+		assert.Equal(t, "web-app-name-24748df3.local.on-acorn.io", ingress.Spec.Rules[0].Host)
+		assert.Equal(t, paths, ingress.Spec.Rules[0].HTTP.Paths)
+		assert.Equal(t, "app.example.com", ingress.Spec.Rules[1].Host)
+		assert.Equal(t, paths, ingress.Spec.Rules[1].HTTP.Paths)
+	}
+	assert.Equal(t, `{"app.example.com":{"port":3000,"service":"web"},"web-app-name-24748df3.local.on-acorn.io":{"port":3000,"service":"web"}}`,
+		ingress.Annotations["acorn.io/targets"])
+}
+
+func backend(service string, port int32) v1.IngressBackend {
+	return v1.IngressBackend{
+		Service: &v1.IngressServiceBackend{
+			Name: service,
+			Port: v1.ServiceBackendPort{Number: port},
+		},
+	}
+}
+
+func TestIngressClassName(t *testing.T) {
+	harness, input, err := tester.FromDir(scheme.Scheme, "testdata/ingress/ingressclass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := harness.Invoke(t, input, router.HandlerFunc(RenderServices))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ingresses := map[string]*v1.Ingress{}
+	for _, obj := range resp.Collected {
+		if i, ok := obj.(*v1.Ingress); ok {
+			ingresses[i.Name] = i
+		}
+	}
+
+	// the port with an ingress class gets its own Ingress, the other keeps the default class
+	if assert.Len(t, ingresses, 2) {
+		assert.Nil(t, ingresses["web"].Spec.IngressClassName)
+		if assert.Len(t, ingresses["web"].Spec.Rules, 1) {
+			assert.Equal(t, backend("web", 80), ingresses["web"].Spec.Rules[0].HTTP.Paths[0].Backend)
+		}
+		if assert.NotNil(t, ingresses["web-nginx"]) {
+			assert.Equal(t, "nginx", *ingresses["web-nginx"].Spec.IngressClassName)
+			if assert.Len(t, ingresses["web-nginx"].Spec.Rules, 1) {
+				assert.Equal(t, backend("web", 81), ingresses["web-nginx"].Spec.Rules[0].HTTP.Paths[0].Backend)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package dev
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/acorn-io/acorn/pkg/buildclient"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/imagesource"
+	"github.com/sirupsen/logrus"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// cacheRepo is the repository of the tags that record the cache key an image was built from
+const cacheRepo = "acorn-dev-cache"
+
+func cacheTag(key string) string {
+	return cacheRepo + ":" + key
+}
+
+// cacheKey hashes everything the build of the image source depends on: the watched files, the files of the build
+// context that are not ignored and the args, profiles and platforms of the build. An empty key is returned if the
+// image source is not built.
+func cacheKey(ctx context.Context, c client.Client, source imagesource.ImageSource) (string, error) {
+	_, file, err := source.ResolveImageAndFile()
+	if err != nil || file == "" {
+		return "", err
+	}
+
+	contextDir, err := source.ResolveContextDir()
+	if err != nil {
+		return "", err
+	}
+
+	watched, err := source.WatchFiles(ctx, c)
+	if err != nil {
+		return "", err
+	}
+
+	contextFiles, err := buildclient.ContextFiles(contextDir)
+	if err != nil {
+		return "", err
+	}
+
+	fileSet := map[string]bool{}
+	for _, f := range append(watched, contextFiles...) {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return "", err
+		}
+		fileSet[abs] = true
+	}
+
+	files := make([]string, 0, len(fileSet))
+	for f := range fileSet {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	d := sha256.New()
+	for _, part := range [][]string{source.Args, source.Profiles, source.Platforms} {
+		d.Write([]byte(strings.Join(part, "\x00")))
+		d.Write([]byte{'\x00'})
+	}
+	for _, f := range files {
+		d.Write([]byte(f))
+		d.Write([]byte{'\x00'})
+		if err := hashFile(d, f); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(d.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, file string) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		// a missing file is part of the state of the project, the build will report it
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// cachedImage returns the image built from the cache key, or an empty string if there is none
+func cachedImage(ctx context.Context, c client.Client, key string) (string, error) {
+	image, err := c.ImageGet(ctx, cacheTag(key))
+	if apierror.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return image.Name, nil
+}
+
+// buildOrReuse builds the image source and records the cache key of the build on the image. If reuse is set and an
+// image was already built from the same cache key, that image is used instead of building.
+func buildOrReuse(ctx context.Context, c client.Client, opts *Options, events *eventWriter, reuse bool) (string, map[string]any, error) {
+	key, err := cacheKey(ctx, c, opts.ImageSource)
+	if err != nil {
+		logrus.Debugf("Failed to compute build cache key, building: %v", err)
+		key = ""
+	}
+
+	if reuse && key != "" {
+		image, err := cachedImage(ctx, c, key)
+		if err != nil {
+			logrus.Debugf("Failed to look up cached image, building: %v", err)
+		} else if image != "" {
+			_, deployArgs, err := opts.ImageSource.GetAppDefinition(ctx, c)
+			if err != nil {
+				return "", nil, err
+			}
+			logrus.Infof("No changes since image [%s] was built, skipping build", image)
+			events.emit(Event{Type: EventBuildCached, BuildID: image, AppName: opts.Run.Name})
+			return image, deployArgs, nil
+		}
+	}
+
+	image, deployArgs, err := build(ctx, c, opts, events)
+	if err == nil && key != "" {
+		if err := c.ImageTag(ctx, image, cacheTag(key)); err != nil {
+			logrus.Debugf("Failed to record build cache key of image [%s]: %v", image, err)
+		}
+	}
+	return image, deployArgs, err
+}
@@ -0,0 +1,84 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/imagesource"
+	"github.com/acorn-io/acorn/pkg/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestBuildCacheSkipsUnchangedProject(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	acornfile := filepath.Join(dir, "Acornfile")
+	require.NoError(t, os.WriteFile(acornfile, []byte(`containers: web: image: "nginx"`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("hello"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".acornignore"), []byte("*.log\n"), 0600))
+
+	var (
+		ctrl    = gomock.NewController(t)
+		mClient = mocks.NewMockClient(ctrl)
+		opts    = &Options{
+			ImageSource: imagesource.ImageSource{File: acornfile},
+			Run:         client.AppRunOptions{Name: "test-app"},
+		}
+		notFound = apierror.NewNotFound(schema.GroupResource{Resource: "images"}, "")
+	)
+
+	key, err := cacheKey(context.Background(), mClient, opts.ImageSource)
+	require.NoError(t, err)
+	require.NotEmpty(t, key)
+
+	// the first start builds and records the cache key
+	mClient.EXPECT().ImageGet(gomock.Any(), cacheTag(key)).Return(nil, notFound)
+	mClient.EXPECT().AcornImageBuild(gomock.Any(), acornfile, gomock.Any()).Return(&v1.AppImage{ID: "built-image"}, nil)
+	mClient.EXPECT().ImageTag(gomock.Any(), "built-image", cacheTag(key)).Return(nil)
+
+	image, _, err := buildOrReuse(context.Background(), mClient, opts, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, "built-image", image)
+
+	// ignored files don't change the cache key
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dev.log"), []byte("log"), 0600))
+
+	// an unchanged project reuses the image without building
+	mClient.EXPECT().ImageGet(gomock.Any(), cacheTag(key)).Return(&apiv1.Image{
+		ObjectMeta: metav1.ObjectMeta{Name: "built-image"},
+	}, nil)
+
+	buf := &bytes.Buffer{}
+	image, _, err = buildOrReuse(context.Background(), mClient, opts, newEventWriter(buf), true)
+	require.NoError(t, err)
+	assert.Equal(t, "built-image", image)
+
+	events := readEvents(t, buf)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventBuildCached, events[0].Type)
+	assert.Equal(t, "built-image", events[0].BuildID)
+
+	// a changed project gets a new cache key
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("hello again"), 0600))
+	changedKey, err := cacheKey(context.Background(), mClient, opts.ImageSource)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, changedKey)
+}
+
+func TestBuildCacheImageSourceNotBuilt(t *testing.T) {
+	key, err := cacheKey(context.Background(), nil, imagesource.ImageSource{Image: "ghcr.io/acorn-io/library/hello-world"})
+	require.NoError(t, err)
+	assert.Empty(t, key)
+}
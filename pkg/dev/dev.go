@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
@@ -18,6 +19,7 @@ import (
 	"github.com/acorn-io/acorn/pkg/labels"
 	"github.com/acorn-io/acorn/pkg/log"
 	"github.com/acorn-io/acorn/pkg/rulerequest"
+	"github.com/acorn-io/acorn/pkg/run"
 	objwatcher "github.com/acorn-io/baaah/pkg/watcher"
 	"github.com/pterm/pterm"
 	"github.com/sirupsen/logrus"
@@ -33,6 +35,22 @@ type Options struct {
 	Replace           bool
 	Dangerous         bool
 	BidirectionalSync bool
+	// Events, if set, receives a JSON line for each dev loop lifecycle event
+	Events io.Writer
+	// Input, if set, is read for keystrokes that control the dev loop
+	Input io.Reader
+	// NoWatch disables watching files for changes, so the app is built and run once. It is set when the app
+	// definition is read from stdin, as there is no file to watch.
+	NoWatch bool
+	// LogContainer, if set, limits the streamed logs to the containers and sidecars with this name
+	LogContainer string
+}
+
+// logOptions returns the options of the log stream of the app
+func (o *Options) logOptions() *client.LogOptions {
+	return &client.LogOptions{
+		Container: o.LogContainer,
+	}
 }
 
 type watcher struct {
@@ -42,6 +60,9 @@ type watcher struct {
 	watching     []string
 	watchingTS   []time.Time
 	initOnce     sync.Once
+	events       *eventWriter
+	control      control
+	noWatch      bool
 }
 
 func (w *watcher) Trigger() {
@@ -52,6 +73,9 @@ func (w *watcher) Trigger() {
 }
 
 func (w *watcher) readFiles(ctx context.Context) []string {
+	if w.noWatch {
+		return nil
+	}
 	files, err := w.imageAndArgs.WatchFiles(ctx, w.c)
 	if err != nil {
 		logrus.Errorf("failed to resolve files to watch: %v", err)
@@ -67,6 +91,7 @@ func (w *watcher) foundChanges() bool {
 			if w.watchingTS[i] != s.ModTime() {
 				if !w.watchingTS[i].IsZero() {
 					logrus.Infof("%s has changed", f)
+					w.events.emit(Event{Type: EventFileChanged, File: f})
 				}
 				return true
 			}
@@ -102,7 +127,7 @@ func (w *watcher) Wait(ctx context.Context) error {
 	})
 
 	for {
-		if !init && !w.foundChanges() {
+		if !init && (w.control.Paused() || !w.foundChanges()) {
 			select {
 			case <-w.trigger:
 			case <-ctx.Done():
@@ -125,39 +150,64 @@ func buildLoop(ctx context.Context, client client.Client, hash string, opts *Opt
 	}()
 
 	var (
+		events  = newEventWriter(opts.Events)
 		watcher = watcher{
 			trigger:      make(chan struct{}, 1),
 			watchingTS:   make([]time.Time, 1),
 			imageAndArgs: opts.ImageSource,
+			events:       events,
+			noWatch:      opts.NoWatch,
 		}
 		startLock sync.Mutex
 		started   = false
+		// only the first build can reuse a previous image, later iterations are triggered by changes
+		reuse = true
 	)
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	if opts.Input != nil {
+		go readInput(ctx, opts.Input, &watcher, cancel)
+	}
+
 outer:
 	for {
 		if err := watcher.Wait(ctx); err != nil {
 			return err
 		}
 
-		image, deployArgs, err := opts.ImageSource.GetImageAndDeployArgs(ctx, client)
-		if err == pflag.ErrHelp {
-			continue
-		} else if err != nil {
-			_, buildFile, _ := opts.ImageSource.ResolveImageAndFile()
-			if buildFile == "" {
-				return err
+		var (
+			image      string
+			deployArgs map[string]any
+			err        error
+		)
+		if released := watcher.control.released(); released != nil {
+			image, deployArgs = released.image, released.deployArgs
+		} else {
+			image, deployArgs, err = buildOrReuse(ctx, client, opts, events, reuse)
+			reuse = false
+			if err == pflag.ErrHelp {
+				continue
+			} else if err != nil {
+				_, buildFile, _ := opts.ImageSource.ResolveImageAndFile()
+				if buildFile == "" || opts.NoWatch {
+					return err
+				}
+				logrus.Errorf("Failed to build %s: %v", buildFile, err)
+				logrus.Infof("Build failed, touch [%s] to rebuild", buildFile)
+				go func() {
+					time.Sleep(120 * time.Second)
+					watcher.Trigger()
+				}()
+				continue
+			}
+
+			if !watcher.control.apply(image, deployArgs) {
+				logrus.Infof("Built %s, the app is not updated while updates are held, press [%c] to apply it", image, keyApply)
+				events.emit(Event{Type: EventUpdateHeld, BuildID: image, AppName: opts.Run.Name})
+				continue
 			}
-			logrus.Errorf("Failed to build %s: %v", buildFile, err)
-			logrus.Infof("Build failed, touch [%s] to rebuild", buildFile)
-			go func() {
-				time.Sleep(120 * time.Second)
-				watcher.Trigger()
-			}()
-			continue
 		}
 
 		var (
@@ -175,6 +225,7 @@ outer:
 			}
 			break
 		}
+		events.emit(Event{Type: EventAppUpdated, BuildID: image, AppName: app.Name})
 
 		startLock.Lock()
 		if started {
@@ -185,7 +236,7 @@ outer:
 		opts.Run.Name = app.Name
 		eg, ctx := errgroup.WithContext(ctx)
 		eg.Go(func() error {
-			return LogLoop(ctx, client, app, nil)
+			return LogLoop(ctx, client, app, opts.logOptions())
 		})
 		eg.Go(func() error {
 			return AppStatusLoop(ctx, client, app)
@@ -212,9 +263,20 @@ outer:
 	}
 }
 
+func build(ctx context.Context, client client.Client, opts *Options, events *eventWriter) (string, map[string]any, error) {
+	events.emit(Event{Type: EventBuildStart, AppName: opts.Run.Name})
+	image, deployArgs, err := opts.ImageSource.GetImageAndDeployArgs(ctx, client)
+	if err != nil && err != pflag.ErrHelp {
+		events.emit(Event{Type: EventBuildFailed, AppName: opts.Run.Name, Error: err.Error()})
+	} else if err == nil {
+		events.emit(Event{Type: EventBuildDone, BuildID: image, AppName: opts.Run.Name})
+	}
+	return image, deployArgs, err
+}
+
 func updateApp(ctx context.Context, c client.Client, app *apiv1.App, image string, opts *Options) (err error) {
 	defer func() {
-		if err == nil && app.Spec.Stop != nil && *app.Spec.Stop {
+		if err == nil && app.Spec.GetStopped() {
 			err = c.AppStart(ctx, app.Name)
 		}
 	}()
@@ -320,7 +382,7 @@ func appDeleteStop(ctx context.Context, c client.Client, app *apiv1.App, cancel
 			cancel()
 			return true, nil
 		}
-		if app.Spec.Stop != nil && *app.Spec.Stop {
+		if app.Spec.GetStopped() {
 			pterm.Println(pterm.FgCyan.Sprintf("starting app %s", app.Name))
 			_ = c.AppStart(ctx, app.Name)
 		}
@@ -375,19 +437,7 @@ func AppStatusLoop(ctx context.Context, c client.Client, app *apiv1.App) error {
 }
 
 func LogLoop(ctx context.Context, c client.Client, app *apiv1.App, opts *client.LogOptions) error {
-	for {
-		if opts == nil {
-			opts = &client.LogOptions{}
-		}
-		opts.Follow = true
-		_ = log.Output(ctx, c, app.Name, opts)
-
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(2 * time.Second):
-		}
-	}
+	return log.Follow(ctx, c, app.Name, opts, log.DefaultFollowBackoff)
 }
 
 func setAppNameAndGetHash(ctx context.Context, client client.Client, opts *Options) (string, *Options, error) {
@@ -410,6 +460,11 @@ func setAppNameAndGetHash(ctx context.Context, client client.Client, opts *Optio
 		}
 		opts.Run.Name = existingName
 	}
+	if opts.Run.Name == "" {
+		// The name of a new app is picked here instead of when it is created, so that the events of the first build
+		// already carry it
+		opts.Run.Name = run.NameGenerator.Generate()
+	}
 
 	return hash, opts, nil
 }
@@ -0,0 +1,40 @@
+package dev
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/acorn-io/acorn/pkg/imagesource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoWatchIgnoresChanges(t *testing.T) {
+	acornfile := filepath.Join(t.TempDir(), "Acornfile")
+	require.NoError(t, os.WriteFile(acornfile, []byte(`containers: web: image: "nginx"`), 0600))
+
+	w := &watcher{
+		trigger:      make(chan struct{}, 1),
+		watchingTS:   make([]time.Time, 1),
+		imageAndArgs: imagesource.ImageSource{File: acornfile},
+		noWatch:      true,
+	}
+
+	// the initial build still happens
+	require.NoError(t, w.Wait(context.Background()))
+	assert.Empty(t, w.watching)
+
+	require.NoError(t, os.Chtimes(acornfile, time.Now(), time.Now().Add(time.Minute)))
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, w.Wait(ctx), context.DeadlineExceeded)
+
+	// a restart of the dev loop still rebuilds
+	w.Trigger()
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, w.Wait(ctx))
+}
@@ -0,0 +1,65 @@
+package dev
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type EventType string
+
+const (
+	EventFileChanged EventType = "file-changed"
+	EventBuildStart  EventType = "build-start"
+	EventBuildDone   EventType = "build-done"
+	EventBuildFailed EventType = "build-failed"
+	EventBuildCached EventType = "build-cached"
+	EventAppUpdated  EventType = "app-updated"
+	EventUpdateHeld  EventType = "update-held"
+)
+
+// Event is a single dev loop lifecycle event, written as one JSON line when structured output is enabled.
+type Event struct {
+	Type    EventType `json:"type"`
+	Time    time.Time `json:"time"`
+	File    string    `json:"file,omitempty"`
+	BuildID string    `json:"buildID,omitempty"`
+	AppName string    `json:"appName,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+type eventWriter struct {
+	out  io.Writer
+	lock sync.Mutex
+}
+
+func newEventWriter(out io.Writer) *eventWriter {
+	if out == nil {
+		return nil
+	}
+	return &eventWriter{out: out}
+}
+
+func (e *eventWriter) emit(event Event) {
+	if e == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		logrus.Errorf("failed to marshal dev event: %v", err)
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if _, err := e.out.Write(append(data, '\n')); err != nil {
+		logrus.Errorf("failed to write dev event: %v", err)
+	}
+}
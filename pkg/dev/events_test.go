@@ -0,0 +1,92 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/imagesource"
+	"github.com/acorn-io/acorn/pkg/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readEvents(t *testing.T, buf *bytes.Buffer) []Event {
+	t.Helper()
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var event Event
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestBuildEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mClient := mocks.NewMockClient(ctrl)
+	mClient.EXPECT().ImageDetails(gomock.Any(), "test-image", gomock.Any()).Return(&client.ImageDetails{
+		AppImage: v1.AppImage{
+			Acornfile: `containers: web: image: "nginx"`,
+		},
+	}, nil).AnyTimes()
+
+	buf := &bytes.Buffer{}
+	opts := &Options{
+		ImageSource: imagesource.ImageSource{Image: "test-image"},
+		Run:         client.AppRunOptions{Name: "test-app"},
+	}
+
+	image, _, err := build(context.Background(), mClient, opts, newEventWriter(buf))
+	require.NoError(t, err)
+	assert.Equal(t, "test-image", image)
+
+	events := readEvents(t, buf)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventBuildStart, events[0].Type)
+	assert.Equal(t, "test-app", events[0].AppName)
+	assert.Equal(t, EventBuildDone, events[1].Type)
+	assert.Equal(t, "test-image", events[1].BuildID)
+	assert.Equal(t, "test-app", events[1].AppName)
+}
+
+func TestBuildEventsFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mClient := mocks.NewMockClient(ctrl)
+	mClient.EXPECT().ImageDetails(gomock.Any(), "test-image", gomock.Any()).
+		Return(nil, errors.New("image not found")).AnyTimes()
+
+	buf := &bytes.Buffer{}
+	opts := &Options{
+		ImageSource: imagesource.ImageSource{Image: "test-image"},
+	}
+
+	_, _, err := build(context.Background(), mClient, opts, newEventWriter(buf))
+	require.Error(t, err)
+
+	events := readEvents(t, buf)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventBuildStart, events[0].Type)
+	assert.Equal(t, EventBuildFailed, events[1].Type)
+	assert.Equal(t, "image not found", events[1].Error)
+}
+
+func TestBuildNoEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mClient := mocks.NewMockClient(ctrl)
+	mClient.EXPECT().ImageDetails(gomock.Any(), "test-image", gomock.Any()).
+		Return(nil, errors.New("image not found")).AnyTimes()
+
+	opts := &Options{
+		ImageSource: imagesource.ImageSource{Image: "test-image"},
+	}
+
+	_, _, err := build(context.Background(), mClient, opts, newEventWriter(nil))
+	require.Error(t, err)
+}
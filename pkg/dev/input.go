@@ -0,0 +1,137 @@
+package dev
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	keyRebuild = 'r'
+	keyPause   = 'p'
+	keyResume  = 'c'
+	keyHold    = 'h'
+	keyApply   = 'a'
+	keyQuit    = 'q'
+)
+
+// control is the state of the dev loop that is changed interactively
+type control struct {
+	paused atomic.Bool
+
+	updateLock sync.Mutex
+	held       bool
+	pending    *update
+}
+
+// update is a built image to run the app with
+type update struct {
+	image      string
+	deployArgs map[string]any
+}
+
+func (c *control) Paused() bool {
+	return c.paused.Load()
+}
+
+// hold stops updating the app with the images that are built, and returns false if updates were already held
+func (c *control) hold() bool {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+	if c.held {
+		return false
+	}
+	c.held = true
+	return true
+}
+
+// release updates the app again, and returns false if updates were not held
+func (c *control) release() bool {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+	if !c.held {
+		return false
+	}
+	c.held = false
+	return true
+}
+
+// apply returns true if the app can be updated with a built image. While updates are held the image is kept instead,
+// replacing the one built before it, and returns false.
+func (c *control) apply(image string, deployArgs map[string]any) bool {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+	if c.held {
+		c.pending = &update{
+			image:      image,
+			deployArgs: deployArgs,
+		}
+		return false
+	}
+	c.pending = nil
+	return true
+}
+
+// released returns the latest image built while updates were held, once they are released
+func (c *control) released() *update {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+	if c.held {
+		return nil
+	}
+	pending := c.pending
+	c.pending = nil
+	return pending
+}
+
+// readInput reads keystrokes from the input to control the dev loop until the input is closed or the context is done.
+// Keys that are not recognized, including the newlines of line buffered input, are ignored.
+func readInput(ctx context.Context, in io.Reader, w *watcher, quit func()) {
+	logrus.Infof("Press [%c] to rebuild, [%c] to pause rebuilding on changes, [%c] to continue, "+
+		"[%c] to hold updates of the app, [%c] to apply them, [%c] to quit",
+		keyRebuild, keyPause, keyResume, keyHold, keyApply, keyQuit)
+
+	reader := bufio.NewReader(in)
+	for ctx.Err() == nil {
+		b, err := reader.ReadByte()
+		if err != nil {
+			if err != io.EOF {
+				logrus.Errorf("failed to read input: %v", err)
+			}
+			return
+		}
+
+		switch b {
+		case keyRebuild:
+			logrus.Infof("Rebuilding")
+			w.Trigger()
+		case keyPause:
+			if !w.control.paused.Swap(true) {
+				logrus.Infof("Paused rebuilding on changes, press [%c] to continue", keyResume)
+			}
+		case keyResume:
+			if w.control.paused.Swap(false) {
+				// changes made while paused are found on the next check of the watcher
+				logrus.Infof("Continued rebuilding on changes")
+			}
+		case keyHold:
+			if w.control.hold() {
+				logrus.Infof("Holding updates of the app, images are still built, press [%c] to apply the latest", keyApply)
+			}
+		case keyApply:
+			if w.control.release() {
+				logrus.Infof("Applying updates of the app")
+				// wake the dev loop to apply the image built while updates were held
+				w.Trigger()
+			}
+		case keyQuit:
+			logrus.Infof("Quitting")
+			quit()
+			return
+		}
+	}
+}
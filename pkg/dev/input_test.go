@@ -0,0 +1,91 @@
+package dev
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acorn-io/acorn/pkg/imagesource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadInput(t *testing.T) {
+	var (
+		w = &watcher{
+			trigger: make(chan struct{}, 1),
+		}
+		quit bool
+	)
+
+	readInput(context.Background(), strings.NewReader("p\n"), w, func() { quit = true })
+	assert.True(t, w.control.Paused())
+
+	readInput(context.Background(), strings.NewReader("x\nc\n"), w, func() { quit = true })
+	assert.False(t, w.control.Paused())
+	assert.Len(t, w.trigger, 0)
+
+	readInput(context.Background(), strings.NewReader("r\n"), w, func() { quit = true })
+	assert.Len(t, w.trigger, 1)
+	assert.False(t, quit)
+
+	// input after quit is not read
+	readInput(context.Background(), strings.NewReader("qp"), w, func() { quit = true })
+	assert.True(t, quit)
+	assert.False(t, w.control.Paused())
+}
+
+func TestHeldUpdatesApplyLatest(t *testing.T) {
+	w := &watcher{
+		trigger: make(chan struct{}, 1),
+	}
+	assert.True(t, w.control.apply("image-1", nil))
+
+	// images built while updates are held are not applied
+	readInput(context.Background(), strings.NewReader("h"), w, func() {})
+	assert.False(t, w.control.apply("image-2", nil))
+	assert.False(t, w.control.apply("image-3", map[string]any{"replicas": 2}))
+	assert.Nil(t, w.control.released())
+	assert.Len(t, w.trigger, 0)
+
+	// the latest image is applied once updates are released
+	readInput(context.Background(), strings.NewReader("a"), w, func() {})
+	assert.Len(t, w.trigger, 1)
+	assert.Equal(t, &update{
+		image:      "image-3",
+		deployArgs: map[string]any{"replicas": 2},
+	}, w.control.released())
+	assert.Nil(t, w.control.released())
+	assert.True(t, w.control.apply("image-4", nil))
+}
+
+func TestPausedSuppressesRebuilds(t *testing.T) {
+	acornfile := filepath.Join(t.TempDir(), "Acornfile")
+	require.NoError(t, os.WriteFile(acornfile, []byte(`containers: web: image: "nginx"`), 0600))
+
+	w := &watcher{
+		trigger:      make(chan struct{}, 1),
+		watchingTS:   make([]time.Time, 1),
+		imageAndArgs: imagesource.ImageSource{File: acornfile},
+	}
+
+	// the initial build is not suppressed
+	require.NoError(t, w.Wait(context.Background()))
+	w.updateTimestamps(context.Background())
+
+	readInput(context.Background(), strings.NewReader("p"), w, func() {})
+	require.NoError(t, os.Chtimes(acornfile, time.Now(), time.Now().Add(time.Minute)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, w.Wait(ctx), context.DeadlineExceeded)
+
+	// the change made while paused triggers a build once continued
+	readInput(context.Background(), strings.NewReader("c"), w, func() {})
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, w.Wait(ctx))
+}
@@ -12,13 +12,13 @@ import (
 	"time"
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/buildclient"
 	"github.com/acorn-io/acorn/pkg/client"
 	"github.com/acorn-io/acorn/pkg/controller/appdefinition"
 	objwatcher "github.com/acorn-io/baaah/pkg/watcher"
 	"github.com/loft-sh/devspace/pkg/devspace/config/versions/latest"
 	"github.com/loft-sh/devspace/pkg/devspace/sync"
 	logpkg "github.com/loft-sh/devspace/pkg/util/log"
-	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -99,20 +99,9 @@ func invokeStartSyncForPath(ctx context.Context, client client.Client, con *apiv
 	if err != nil {
 		return nil, nil, err
 	}
-	var exclude []string
-	f, err := os.Open(filepath.Join(cwd, ".dockerignore"))
-	if err == nil {
-		lines, err := dockerignore.ReadAll(f)
-		_ = f.Close()
-		if err == nil {
-			exclude = lines
-		} else {
-			logrus.Warnf("failed to read %s for syncing: %v", filepath.Join(cwd, ".dockerignore"), err)
-			exclude = nil
-		}
-	} else if !os.IsNotExist(err) {
-		logrus.Warnf("failed to open %s for syncing: %v", filepath.Join(cwd, ".dockerignore"), err)
-		exclude = nil
+	exclude, err := buildclient.ReadIgnorePatterns(cwd)
+	if err != nil {
+		logrus.Warnf("failed to read ignore files of %s for syncing: %v", cwd, err)
 	}
 	s, err := sync.NewSync(ctx, source, sync.Options{
 		DownstreamDisabled: !bidirectional,
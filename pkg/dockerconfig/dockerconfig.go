@@ -3,6 +3,7 @@ package dockerconfig
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 )
@@ -40,3 +41,32 @@ func ToData(server, username, password string) (map[string][]byte, error) {
 		corev1.DockerConfigJsonKey: data,
 	}, err
 }
+
+// Credential is the username and password for a registry
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Merge adds the credentials for each server to the auths of the docker config, replacing the auths the config already
+// has for those servers. The other auths and fields of the config are kept. An empty config is treated as having no
+// auths.
+func Merge(config []byte, creds map[string]Credential) ([]byte, error) {
+	result := map[string]any{}
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &result); err != nil {
+			return nil, fmt.Errorf("invalid docker config: %w", err)
+		}
+	}
+
+	auths, ok := result["auths"].(map[string]any)
+	if !ok {
+		auths = map[string]any{}
+	}
+	for server, cred := range creds {
+		auths[server] = toEntry(cred.Username, cred.Password)
+	}
+	result["auths"] = auths
+
+	return json.Marshal(result)
+}
@@ -0,0 +1,38 @@
+package nacl
+
+import (
+	crypto_rand "crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// GenerateKeyPair returns a new public and private key that are not stored in the cluster, so data encrypted with
+// the public key can only be decrypted by whoever holds the private key.
+func GenerateKeyPair() (publicKey, privateKey string, err error) {
+	public, private, err := box.GenerateKey(crypto_rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return KeyBytesToB64String(public), KeyBytesToB64String(private), nil
+}
+
+// DecryptWithPrivateKey decrypts data encrypted for the public key of the given private key, as generated by
+// GenerateKeyPair.
+func DecryptWithPrivateKey(encData []byte, privateKey string) ([]byte, error) {
+	private, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil || len(private) != curve25519.ScalarSize {
+		return nil, fmt.Errorf("invalid private key, must be %d base64 encoded bytes", curve25519.ScalarSize)
+	}
+
+	key := &NaclKey{
+		PublicKey:  &[32]byte{},
+		privateKey: &[32]byte{},
+	}
+	copy(key.privateKey[:], private)
+	curve25519.ScalarBaseMult(key.PublicKey, key.privateKey)
+	return key.Decrypt(encData)
+}
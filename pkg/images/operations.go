@@ -8,12 +8,14 @@ import (
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/appdefinition"
+	"github.com/acorn-io/acorn/pkg/config"
 	"github.com/acorn-io/acorn/pkg/imagesystem"
 	"github.com/acorn-io/acorn/pkg/pullsecret"
 	"github.com/acorn-io/acorn/pkg/tags"
 	"github.com/google/go-containerregistry/pkg/authn"
 	imagename "github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sirupsen/logrus"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -32,7 +34,11 @@ func ListTags(ctx context.Context, c client.Reader, namespace, image string, opt
 		return nil, nil, err
 	}
 
-	tags, err := remote.List(tag.Context(), opts...)
+	var tags []string
+	err = withPullThroughCache(ctx, c, image, tag, func(ref imagename.Reference) (err error) {
+		tags, err = remote.List(ref.Context(), opts...)
+		return err
+	})
 	return tag, tags, err
 }
 
@@ -47,12 +53,16 @@ func ImageDigest(ctx context.Context, c client.Reader, namespace, image string,
 		return "", err
 	}
 
-	descriptor, err := remote.Head(tag, opts...)
-	if err != nil {
-		return "", err
-	}
-
-	return descriptor.Digest.String(), nil
+	var digest string
+	err = withPullThroughCache(ctx, c, image, tag, func(ref imagename.Reference) error {
+		descriptor, err := remote.Head(ref, opts...)
+		if err != nil {
+			return err
+		}
+		digest = descriptor.Digest.String()
+		return nil
+	})
+	return digest, err
 }
 
 func PullAppImage(ctx context.Context, c client.Reader, namespace, image, nestedDigest string, opts ...remote.Option) (*v1.AppImage, error) {
@@ -73,7 +83,11 @@ func PullAppImage(ctx context.Context, c client.Reader, namespace, image, nested
 		return nil, err
 	}
 
-	appImage, err := pullIndex(tag, opts)
+	var appImage *v1.AppImage
+	err = withPullThroughCache(ctx, c, image, tag, func(ref imagename.Reference) (err error) {
+		appImage, err = pullIndex(ref, opts)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -177,6 +191,51 @@ func GetImageReference(ctx context.Context, c client.Reader, namespace, image st
 	return imagename.ParseReference(image)
 }
 
+// PullThroughCacheReference returns the reference of ref when pulled through the pull-through cache registry. The
+// original registry becomes the first path segment of the repository, so docker.io/library/nginx:latest is pulled as
+// <cache>/docker.io/library/nginx:latest. References to the cache itself are returned unchanged.
+func PullThroughCacheReference(cache string, ref imagename.Reference) (imagename.Reference, error) {
+	if cache == "" || ref.Context().RegistryStr() == cache {
+		return ref, nil
+	}
+
+	repo := cache + "/" + ref.Context().RegistryStr() + "/" + ref.Context().RepositoryStr()
+	switch r := ref.(type) {
+	case imagename.Digest:
+		return imagename.NewDigest(repo + "@" + r.DigestStr())
+	case imagename.Tag:
+		return imagename.NewTag(repo + ":" + r.TagStr())
+	}
+	return nil, fmt.Errorf("unsupported reference %s", ref)
+}
+
+// withPullThroughCache calls pull with the reference of the image through the configured pull-through cache. If there
+// is no cache, the image is internal, or the pull through the cache fails, pull is called with the original reference.
+func withPullThroughCache(ctx context.Context, c client.Reader, image string, ref imagename.Reference, pull func(imagename.Reference) error) error {
+	if tags.SHAPattern.MatchString(image) {
+		return pull(ref)
+	}
+
+	cfg, err := config.Get(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	cacheRef, err := PullThroughCacheReference(*cfg.PullThroughCache, ref)
+	if err != nil {
+		return err
+	}
+	if cacheRef == ref {
+		return pull(ref)
+	}
+
+	if err := pull(cacheRef); err != nil {
+		logrus.Debugf("Failed to pull %s through the pull-through cache, falling back to %s: %v", cacheRef, ref, err)
+		return pull(ref)
+	}
+	return nil
+}
+
 func GetAuthenticationRemoteKeychainWithLocalAuth(ctx context.Context, registry authn.Resource, localAuth *apiv1.RegistryAuth, client client.Reader, namespace string) (authn.Keychain, error) {
 	authn, err := pullsecret.Keychain(ctx, client, namespace)
 	if err != nil {
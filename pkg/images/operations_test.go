@@ -0,0 +1,58 @@
+package images
+
+import (
+	"testing"
+
+	imagename "github.com/google/go-containerregistry/pkg/name"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullThroughCacheReference(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache string
+		image string
+		want  string
+	}{
+		{
+			name:  "no cache",
+			image: "ghcr.io/acorn-io/library/hello-world:latest",
+			want:  "ghcr.io/acorn-io/library/hello-world:latest",
+		},
+		{
+			name:  "tag",
+			cache: "cache.example.com",
+			image: "ghcr.io/acorn-io/library/hello-world:v1",
+			want:  "cache.example.com/ghcr.io/acorn-io/library/hello-world:v1",
+		},
+		{
+			name:  "digest",
+			cache: "cache.example.com:5000",
+			image: "ghcr.io/acorn-io/library/hello-world@sha256:a0cd3f8d7b1d5e4a3fa3e4e1cc8b8a8e2d2ea6f8a2c9b6c2b2d1ad6b7e1c0f9d",
+			want:  "cache.example.com:5000/ghcr.io/acorn-io/library/hello-world@sha256:a0cd3f8d7b1d5e4a3fa3e4e1cc8b8a8e2d2ea6f8a2c9b6c2b2d1ad6b7e1c0f9d",
+		},
+		{
+			name:  "docker hub short name",
+			cache: "cache.example.com",
+			image: "nginx",
+			want:  "cache.example.com/index.docker.io/library/nginx:latest",
+		},
+		{
+			name:  "already through the cache",
+			cache: "cache.example.com",
+			image: "cache.example.com/ghcr.io/acorn-io/library/hello-world:v1",
+			want:  "cache.example.com/ghcr.io/acorn-io/library/hello-world:v1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := imagename.ParseReference(tt.image)
+			require.NoError(t, err)
+
+			got, err := PullThroughCacheReference(tt.cache, ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
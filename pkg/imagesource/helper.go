@@ -9,6 +9,7 @@ import (
 
 	"github.com/acorn-io/acorn/pkg/appdefinition"
 	"github.com/acorn-io/acorn/pkg/build"
+	"github.com/acorn-io/acorn/pkg/buildclient"
 	"github.com/acorn-io/acorn/pkg/client"
 	"github.com/acorn-io/acorn/pkg/config"
 	"github.com/acorn-io/acorn/pkg/credentials"
@@ -21,6 +22,8 @@ type ImageSource struct {
 	Args      []string
 	Profiles  []string
 	Platforms []string
+	// ContextDir overrides the build context dir, which defaults to the directory of the image
+	ContextDir string
 }
 
 func NewImageSource(file string, args, profiles, platforms []string) (result ImageSource) {
@@ -115,14 +118,36 @@ func (i ImageSource) WatchFiles(ctx context.Context, c client.Client) ([]string,
 		return []string{file}, err
 	}
 
+	cwd = i.contextDir(cwd)
 	files, err := app.WatchFiles(cwd)
 	if err != nil {
 		return []string{file}, err
 	}
 
+	files, err = buildclient.FilterIgnored(cwd, files)
+	if err != nil {
+		return []string{file}, err
+	}
+
 	return append([]string{file}, files...), nil
 }
 
+// ResolveContextDir returns the build context dir of the image source
+func (i ImageSource) ResolveContextDir() (string, error) {
+	image, _, err := i.ResolveImageAndFile()
+	if err != nil {
+		return "", err
+	}
+	return i.contextDir(image), nil
+}
+
+func (i ImageSource) contextDir(image string) string {
+	if i.ContextDir != "" {
+		return i.ContextDir
+	}
+	return image
+}
+
 func (i ImageSource) ResolveImageAndFile() (string, string, error) {
 	if !i.IsImageSet() {
 		i.Image = "."
@@ -181,7 +206,7 @@ func (i ImageSource) GetImageAndDeployArgs(ctx context.Context, c client.Client)
 
 		image, err := c.AcornImageBuild(ctx, i.File, &client.AcornImageBuildOptions{
 			Credentials: creds,
-			Cwd:         i.Image,
+			Cwd:         i.contextDir(i.Image),
 			Args:        params,
 			Profiles:    i.Profiles,
 			Platforms:   platforms,
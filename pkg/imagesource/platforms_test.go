@@ -64,3 +64,19 @@ func TestParams(t *testing.T) {
 
 	assert.Equal(t, "d3", appSpec.Containers["foo"].Image)
 }
+
+func TestWatchFilesIgnored(t *testing.T) {
+	source := NewImageSource("testdata/ignore/acorn/Acornfile", nil, nil, nil)
+	source.ContextDir = "testdata/ignore"
+
+	files, err := source.WatchFiles(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// docs is ignored by .acornignore and tmp by .dockerignore
+	assert.Equal(t, []string{
+		"testdata/ignore/acorn/Acornfile",
+		"testdata/ignore/web/Dockerfile",
+	}, files)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/acorn-io/acorn/pkg/client/term"
+	"github.com/acorn-io/acorn/pkg/config"
 	"github.com/acorn-io/acorn/pkg/k8schannel"
 	"github.com/acorn-io/acorn/pkg/k8sclient"
 	"github.com/acorn-io/acorn/pkg/publish"
@@ -38,6 +39,7 @@ import (
 type CheckResult struct {
 	Message string `json:"message"`
 	Passed  bool   `json:"passed"`
+	Warning bool   `json:"warning,omitempty"`
 	Name    string `json:"name"`
 }
 
@@ -51,6 +53,9 @@ type CheckOptions struct {
 
 	// Namespace to override the namespace in which tests are executed (default: acorn-system)
 	Namespace *string `json:"namespace"`
+
+	// Client to use for checks that only read from the cluster (default: client from the current kubeconfig)
+	Client client.Client `json:"-"`
 }
 
 func (copts *CheckOptions) setDefaults() {
@@ -64,6 +69,13 @@ func (copts *CheckOptions) setDefaults() {
 	}
 }
 
+func (copts *CheckOptions) client() (client.Client, error) {
+	if copts.Client != nil {
+		return copts.Client, nil
+	}
+	return k8sclient.Default()
+}
+
 // PreInstallChecks is a list of all checks that are run before the installation.
 // They are crictial and will make the installation fail.
 func PreInstallChecks(ctx context.Context, opts CheckOptions) []CheckResult {
@@ -507,7 +519,7 @@ func CheckDefaultStorageClass(ctx context.Context, opts CheckOptions) CheckResul
 	}
 
 	silenceKlog()
-	cli, err := k8sclient.Default()
+	cli, err := opts.client()
 	if err != nil {
 		result.Passed = false
 		result.Message = fmt.Sprintf("Error creating client: %v", err)
@@ -550,3 +562,95 @@ func CheckDefaultStorageClass(ctx context.Context, opts CheckOptions) CheckResul
 
 	return result
 }
+
+/*
+ * CheckNetworkPolicies checks if the cluster supports NetworkPolicies, unless they are disabled in the Acorn config.
+ * -> This is a non-critical check that "only" affects isolation of network traffic between projects.
+ */
+func CheckNetworkPolicies(ctx context.Context, opts CheckOptions) CheckResult {
+	result := CheckResult{
+		Name: "NetworkPolicies",
+	}
+
+	silenceKlog()
+	cli, err := opts.client()
+	if err != nil {
+		result.Passed = false
+		result.Message = fmt.Sprintf("Error creating client: %v", err)
+		return result
+	}
+
+	cfg, err := config.Get(ctx, cli)
+	if err != nil {
+		result.Passed = false
+		result.Message = fmt.Sprintf("Error getting acorn config: %v", err)
+		return result
+	}
+
+	if cfg.NetworkPolicies != nil && !*cfg.NetworkPolicies {
+		result.Passed = true
+		result.Warning = true
+		result.Message = "NetworkPolicies are disabled in the acorn config, traffic between projects will not be blocked"
+		return result
+	}
+
+	var policies networkingv1.NetworkPolicyList
+	if err := cli.List(ctx, &policies, client.InNamespace(*opts.Namespace), client.Limit(1)); err != nil {
+		result.Passed = false
+		result.Message = fmt.Sprintf("Error listing network policies: %v", err)
+		return result
+	}
+
+	result.Passed = true
+	result.Message = "NetworkPolicy API is available"
+	return result
+}
+
+/*
+ * CheckLoadBalancer checks if services of type LoadBalancer are assigned an external address.
+ * -> This is a non-critical check that "only" affects published TCP and UDP ports.
+ */
+func CheckLoadBalancer(ctx context.Context, opts CheckOptions) CheckResult {
+	result := CheckResult{
+		Name: "LoadBalancer",
+	}
+
+	silenceKlog()
+	cli, err := opts.client()
+	if err != nil {
+		result.Passed = false
+		result.Message = fmt.Sprintf("Error creating client: %v", err)
+		return result
+	}
+
+	var services corev1.ServiceList
+	if err := cli.List(ctx, &services); err != nil {
+		result.Passed = false
+		result.Message = fmt.Sprintf("Error listing services: %v", err)
+		return result
+	}
+
+	var loadBalancers int
+	for _, svc := range services.Items {
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		loadBalancers++
+		if len(svc.Status.LoadBalancer.Ingress) > 0 {
+			result.Passed = true
+			result.Message = fmt.Sprintf("Found LoadBalancer service %s/%s with an external address", svc.Namespace, svc.Name)
+			return result
+		}
+	}
+
+	if loadBalancers == 0 {
+		result.Passed = true
+		result.Warning = true
+		result.Message = "No LoadBalancer services found, unable to verify that published TCP and UDP ports will be reachable"
+	} else {
+		result.Passed = false
+		result.Message = fmt.Sprintf("Found %d LoadBalancer services, but none have been assigned an external address", loadBalancers)
+	}
+
+	return result
+}
@@ -0,0 +1,105 @@
+package install
+
+import (
+	"context"
+	"testing"
+
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/util/storage"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func checkOptions(objs ...kclient.Object) CheckOptions {
+	opts := CheckOptions{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+	}
+	opts.setDefaults()
+	return opts
+}
+
+func acornConfig(data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      system.ConfigName,
+			Namespace: system.Namespace,
+		},
+		Data: map[string]string{
+			"config": data,
+		},
+	}
+}
+
+func loadBalancer(name string, ingress ...corev1.LoadBalancerIngress) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: ingress,
+			},
+		},
+	}
+}
+
+func TestCheckDefaultStorageClass(t *testing.T) {
+	result := CheckDefaultStorageClass(context.Background(), checkOptions())
+	assert.False(t, result.Passed)
+	assert.Equal(t, "No storage classes found", result.Message)
+
+	result = CheckDefaultStorageClass(context.Background(), checkOptions(&storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+	}))
+	assert.False(t, result.Passed)
+	assert.Equal(t, "Found 1 storage classes, but none are marked as default", result.Message)
+
+	result = CheckDefaultStorageClass(context.Background(), checkOptions(&storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "local-path",
+			Annotations: map[string]string{
+				storage.IsDefaultStorageClassAnnotation: "true",
+			},
+		},
+	}))
+	assert.True(t, result.Passed)
+	assert.Equal(t, "Found default storage class local-path", result.Message)
+}
+
+func TestCheckNetworkPolicies(t *testing.T) {
+	result := CheckNetworkPolicies(context.Background(), checkOptions())
+	assert.True(t, result.Passed)
+	assert.False(t, result.Warning)
+
+	result = CheckNetworkPolicies(context.Background(), checkOptions(acornConfig(`{"networkPolicies": false}`)))
+	assert.True(t, result.Passed)
+	assert.True(t, result.Warning)
+	assert.Contains(t, result.Message, "disabled")
+}
+
+func TestCheckLoadBalancer(t *testing.T) {
+	result := CheckLoadBalancer(context.Background(), checkOptions())
+	assert.True(t, result.Passed)
+	assert.True(t, result.Warning)
+
+	result = CheckLoadBalancer(context.Background(), checkOptions(loadBalancer("pending")))
+	assert.False(t, result.Passed)
+	assert.Equal(t, "Found 1 LoadBalancer services, but none have been assigned an external address", result.Message)
+
+	result = CheckLoadBalancer(context.Background(), checkOptions(
+		loadBalancer("pending"),
+		loadBalancer("ready", corev1.LoadBalancerIngress{IP: "10.0.0.1"}),
+	))
+	assert.True(t, result.Passed)
+	assert.False(t, result.Warning)
+	assert.Equal(t, "Found LoadBalancer service default/ready with an external address", result.Message)
+}
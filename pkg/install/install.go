@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/mail"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,6 +29,7 @@ import (
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/typed"
 	"github.com/acorn-io/baaah/pkg/watcher"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pterm/pterm"
 	"github.com/rancher/wrangler/pkg/merr"
 	"github.com/rancher/wrangler/pkg/yaml"
@@ -42,6 +44,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog"
 	klogv2 "k8s.io/klog/v2"
 	v1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
@@ -161,6 +164,46 @@ func Install(ctx context.Context, image string, opts *Options) error {
 		return err
 	}
 
+	if err = validateRegistryMirrors(finalConfForValidation.RegistryMirrors); err != nil {
+		return err
+	}
+
+	if err = validateIngressClassControllerNamespaces(finalConfForValidation.IngressClassControllerNamespaces); err != nil {
+		return err
+	}
+
+	if err = validateAllowTrafficFromNamespaceLabel(*finalConfForValidation.AllowTrafficFromNamespaceLabel); err != nil {
+		return err
+	}
+
+	if err = validateSecretCompatLabels(finalConfForValidation.SecretCompatLabels); err != nil {
+		return err
+	}
+
+	if err = validateVolumeSizeDefault(*finalConfForValidation.VolumeSizeDefault); err != nil {
+		return err
+	}
+
+	if err = validatePullThroughCache(*finalConfForValidation.PullThroughCache); err != nil {
+		return err
+	}
+
+	if err = validateBackingSecretNamespace(*finalConfForValidation.BackingSecretNamespace); err != nil {
+		return err
+	}
+
+	if err = validateSecretWebhookURL(*finalConfForValidation.SecretWebhookURL); err != nil {
+		return err
+	}
+
+	if err = validateDisallowedSecretTypes(finalConfForValidation.DisallowedSecretTypes); err != nil {
+		return err
+	}
+
+	if err = validateGenerationJobSettings(*finalConfForValidation.GenerationJobBackoffLimit, *finalConfForValidation.GenerationJobTTLSeconds); err != nil {
+		return err
+	}
+
 	opts = opts.complete()
 	if opts.OutputFormat != "" {
 		return printObject(image, opts)
@@ -274,6 +317,129 @@ func validateServiceLBAnnotations(annotations []string) error {
 	return nil
 }
 
+func validateRegistryMirrors(mirrors []string) error {
+	for _, mirror := range mirrors {
+		region, registry, found := strings.Cut(mirror, "=")
+		if !found || region == "" || registry == "" {
+			return fmt.Errorf("invalid registry mirror %s, must be in the form of region=registry", mirror)
+		}
+	}
+	return nil
+}
+
+func validateIngressClassControllerNamespaces(entries []string) error {
+	for _, entry := range entries {
+		class, namespace, found := strings.Cut(entry, "=")
+		if !found || class == "" || namespace == "" {
+			return fmt.Errorf("invalid ingress class controller namespace %s, must be in the form of class=namespace", entry)
+		}
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid ingress class controller namespace %s: %s", entry, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+func validateAllowTrafficFromNamespaceLabel(label string) error {
+	if label == "" {
+		return nil
+	}
+	key, value, found := strings.Cut(label, "=")
+	if !found {
+		return fmt.Errorf("invalid allow traffic from namespace label %s, must be in the form of key=value", label)
+	}
+	errs := append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...)
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid allow traffic from namespace label %s: %s", label, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func validateSecretCompatLabels(entries []string) error {
+	for _, entry := range entries {
+		key, legacyKey, found := strings.Cut(entry, "=")
+		if !found || key == "" {
+			return fmt.Errorf("invalid secret compat label %s, must be in the form of key=legacyKey", entry)
+		}
+		errs := validation.IsQualifiedName(key)
+		if legacyKey != "" {
+			errs = append(errs, validation.IsQualifiedName(legacyKey)...)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("invalid secret compat label %s: %s", entry, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+func validateDisallowedSecretTypes(entries []string) error {
+	for _, entry := range entries {
+		project, secretType, found := strings.Cut(entry, "=")
+		if !found || project == "" || secretType == "" {
+			return fmt.Errorf("invalid disallowed secret type %s, must be in the form of project=type", entry)
+		}
+	}
+	return nil
+}
+
+func validateVolumeSizeDefault(size string) error {
+	if size == "" {
+		return nil
+	}
+	q, err := resource.ParseQuantity(size)
+	if err != nil {
+		return fmt.Errorf("invalid volume-size-default %s: %w", size, err)
+	}
+	if q.Sign() <= 0 {
+		return fmt.Errorf("invalid volume-size-default %s, must be greater than 0", size)
+	}
+	return nil
+}
+
+func validatePullThroughCache(cache string) error {
+	if cache == "" {
+		return nil
+	}
+	if _, err := name.NewRegistry(cache, name.StrictValidation); err != nil {
+		return fmt.Errorf("invalid pull-through-cache %s, must be a registry host: %w", cache, err)
+	}
+	return nil
+}
+
+func validateBackingSecretNamespace(namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid backing-secret-namespace %s: %s", namespace, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func validateSecretWebhookURL(webhookURL string) error {
+	if webhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid secret-webhook-url %s: %w", webhookURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid secret-webhook-url %s, must be an http or https URL", webhookURL)
+	}
+	return nil
+}
+
+func validateGenerationJobSettings(backoffLimit, ttlSeconds int) error {
+	if backoffLimit < 0 {
+		return fmt.Errorf("invalid generation-job-backoff-limit %d, must not be negative", backoffLimit)
+	}
+	if ttlSeconds < 0 {
+		return fmt.Errorf("invalid generation-job-ttl-seconds %d, must not be negative", ttlSeconds)
+	}
+	return nil
+}
+
 func validateMemoryArgs(defaultMemory int64, maximumMemory int64) error {
 	// if default is set to unrestricted memory (0) and max memory is not default will be set to maximum
 	if defaultMemory == 0 && maximumMemory != 0 {
@@ -23,6 +23,8 @@ import (
 var (
 	ErrJobNotDone  = errors.New("job not complete")
 	ErrJobNoOutput = errors.New("job has no output")
+	// ErrInvalidOutput is returned when the output of a job can't be parsed
+	ErrInvalidOutput = errors.New("invalid job output")
 )
 
 // GetOutputFor obj must be acorn internal v1.Secret, v1.Service, or string
@@ -46,7 +48,7 @@ func GetOutputFor(ctx context.Context, c kclient.Client, appInstance *v1.AppInst
 			for key, value := range v.Data {
 				d, err := base64.StdEncoding.DecodeString(value)
 				if err != nil {
-					return nil, err
+					return nil, fmt.Errorf("decoding key [%s]: %w", key, scrubOutputError(err))
 				}
 				v.Data[key] = string(d)
 			}
@@ -75,9 +77,33 @@ func GetOutputFor(ctx context.Context, c kclient.Client, appInstance *v1.AppInst
 func asAppSpec(data []byte) (*v1.AppSpec, error) {
 	appDef, err := appdefinition.NewAppDefinition(data)
 	if err != nil {
-		return nil, err
+		return nil, scrubOutputError(err)
+	}
+	appSpec, err := appDef.AppSpec()
+	if err != nil {
+		return nil, scrubOutputError(err)
+	}
+	return appSpec, nil
+}
+
+// scrubOutputError replaces an error from parsing the output of a job with one that only describes where the output is
+// invalid. Errors from parsers can quote the input they failed on, and the output may hold secret values that must not
+// end up in conditions or logs.
+func scrubOutputError(err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		base64Err base64.CorruptInputError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("%w: invalid JSON at offset %d", ErrInvalidOutput, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("%w: invalid JSON at offset %d: expected %s", ErrInvalidOutput, typeErr.Offset, typeErr.Type)
+	case errors.As(err, &base64Err):
+		return fmt.Errorf("%w: invalid base64 at offset %d", ErrInvalidOutput, int64(base64Err))
 	}
-	return appDef.AppSpec()
+	return ErrInvalidOutput
 }
 
 func GetOutput(ctx context.Context, c kclient.Client, appInstance *v1.AppInstance, name string) (job *batchv1.Job, data []byte, err error) {
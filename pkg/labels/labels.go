@@ -19,6 +19,7 @@ const (
 	AcornAcornName                      = Prefix + "acorn-name"
 	AcornServiceName                    = Prefix + "service-name"
 	AcornServicePublish                 = Prefix + "service-publish"
+	AcornServicePublic                  = Prefix + "service-public"
 	AcornServiceNamePrefix              = "service-name." + Prefix
 	AcornDepNames                       = Prefix + "dep-names"
 	AcornAppUID                         = Prefix + "app-uid"
@@ -26,6 +27,12 @@ const (
 	AcornVolumeClass                    = Prefix + "volume-class"
 	AcornSecretName                     = Prefix + "secret-name"
 	AcornSecretGenerated                = Prefix + "secret-generated"
+	AcornSecretRegenerate               = Prefix + "regenerate"
+	AcornSecretTTL                      = Prefix + "secret-ttl"
+	AcornSecretLastUsed                 = Prefix + "secret-last-used"
+	AcornSecretTokenJobs                = Prefix + "secret-token-jobs"
+	AcornSecretTokenRefresh             = Prefix + "secret-token-refresh"
+	AcornSecretReplacedInvalid          = Prefix + "secret-replaced-invalid"
 	AcornContainerName                  = Prefix + "container-name"
 	AcornRouterName                     = Prefix + "router-name"
 	AcornJobName                        = Prefix + "job-name"
@@ -37,6 +44,7 @@ const (
 	AcornPortNumberPrefix               = "port-number." + Prefix
 	AcornCredential                     = Prefix + "credential"
 	AcornPullSecret                     = Prefix + "pull-secret"
+	AcornPrePull                        = Prefix + "prepull"
 	AcornSecretRevPrefix                = "secret-rev." + Prefix
 	AcornPublishURL                     = Prefix + "publish-url"
 	AcornTargets                        = Prefix + "targets"
@@ -52,6 +60,7 @@ const (
 	AcornProjectDefaultRegion           = Prefix + "project-default-region"
 	AcornProjectSupportedRegions        = Prefix + "project-supported-regions"
 	AcornCalculatedProjectDefaultRegion = Prefix + "calculated-project-default-region"
+	AcornProjectDefaultSecrets          = Prefix + "project-default-secrets"
 )
 
 func Merge(base, overlay map[string]string) map[string]string {
@@ -0,0 +1,143 @@
+// Package lint checks an app definition for problems that can be found without a cluster.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/ports"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/typed"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// AppSpec returns all the problems found in the secrets, volumes and ports of the app
+func AppSpec(appSpec *v1.AppSpec) (result field.ErrorList) {
+	workloads := Workloads(appSpec)
+	result = append(result, Secrets(appSpec)...)
+	result = append(result, Volumes(appSpec)...)
+	result = append(result, VolumeNames(appSpec, workloads)...)
+	result = append(result, Ports(workloads)...)
+	return result
+}
+
+// Workloads returns the containers, sidecars and jobs of the app by name
+func Workloads(appSpec *v1.AppSpec) map[string]v1.Container {
+	result := make(map[string]v1.Container, len(appSpec.Containers)+len(appSpec.Jobs))
+	for workload, container := range appSpec.Containers {
+		result[workload] = container
+		for sidecarWorkload, sidecarContainer := range container.Sidecars {
+			result[sidecarWorkload] = sidecarContainer
+		}
+	}
+	for workload, container := range appSpec.Jobs {
+		result[workload] = container
+	}
+	return result
+}
+
+// Secrets checks that each secret of the app has a known type and valid params for that type
+func Secrets(appSpec *v1.AppSpec) (result field.ErrorList) {
+	for _, entry := range typed.Sorted(appSpec.Secrets) {
+		if err := secrets.ValidateParams(entry.Value); err != nil {
+			result = append(result, field.Invalid(field.NewPath("secrets").Key(entry.Key), entry.Value.Type, err.Error()))
+		}
+	}
+	return result
+}
+
+// Volumes checks that the size of each volume of the app can be parsed and that its access modes are known. A
+// percentage size is only valid for ephemeral volumes.
+func Volumes(appSpec *v1.AppSpec) (result field.ErrorList) {
+	for _, entry := range typed.Sorted(appSpec.Volumes) {
+		path := field.NewPath("volumes").Key(entry.Key)
+		if size := entry.Value.Size; size != "" {
+			if _, err := v1.ParseQuantity(string(size)); err != nil {
+				result = append(result, field.Invalid(path.Child("size"), size, err.Error()))
+			} else if _, ok := size.Percentage(); ok && entry.Value.Class != v1.VolumeRequestTypeEphemeral {
+				result = append(result, field.Invalid(path.Child("size"), size, "a percentage size is only supported for ephemeral volumes"))
+			}
+		}
+		for i, accessMode := range entry.Value.AccessModes {
+			switch accessMode {
+			case v1.AccessModeReadWriteMany, v1.AccessModeReadWriteOnce, v1.AccessModeReadOnlyMany, v1.AccessModeReadWriteOncePod:
+			default:
+				result = append(result, field.NotSupported(path.Child("accessModes").Index(i), accessMode, []string{
+					string(v1.AccessModeReadWriteMany),
+					string(v1.AccessModeReadWriteOnce),
+					string(v1.AccessModeReadOnlyMany),
+					string(v1.AccessModeReadWriteOncePod),
+				}))
+			}
+		}
+	}
+	return result
+}
+
+// Ports checks that the service ports generated from the ports of each workload have DNS-1123 label names and that
+// ports sharing a name don't disagree on the target port or protocol. Service ports are deduplicated by name, so such
+// ports would otherwise be silently dropped.
+func Ports(workloads map[string]v1.Container) (result field.ErrorList) {
+	for _, entry := range typed.Sorted(workloads) {
+		byName := map[string]v1.PortDef{}
+		for i, port := range entry.Value.Ports {
+			path := field.NewPath("containers").Key(entry.Key).Child("ports").Index(i)
+			port = port.Complete()
+			servicePort := ports.ToServicePort(port)
+			if errs := validation.IsDNS1123Label(servicePort.Name); len(errs) > 0 {
+				result = append(result, field.Invalid(path, port.FormatString(""),
+					fmt.Sprintf("port name %q is not valid: %s", servicePort.Name, strings.Join(errs, ", "))))
+				continue
+			}
+
+			existing, ok := byName[servicePort.Name]
+			if !ok {
+				byName[servicePort.Name] = port
+				continue
+			}
+			if existing.TargetPort != port.TargetPort || (existing.Protocol == v1.ProtocolUDP) != (port.Protocol == v1.ProtocolUDP) {
+				result = append(result, field.Duplicate(path, fmt.Sprintf("port name %q is used by both %s and %s",
+					servicePort.Name, existing.FormatString(""), port.FormatString(""))))
+			}
+		}
+	}
+	return result
+}
+
+// reservedVolumeNamePrefixes are the prefixes of the pod volumes that are not backed by an acorn volume. Secrets are
+// mounted as "secret-<name>" and files as "secrets-<app id>", so a volume with such a name could collide with them.
+var reservedVolumeNamePrefixes = []string{"secret-", "secrets-"}
+
+// VolumeNames checks that neither the volumes of the app nor the volumes mounted by its workloads use a name reserved
+// for the pod volumes of secrets and files.
+func VolumeNames(appSpec *v1.AppSpec, workloads map[string]v1.Container) (result field.ErrorList) {
+	for _, volName := range typed.SortedKeys(appSpec.Volumes) {
+		if err := checkReservedVolumeName(volName); err != nil {
+			result = append(result, field.Invalid(field.NewPath("volumes").Key(volName), volName, err.Error()))
+		}
+	}
+
+	for _, entry := range typed.Sorted(workloads) {
+		for _, dir := range typed.Sorted(entry.Value.Dirs) {
+			if _, ok := appSpec.Volumes[dir.Value.Volume]; ok || dir.Value.Volume == "" {
+				// volumes of the app are already checked above
+				continue
+			}
+			if err := checkReservedVolumeName(dir.Value.Volume); err != nil {
+				result = append(result, field.Invalid(field.NewPath("containers").Key(entry.Key).Child("dirs").Key(dir.Key), dir.Value.Volume, err.Error()))
+			}
+		}
+	}
+	return result
+}
+
+func checkReservedVolumeName(volName string) error {
+	for _, prefix := range reservedVolumeNamePrefixes {
+		if strings.HasPrefix(volName, prefix) {
+			return fmt.Errorf("volume name %q is reserved, volume names must not start with %q", volName, prefix)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+)
+
+func TestPorts(t *testing.T) {
+	tests := []struct {
+		name      string
+		workloads map[string]v1.Container
+		wantErr   string
+	}{
+		{
+			name: "Unique ports",
+			workloads: map[string]v1.Container{
+				"web": {
+					Ports: []v1.PortDef{
+						{Port: 80, TargetPort: 8080, Protocol: v1.ProtocolHTTP},
+						{Port: 443, Protocol: v1.ProtocolTCP},
+					},
+				},
+				"sidecar": {
+					Ports: []v1.PortDef{
+						{Port: 80, TargetPort: 9090},
+					},
+				},
+			},
+		},
+		{
+			name: "Same port repeated with the same target",
+			workloads: map[string]v1.Container{
+				"web": {
+					Ports: []v1.PortDef{
+						{Port: 80, TargetPort: 8080, Protocol: v1.ProtocolHTTP},
+						{Port: 80, TargetPort: 8080, Protocol: v1.ProtocolTCP, Publish: true},
+					},
+				},
+			},
+		},
+		{
+			name: "Duplicate port name with different targets",
+			workloads: map[string]v1.Container{
+				"web": {
+					Ports: []v1.PortDef{
+						{Port: 80, TargetPort: 8080, Protocol: v1.ProtocolHTTP},
+						{Port: 80, TargetPort: 9090, Protocol: v1.ProtocolHTTP},
+					},
+				},
+			},
+			wantErr: `containers[web].ports[1]: Duplicate value: "port name \"80\" is used by both 80:8080/http and 80:9090/http"`,
+		},
+		{
+			name: "Duplicate port name with different protocols",
+			workloads: map[string]v1.Container{
+				"dns": {
+					Ports: []v1.PortDef{
+						{Port: 53, Protocol: v1.ProtocolTCP},
+						{Port: 53, Protocol: v1.ProtocolUDP},
+					},
+				},
+			},
+			wantErr: `containers[dns].ports[1]: Duplicate value: "port name \"53\" is used by both 53/tcp and 53/udp"`,
+		},
+		{
+			name: "Invalid port name",
+			workloads: map[string]v1.Container{
+				"web": {
+					Ports: []v1.PortDef{
+						{Port: -80, Protocol: v1.ProtocolTCP},
+					},
+				},
+			},
+			wantErr: `containers[web].ports[0]: Invalid value: "-80/tcp": port name "-80" is not valid: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Ports(tt.workloads)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("Expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("Expected 1 error, got %v", errs)
+			}
+			if errs[0].Error() != tt.wantErr {
+				t.Fatalf("Expected error %q, got %q", tt.wantErr, errs[0].Error())
+			}
+		})
+	}
+}
+
+func TestVolumeNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		appSpec   v1.AppSpec
+		workloads map[string]v1.Container
+		wantErr   string
+	}{
+		{
+			name: "Unreserved names",
+			appSpec: v1.AppSpec{
+				Volumes: map[string]v1.VolumeRequest{
+					"data":       {},
+					"my-secrets": {},
+				},
+			},
+			workloads: map[string]v1.Container{
+				"web": {
+					Dirs: map[string]v1.VolumeMount{
+						"/data":  {Volume: "data"},
+						"/cache": {Volume: "cache"},
+						"/creds": {Secret: v1.VolumeSecretMount{Name: "creds"}},
+					},
+				},
+			},
+		},
+		{
+			name: "Volume with the secret prefix",
+			appSpec: v1.AppSpec{
+				Volumes: map[string]v1.VolumeRequest{
+					"secret-creds": {},
+				},
+			},
+			wantErr: `volumes[secret-creds]: Invalid value: "secret-creds": volume name "secret-creds" is reserved, volume names must not start with "secret-"`,
+		},
+		{
+			name: "Volume with the files prefix",
+			appSpec: v1.AppSpec{
+				Volumes: map[string]v1.VolumeRequest{
+					"secrets-1234": {},
+				},
+			},
+			wantErr: `volumes[secrets-1234]: Invalid value: "secrets-1234": volume name "secrets-1234" is reserved, volume names must not start with "secrets-"`,
+		},
+		{
+			name: "Mounted volume with a reserved prefix",
+			workloads: map[string]v1.Container{
+				"web": {
+					Dirs: map[string]v1.VolumeMount{
+						"/data": {Volume: "secret-data"},
+					},
+				},
+			},
+			wantErr: `containers[web].dirs[/data]: Invalid value: "secret-data": volume name "secret-data" is reserved, volume names must not start with "secret-"`,
+		},
+		{
+			name: "Mounted volume of the app is reported once",
+			appSpec: v1.AppSpec{
+				Volumes: map[string]v1.VolumeRequest{
+					"secrets-data": {},
+				},
+			},
+			workloads: map[string]v1.Container{
+				"web": {
+					Dirs: map[string]v1.VolumeMount{
+						"/data": {Volume: "secrets-data"},
+					},
+				},
+			},
+			wantErr: `volumes[secrets-data]: Invalid value: "secrets-data": volume name "secrets-data" is reserved, volume names must not start with "secrets-"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := VolumeNames(&tt.appSpec, tt.workloads)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("Expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("Expected 1 error, got %v", errs)
+			}
+			if errs[0].Error() != tt.wantErr {
+				t.Fatalf("Expected error %q, got %q", tt.wantErr, errs[0].Error())
+			}
+		})
+	}
+}
+
+func TestAppSpecReportsAllProblems(t *testing.T) {
+	appSpec := &v1.AppSpec{
+		Containers: map[string]v1.Container{
+			"web": {
+				Ports: []v1.PortDef{
+					{Port: 80, TargetPort: 8080, Protocol: v1.ProtocolHTTP},
+					{Port: 80, TargetPort: 9090, Protocol: v1.ProtocolHTTP},
+				},
+			},
+		},
+		Volumes: map[string]v1.VolumeRequest{
+			"data": {
+				Size:        "50%",
+				AccessModes: v1.AccessModes{"readSometimes"},
+			},
+		},
+		Secrets: map[string]v1.Secret{
+			"creds": {Type: "magic"},
+			"cert": {
+				Type:   "tls",
+				Params: v1.GenericMap{"sans": "example.com"},
+			},
+			"pass": {
+				Type:   "token",
+				Params: v1.GenericMap{"pattern": "["},
+			},
+		},
+	}
+
+	errs := AppSpec(appSpec)
+	want := []string{
+		`secrets[cert]: Invalid value: "tls": invalid sans param [example.com], must be a list`,
+		`secrets[creds]: Invalid value: "magic": invalid secret type [magic]`,
+		`secrets[pass]: Invalid value: "token": invalid pattern param [[]`,
+		`volumes[data].size: Invalid value: "50%": a percentage size is only supported for ephemeral volumes`,
+		`volumes[data].accessModes[0]: Unsupported value: "readSometimes"`,
+		`containers[web].ports[1]: Duplicate value: "port name \"80\" is used by both 80:8080/http and 80:9090/http"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d errors, got %v", len(want), errs)
+	}
+	for i := range want {
+		if !strings.HasPrefix(errs[i].Error(), want[i]) {
+			t.Errorf("Expected error %d to start with %q, got %q", i, want[i], errs[i].Error())
+		}
+	}
+}
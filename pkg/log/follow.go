@@ -0,0 +1,76 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/sirupsen/logrus"
+)
+
+// FollowBackoff controls how Follow reconnects after a log stream ends
+type FollowBackoff struct {
+	// Initial is the delay before the first reconnect
+	Initial time.Duration
+	// Max caps the delay, which doubles after every reconnect that doesn't receive any log lines
+	Max time.Duration
+	// MaxRetries is the number of consecutive reconnects without receiving any log lines before giving up. Zero
+	// means retry until the context is canceled.
+	MaxRetries int
+}
+
+var DefaultFollowBackoff = FollowBackoff{
+	Initial: time.Second,
+	Max:     30 * time.Second,
+}
+
+// streamLogs is a variable so that tests can replace the log stream
+var streamLogs = output
+
+// Follow prints the logs of the app and re-attaches whenever the log stream ends, such as when pods are replaced.
+// The backoff is reset every time a stream delivers log lines.
+func Follow(ctx context.Context, c client.Client, name string, opts *client.LogOptions, backoff FollowBackoff) error {
+	if opts == nil {
+		opts = &client.LogOptions{}
+	}
+	opts.Follow = true
+
+	var (
+		delay   = backoff.Initial
+		retries = 0
+	)
+	for {
+		received, err := streamLogs(ctx, c, name, opts)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			logrus.Debugf("log stream for app %s ended: %v", name, err)
+		}
+
+		if received > 0 {
+			delay = backoff.Initial
+			retries = 0
+		} else {
+			retries++
+		}
+		if backoff.MaxRetries > 0 && retries > backoff.MaxRetries {
+			if err != nil {
+				return fmt.Errorf("giving up on logs for app %s after %d reconnect attempts: %w", name, backoff.MaxRetries, err)
+			}
+			return fmt.Errorf("giving up on logs for app %s after %d reconnect attempts", name, backoff.MaxRetries)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if backoff.Max > 0 && delay > backoff.Max {
+			delay = backoff.Max
+		}
+	}
+}
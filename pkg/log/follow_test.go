@@ -0,0 +1,73 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeStreams(t *testing.T, results ...int) (calls *int) {
+	t.Helper()
+	calls = new(int)
+	old := streamLogs
+	t.Cleanup(func() {
+		streamLogs = old
+	})
+	streamLogs = func(_ context.Context, _ client.Client, _ string, opts *client.LogOptions) (int, error) {
+		assert.True(t, opts.Follow)
+		defer func() { *calls++ }()
+		if *calls < len(results) {
+			return results[*calls], nil
+		}
+		return 0, errors.New("stream closed")
+	}
+	return calls
+}
+
+var testBackoff = FollowBackoff{
+	Initial:    time.Millisecond,
+	Max:        4 * time.Millisecond,
+	MaxRetries: 2,
+}
+
+func TestFollowReconnectsOnStreamEnd(t *testing.T) {
+	// Each stream that delivers lines resets the retry count, so all three streams are followed and it
+	// takes three more empty streams to exceed the two allowed retries.
+	calls := fakeStreams(t, 3, 1, 2)
+
+	err := Follow(context.Background(), nil, "app", nil, testBackoff)
+	assert.EqualError(t, err, "giving up on logs for app app after 2 reconnect attempts: stream closed")
+	assert.Equal(t, 6, *calls)
+}
+
+func TestFollowMaxRetries(t *testing.T) {
+	calls := fakeStreams(t)
+
+	err := Follow(context.Background(), nil, "app", &client.LogOptions{}, testBackoff)
+	assert.Error(t, err)
+	assert.Equal(t, 3, *calls)
+}
+
+func TestFollowContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := new(int)
+	old := streamLogs
+	t.Cleanup(func() {
+		streamLogs = old
+	})
+	streamLogs = func(context.Context, client.Client, string, *client.LogOptions) (int, error) {
+		*calls++
+		if *calls == 3 {
+			cancel()
+		}
+		return 1, nil
+	}
+
+	err := Follow(ctx, nil, "app", nil, FollowBackoff{Initial: time.Millisecond})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, *calls)
+}
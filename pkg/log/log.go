@@ -76,6 +76,8 @@ type Options struct {
 	Tail             *int64
 	Follow           bool
 	ContainerReplica string
+	// Container, if set, limits the logs to the containers and sidecars with this name
+	Container string
 }
 
 func (o *Options) restConfig() (*rest.Config, error) {
@@ -388,6 +390,10 @@ func matchesPod(pod *corev1.Pod, options *Options) bool {
 }
 
 func matchesContainer(pod *corev1.Pod, container corev1.Container, options *Options) bool {
+	if options != nil && options.Container != "" && container.Name != options.Container {
+		return false
+	}
+
 	if options != nil && options.ContainerReplica != "" {
 		parts := strings.SplitN(options.ContainerReplica, ".", 3)
 		if len(parts) == 3 {
@@ -1,12 +1,16 @@
 package log
 
 import (
+	"context"
 	"testing"
 
 	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var (
@@ -138,6 +142,39 @@ func TestMatchesContainer(t *testing.T) {
 			},
 			expectedResult: false,
 		},
+		{
+			name: "container-sidecar-match",
+			args: args{
+				pod:       appWithLinkerdProxy,
+				container: corev1.Container{Name: "sidecar"},
+				options: &Options{
+					Container: "sidecar",
+				},
+			},
+			expectedResult: true,
+		},
+		{
+			name: "container-nginx-no-match",
+			args: args{
+				pod:       appWithLinkerdProxy,
+				container: corev1.Container{Name: "nginx"},
+				options: &Options{
+					Container: "sidecar",
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			name: "container-linkerd-proxy-no-match",
+			args: args{
+				pod:       appWithLinkerdProxy,
+				container: corev1.Container{Name: "linkerd-proxy"},
+				options: &Options{
+					Container: "linkerd-proxy",
+				},
+			},
+			expectedResult: false,
+		},
 		{
 			name: "busybox-match",
 			args: args{
@@ -198,3 +235,24 @@ func TestMatchesContainer(t *testing.T) {
 		})
 	}
 }
+
+func TestPodContainerFilter(t *testing.T) {
+	output := make(chan Message)
+	go func() {
+		defer close(output)
+		err := Pod(context.Background(), appWithLinkerdProxy, output, &Options{
+			Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+			PodClient: k8sfake.NewSimpleClientset().CoreV1(),
+			Container: "sidecar",
+		})
+		assert.NoError(t, err)
+	}()
+
+	var containers []string
+	for msg := range output {
+		assert.NoError(t, msg.Err)
+		assert.Equal(t, "fake logs", msg.Line)
+		containers = append(containers, msg.ContainerName)
+	}
+	assert.Equal(t, []string{"sidecar"}, containers)
+}
@@ -30,9 +30,15 @@ func nextColor() pterm.Color {
 }
 
 func Output(ctx context.Context, c client.Client, name string, opts *client.LogOptions) error {
+	_, err := output(ctx, c, name, opts)
+	return err
+}
+
+// output prints the log stream of the app until it ends and returns the number of log lines that were received
+func output(ctx context.Context, c client.Client, name string, opts *client.LogOptions) (received int, _ error) {
 	msgs, err := c.AppLog(ctx, name, opts)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	containerColors := map[string]pterm.Color{}
@@ -40,10 +46,11 @@ func Output(ctx context.Context, c client.Client, name string, opts *client.LogO
 	for msg := range msgs {
 		result, err := SinceLogCheck(opts.Since, msg)
 		if err != nil {
-			return err
+			return received, err
 		}
 		if result {
 			if msg.Error == "" {
+				received++
 				color, ok := containerColors[msg.ContainerName]
 				if !ok {
 					color = nextColor()
@@ -57,7 +64,7 @@ func Output(ctx context.Context, c client.Client, name string, opts *client.LogO
 		}
 	}
 
-	return nil
+	return received, nil
 }
 
 func SinceLogCheck(since string, msg v1.LogMessage) (bool, error) {
@@ -187,6 +187,20 @@ func (mr *MockClientMockRecorder) AppPullImage(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppPullImage", reflect.TypeOf((*MockClient)(nil).AppPullImage), arg0, arg1)
 }
 
+// AppResume mocks base method.
+func (m *MockClient) AppResume(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppResume", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppResume indicates an expected call of AppResume.
+func (mr *MockClientMockRecorder) AppResume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppResume", reflect.TypeOf((*MockClient)(nil).AppResume), arg0, arg1)
+}
+
 // AppRun mocks base method.
 func (m *MockClient) AppRun(arg0 context.Context, arg1 string, arg2 *client.AppRunOptions) (*v1.App, error) {
 	m.ctrl.T.Helper()
@@ -230,6 +244,20 @@ func (mr *MockClientMockRecorder) AppStop(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppStop", reflect.TypeOf((*MockClient)(nil).AppStop), arg0, arg1)
 }
 
+// AppSuspend mocks base method.
+func (m *MockClient) AppSuspend(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppSuspend", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppSuspend indicates an expected call of AppSuspend.
+func (mr *MockClientMockRecorder) AppSuspend(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppSuspend", reflect.TypeOf((*MockClient)(nil).AppSuspend), arg0, arg1)
+}
+
 // AppUpdate mocks base method.
 func (m *MockClient) AppUpdate(arg0 context.Context, arg1 string, arg2 *client.AppUpdateOptions) (*v1.App, error) {
 	m.ctrl.T.Helper()
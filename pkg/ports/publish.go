@@ -9,6 +9,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+// maxSessionAffinityTimeout is the longest client IP affinity kubernetes accepts on a service, one day
+const maxSessionAffinityTimeout = 86400
+
 var clusterDomainHTTPDef = ListenDef{
 	Protocol: v1.ProtocolHTTP,
 }
@@ -64,6 +67,45 @@ func (b BoundPorts) ServicePorts() (result []corev1.ServicePort, err error) {
 	return
 }
 
+// Public returns true if any of the bound ports is public
+func (b BoundPorts) Public() bool {
+	for _, ports := range b {
+		for _, port := range ports {
+			if port.Public {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SessionAffinity returns the client address affinity of the bound ports, or nil if none of them asks for it. The
+// affinity applies to the whole service, so the longest timeout of the ports wins.
+func (b BoundPorts) SessionAffinity() (*corev1.ClientIPConfig, error) {
+	var result *corev1.ClientIPConfig
+	for _, ports := range b {
+		for _, port := range ports {
+			if !port.SessionAffinity {
+				continue
+			}
+			timeout := port.SessionAffinityTimeout
+			if timeout == 0 {
+				timeout = corev1.DefaultClientIPServiceAffinitySeconds
+			}
+			if timeout < 0 || timeout > maxSessionAffinityTimeout {
+				return nil, fmt.Errorf("session affinity timeout [%d] of port [%s] must be between 1 and %d seconds",
+					port.SessionAffinityTimeout, port.FormatString(""), maxSessionAffinityTimeout)
+			}
+			if result == nil || timeout > *result.TimeoutSeconds {
+				result = &corev1.ClientIPConfig{
+					TimeoutSeconds: &timeout,
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
 func (b BoundPorts) ByHostname() map[string][]v1.PortDef {
 	byHostname := map[string][]v1.PortDef{}
 	for k, v := range b {
@@ -146,25 +146,60 @@ func Ingress(req router.Request, svc *v1.ServiceInstance) (result []kclient.Obje
 		return nil, err
 	}
 
-	ingressClassName := cfg.IngressClassName
-	if ingressClassName == nil {
-		ingressClassName, err = IngressClassNameIfNoDefault(req.Ctx, req.Client)
+	defaultIngressClassName := cfg.IngressClassName
+	if defaultIngressClassName == nil {
+		defaultIngressClassName, err = IngressClassNameIfNoDefault(req.Ctx, req.Client)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// ingress rules and targets by the ingress class of their ports, "" being the default class
 	var (
-		rules   []networkingv1.IngressRule
-		targets = map[string]Target{}
+		rules   = map[string][]networkingv1.IngressRule{}
+		targets = map[string]map[string]Target{}
 	)
+	addRule := func(ingressClassName, hostname string, target Target, rule networkingv1.IngressRule) {
+		if defaultIngressClassName != nil && ingressClassName == *defaultIngressClassName {
+			ingressClassName = ""
+		}
+		if targets[ingressClassName] == nil {
+			targets[ingressClassName] = map[string]Target{}
+		}
+		targets[ingressClassName][hostname] = target
+		rules[ingressClassName] = append(rules[ingressClassName], rule)
+	}
 
 	for _, entry := range typed.Sorted(bindings.ByHostname()) {
 		hostname := entry.Key
 		ports := typed.MapSlice(entry.Value, func(p v1.PortDef) v1.PortDef {
 			return p.Complete()
 		})
-		if hostname == "" {
+		if hasPaths(ports) {
+			// ports with paths share the hostname, each receiving the requests for its path
+			if err := checkPaths(hostname, ports); err != nil {
+				return nil, err
+			}
+			ingressClassName, err := sharedIngressClassName(hostname, ports)
+			if err != nil {
+				return nil, err
+			}
+			hostnames := []string{hostname}
+			if hostname == "" {
+				hostnames = nil
+				for _, domain := range cfg.ClusterDomains {
+					hostname, err := toHTTPEndpointHostname(*cfg.HttpEndpointPattern, domain, svc.Name, svc.Spec.AppName, svc.Spec.AppNamespace)
+					if err != nil {
+						return nil, err
+					}
+					hostnames = append(hostnames, hostname)
+				}
+			}
+			for _, hostname := range hostnames {
+				addRule(ingressClassName, hostname, Target{Port: rootPort(ports).TargetPort, Service: svc.Name},
+					getIngressRule(svc, hostname, ports...))
+			}
+		} else if hostname == "" {
 			for i, port := range ports {
 				targetName := svc.Name
 				if i > 0 {
@@ -176,72 +211,86 @@ func Ingress(req router.Request, svc *v1.ServiceInstance) (result []kclient.Obje
 					if err != nil {
 						return nil, err
 					}
-					targets[hostname] = Target{Port: port.TargetPort, Service: svc.Name}
-					rules = append(rules, getIngressRule(svc, hostname, port.Port))
+					addRule(port.IngressClassName, hostname, Target{Port: port.TargetPort, Service: svc.Name},
+						getIngressRule(svc, hostname, port))
 				}
 			}
 		} else {
 			if len(ports) > 1 {
 				return nil, fmt.Errorf("multiple ports bound to the same hostname [%s]", hostname)
 			}
-			targets[hostname] = Target{Port: ports[0].TargetPort, Service: svc.Name}
-			rules = append(rules, getIngressRule(svc, hostname, ports[0].Port))
+			addRule(ports[0].IngressClassName, hostname, Target{Port: ports[0].TargetPort, Service: svc.Name},
+				getIngressRule(svc, hostname, ports[0]))
 		}
 	}
 
-	if len(rules) == 0 {
-		return
-	}
+	hostnameSeen := map[string]struct{}{}
+	for _, ingressClassName := range typed.SortedKeys(rules) {
+		ingressName, className := svc.Name, defaultIngressClassName
+		if ingressClassName != "" {
+			ingressName, className = name.SafeConcatName(svc.Name, ingressClassName), &[]string{ingressClassName}[0]
+		}
 
-	secrets, ingressTLS, err := setupCertsForRules(req, svc, rules)
-	if err != nil {
-		return nil, err
-	}
+		secrets, ingressTLS, err := setupCertsForRules(req, svc, rules[ingressClassName])
+		if err != nil {
+			return nil, err
+		}
 
-	targetJSON, err := json.Marshal(targets)
-	if err != nil {
-		return nil, err
-	}
+		targetJSON, err := json.Marshal(targets[ingressClassName])
+		if err != nil {
+			return nil, err
+		}
 
-	proto := v1.PublishProtocolHTTP
-	if len(ingressTLS) > 0 {
-		proto = v1.PublishProtocolHTTPS
-	}
+		proto := v1.PublishProtocolHTTP
+		if len(ingressTLS) > 0 {
+			proto = v1.PublishProtocolHTTPS
+		}
 
-	hostnameSeen := map[string]struct{}{}
-	for _, rule := range rules {
-		if _, ok := hostnameSeen[rule.Host]; ok {
-			continue
+		for _, rule := range rules[ingressClassName] {
+			if _, ok := hostnameSeen[rule.Host]; ok {
+				continue
+			}
+			hostnameSeen[rule.Host] = struct{}{}
+			svc.Status.Endpoints = append(svc.Status.Endpoints, v1.Endpoint{
+				Address:         rule.Host,
+				PublishProtocol: proto,
+			})
 		}
-		hostnameSeen[rule.Host] = struct{}{}
-		svc.Status.Endpoints = append(svc.Status.Endpoints, v1.Endpoint{
-			Address:         rule.Host,
-			PublishProtocol: proto,
-		})
-	}
 
-	result = append(result, &networkingv1.Ingress{
-		TypeMeta: metav1.TypeMeta{},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      svc.Name,
-			Namespace: svc.Namespace,
-			Labels:    svc.Spec.Labels,
-			Annotations: labels.Merge(svc.Spec.Annotations, map[string]string{
-				labels.AcornTargets: string(targetJSON),
-			}),
-		},
-		Spec: networkingv1.IngressSpec{
-			IngressClassName: ingressClassName,
-			Rules:            rules,
-			TLS:              ingressTLS,
-		},
-	})
+		result = append(result, &networkingv1.Ingress{
+			TypeMeta: metav1.TypeMeta{},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ingressName,
+				Namespace: svc.Namespace,
+				Labels:    svc.Spec.Labels,
+				Annotations: labels.Merge(svc.Spec.Annotations, map[string]string{
+					labels.AcornTargets: string(targetJSON),
+				}),
+			},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: className,
+				Rules:            rules[ingressClassName],
+				TLS:              ingressTLS,
+			},
+		})
 
-	result = append(result, secrets...)
+		result = append(result, secrets...)
+	}
 
 	return
 }
 
+// sharedIngressClassName returns the ingress class of the ports sharing a hostname, which must agree on it
+func sharedIngressClassName(hostname string, ports []v1.PortDef) (string, error) {
+	for _, port := range ports[1:] {
+		if port.IngressClassName != ports[0].IngressClassName {
+			return "", fmt.Errorf("ports [%s, %s] bound to the same hostname [%s] have different ingress classes [%s, %s]",
+				ports[0].FormatString(""), port.FormatString(""), hostname, ports[0].IngressClassName, port.IngressClassName)
+		}
+	}
+	return ports[0].IngressClassName, nil
+}
+
 func setupCertManager(serviceName string, annotations map[string]string, rules []networkingv1.IngressRule, tls []networkingv1.IngressTLS) []networkingv1.IngressTLS {
 	if annotations["cert-manager.io/cluster-issuer"] == "" && annotations["cert-manager.io/issuer"] == "" {
 		// cert-manager override is not being used
@@ -282,7 +331,7 @@ func IngressClassNameIfNoDefault(ctx context.Context, client kclient.Client) (*s
 	return nil, nil
 }
 
-func getIngressRule(svc *v1.ServiceInstance, host string, port int32) networkingv1.IngressRule {
+func getIngressRule(svc *v1.ServiceInstance, host string, ports ...v1.PortDef) networkingv1.IngressRule {
 	// strip possible port in host
 	host, _, _ = strings.Cut(host, ":")
 
@@ -290,25 +339,76 @@ func getIngressRule(svc *v1.ServiceInstance, host string, port int32) networking
 		return routerRule(host, svc.Spec.Routes)
 	}
 
-	return networkingv1.IngressRule{
+	rule := networkingv1.IngressRule{
 		Host: host,
 		IngressRuleValue: networkingv1.IngressRuleValue{
-			HTTP: &networkingv1.HTTPIngressRuleValue{
-				Paths: []networkingv1.HTTPIngressPath{
-					{
-						Path:     "/",
-						PathType: &[]networkingv1.PathType{networkingv1.PathTypePrefix}[0],
-						Backend: networkingv1.IngressBackend{
-							Service: &networkingv1.IngressServiceBackend{
-								Name: svc.Name,
-								Port: networkingv1.ServiceBackendPort{
-									Number: port,
-								},
-							},
-						},
+			HTTP: &networkingv1.HTTPIngressRuleValue{},
+		},
+	}
+	for _, port := range ports {
+		rule.IngressRuleValue.HTTP.Paths = append(rule.IngressRuleValue.HTTP.Paths, networkingv1.HTTPIngressPath{
+			Path:     portPath(port),
+			PathType: &[]networkingv1.PathType{portPathType(port)}[0],
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: svc.Name,
+					Port: networkingv1.ServiceBackendPort{
+						Number: port.Port,
 					},
 				},
 			},
-		},
+		})
+	}
+	return rule
+}
+
+func portPath(port v1.PortDef) string {
+	if port.Path == "" {
+		return "/"
+	}
+	return port.Path
+}
+
+func portPathType(port v1.PortDef) networkingv1.PathType {
+	if port.PathType == v1.PathTypeExact {
+		return networkingv1.PathTypeExact
+	}
+	return networkingv1.PathTypePrefix
+}
+
+func hasPaths(ports []v1.PortDef) bool {
+	for _, port := range ports {
+		if port.Path != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPaths returns an error if the ports sharing a hostname don't have valid and distinct paths
+func checkPaths(hostname string, ports []v1.PortDef) error {
+	seen := map[string]v1.PortDef{}
+	for _, port := range ports {
+		path := portPath(port)
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("invalid path [%s] of port [%s], must start with /", path, port.FormatString(""))
+		}
+		key := string(portPathType(port)) + ":" + path
+		if existing, ok := seen[key]; ok && existing.Port != port.Port {
+			return fmt.Errorf("multiple ports [%s, %s] bound to the same path [%s] of hostname [%s]",
+				existing.FormatString(""), port.FormatString(""), path, hostname)
+		}
+		seen[key] = port
+	}
+	return nil
+}
+
+// rootPort returns the port receiving the requests for the root path, or the first port if no port does
+func rootPort(ports []v1.PortDef) v1.PortDef {
+	for _, port := range ports {
+		if portPath(port) == "/" {
+			return port
+		}
 	}
+	return ports[0]
 }
@@ -61,7 +61,16 @@ func ServiceLoadBalancer(req router.Request, svc *v1.ServiceInstance) (result []
 		return nil, err
 	}
 
-	result = append(result, &corev1.Service{
+	if bindings.Public() {
+		svc.Spec.Annotations[labels.AcornServicePublic] = "true"
+	}
+
+	clientIP, err := bindings.SessionAffinity()
+	if err != nil {
+		return nil, err
+	}
+
+	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name.SafeConcatName(svc.Name, "publish", svc.ShortID()),
 			Namespace: svc.Namespace,
@@ -75,7 +84,13 @@ func ServiceLoadBalancer(req router.Request, svc *v1.ServiceInstance) (result []
 			Selector: labels.Merge(labels.ManagedByApp(svc.Spec.AppNamespace, svc.Spec.AppName), selectorLabels),
 			Type:     corev1.ServiceTypeLoadBalancer,
 		},
-	})
+	}
+	if clientIP != nil {
+		service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+		service.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: clientIP,
+		}
+	}
 
-	return result, nil
+	return append(result, service), nil
 }
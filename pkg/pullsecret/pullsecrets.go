@@ -74,7 +74,10 @@ func Keychain(ctx context.Context, c client.Reader, namespace string) (authn.Key
 	return kubernetes.NewFromPullSecrets(ctx, keychainSecrets)
 }
 
-func ForImages(secretName, secretNamespace string, keychain authn.Keychain, images ...string) (*corev1.Secret, error) {
+// ForImages builds a docker config secret with the credentials needed to pull the given images. If registryMirror
+// is set, the credentials for the mirror are also included, so that nodes configured to pull through the mirror can
+// authenticate to it. The images themselves are still pulled by their original references.
+func ForImages(secretName, secretNamespace string, keychain authn.Keychain, registryMirror string, images ...string) (*corev1.Secret, error) {
 	dockerConfig := map[string]any{}
 
 	for _, image := range images {
@@ -83,19 +86,19 @@ func ForImages(secretName, secretNamespace string, keychain authn.Keychain, imag
 			return nil, err
 		}
 
-		registry := ref.Context().RegistryStr()
-
-		auth, err := keychain.Resolve(ref.Context())
-		if err != nil {
+		if err := addAuth(dockerConfig, keychain, ref.Context()); err != nil {
 			return nil, err
 		}
 
-		config, err := auth.Authorization()
-		if err != nil {
-			return nil, err
+		if registryMirror != "" {
+			mirrorRepo, err := name.NewRepository(registryMirror + "/" + ref.Context().RepositoryStr())
+			if err != nil {
+				return nil, fmt.Errorf("invalid registry mirror %s: %w", registryMirror, err)
+			}
+			if err := addAuth(dockerConfig, keychain, mirrorRepo); err != nil {
+				return nil, err
+			}
 		}
-
-		dockerConfig[registry] = config
 	}
 
 	data, err := json.Marshal(map[string]any{
@@ -120,3 +123,18 @@ func ForImages(secretName, secretNamespace string, keychain authn.Keychain, imag
 		Type: corev1.SecretTypeDockerConfigJson,
 	}, nil
 }
+
+func addAuth(dockerConfig map[string]any, keychain authn.Keychain, repo name.Repository) error {
+	auth, err := keychain.Resolve(repo)
+	if err != nil {
+		return err
+	}
+
+	config, err := auth.Authorization()
+	if err != nil {
+		return err
+	}
+
+	dockerConfig[repo.RegistryStr()] = config
+	return nil
+}
@@ -0,0 +1,64 @@
+package pullsecret
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type registryKeychain map[string]authn.AuthConfig
+
+func (r registryKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	if cfg, ok := r[resource.RegistryStr()]; ok {
+		return authn.FromConfig(cfg), nil
+	}
+	return authn.Anonymous, nil
+}
+
+func authsFromSecret(t *testing.T, secret *corev1.Secret) map[string]authn.AuthConfig {
+	t.Helper()
+	result := struct {
+		Auths map[string]authn.AuthConfig `json:"auths"`
+	}{}
+	require.NoError(t, json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &result))
+	return result.Auths
+}
+
+func TestForImages(t *testing.T) {
+	keychain := registryKeychain{
+		"ghcr.io":            {Username: "origin", Password: "origin-pass"},
+		"mirror.example.com": {Username: "mirror", Password: "mirror-pass"},
+	}
+
+	secret, err := ForImages("pull", "ns", keychain, "", "ghcr.io/acorn-io/app:latest")
+	require.NoError(t, err)
+
+	auths := authsFromSecret(t, secret)
+	assert.Len(t, auths, 1)
+	assert.Equal(t, "origin", auths["ghcr.io"].Username)
+}
+
+func TestForImagesWithRegistryMirror(t *testing.T) {
+	keychain := registryKeychain{
+		"ghcr.io":            {Username: "origin", Password: "origin-pass"},
+		"mirror.example.com": {Username: "mirror", Password: "mirror-pass"},
+	}
+
+	secret, err := ForImages("pull", "ns", keychain, "mirror.example.com", "ghcr.io/acorn-io/app:latest")
+	require.NoError(t, err)
+
+	auths := authsFromSecret(t, secret)
+	assert.Len(t, auths, 2)
+	assert.Equal(t, "origin", auths["ghcr.io"].Username)
+	assert.Equal(t, "mirror", auths["mirror.example.com"].Username)
+	assert.Equal(t, "mirror-pass", auths["mirror.example.com"].Password)
+}
+
+func TestForImagesInvalidRegistryMirror(t *testing.T) {
+	_, err := ForImages("pull", "ns", registryKeychain{}, "Not A Registry", "ghcr.io/acorn-io/app:latest")
+	assert.Error(t, err)
+}
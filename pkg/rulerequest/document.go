@@ -0,0 +1,51 @@
+package rulerequest
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// RunOrDocument runs the app if it doesn't request permissions. Otherwise the app is not run, and the app granting the
+// permissions it requests is written to out as a yaml document instead, for an admin to review and apply.
+func RunOrDocument(ctx context.Context, c client.Client, image string, opts client.AppRunOptions, out io.Writer) (*apiv1.App, error) {
+	app, err := c.AppRun(ctx, image, &opts)
+	if permErr := (*client.ErrRulesNeeded)(nil); errors.As(err, &permErr) {
+		opts.Permissions = permErr.Permissions
+		data, err := PermissionsDocument(client.ToApp(c.GetNamespace(), image, &opts))
+		if err != nil {
+			return nil, err
+		}
+		_, err = out.Write(data)
+		return nil, err
+	}
+	return app, err
+}
+
+// document is an app without its status
+type document struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              v1.AppInstanceSpec `json:"spec,omitempty"`
+}
+
+// PermissionsDocument renders the app, including the permissions granted to it, as a yaml document that can be
+// applied
+func PermissionsDocument(app *apiv1.App) ([]byte, error) {
+	return yaml.Marshal(document{
+		TypeMeta: app.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        app.Name,
+			Namespace:   app.Namespace,
+			Labels:      app.Labels,
+			Annotations: app.Annotations,
+		},
+		Spec: app.Spec,
+	})
+}
@@ -0,0 +1,70 @@
+package rulerequest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestRunOrDocument(t *testing.T) {
+	var (
+		ctrl    = gomock.NewController(t)
+		mClient = mocks.NewMockClient(ctrl)
+		out     = &bytes.Buffer{}
+		perms   = []v1.Permissions{
+			{
+				ServiceName: "web",
+				Rules: []v1.PolicyRule{{
+					PolicyRule: rbacv1.PolicyRule{
+						Verbs:     []string{"get", "list"},
+						APIGroups: []string{""},
+						Resources: []string{"secrets"},
+					},
+					Scopes: []string{"project"},
+				}},
+			},
+		}
+	)
+
+	mClient.EXPECT().AppRun(gomock.Any(), "image", gomock.Any()).Return(nil, &client.ErrRulesNeeded{Permissions: perms})
+	mClient.EXPECT().GetNamespace().Return("acorn")
+
+	app, err := RunOrDocument(context.Background(), mClient, "image", client.AppRunOptions{Name: "app-name"}, out)
+	require.NoError(t, err)
+	assert.Nil(t, app)
+
+	var doc apiv1.App
+	require.NoError(t, yaml.UnmarshalStrict(out.Bytes(), &doc))
+	assert.Equal(t, "api.acorn.io/v1", doc.APIVersion)
+	assert.Equal(t, "App", doc.Kind)
+	assert.Equal(t, "app-name", doc.Name)
+	assert.Equal(t, "acorn", doc.Namespace)
+	assert.Equal(t, "image", doc.Spec.Image)
+	assert.Equal(t, perms, doc.Spec.Permissions)
+	assert.NotContains(t, out.String(), "status:")
+}
+
+func TestRunOrDocumentNoPermissions(t *testing.T) {
+	var (
+		ctrl    = gomock.NewController(t)
+		mClient = mocks.NewMockClient(ctrl)
+		out     = &bytes.Buffer{}
+	)
+
+	mClient.EXPECT().AppRun(gomock.Any(), "image", gomock.Any()).Return(&apiv1.App{}, nil)
+
+	app, err := RunOrDocument(context.Background(), mClient, "image", client.AppRunOptions{Name: "app-name"}, out)
+	require.NoError(t, err)
+	assert.NotNil(t, app)
+	assert.Empty(t, out.String())
+}
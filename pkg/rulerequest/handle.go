@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
@@ -12,6 +13,9 @@ import (
 	"github.com/acorn-io/acorn/pkg/prompt"
 	"github.com/acorn-io/acorn/pkg/tables"
 	"github.com/pterm/pterm"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 func PromptRun(ctx context.Context, c client.Client, dangerous bool, image string, opts client.AppRunOptions) (*apiv1.App, error) {
@@ -40,6 +44,82 @@ func PromptUpdate(ctx context.Context, c client.Client, dangerous bool, name str
 	return app, err
 }
 
+// PromptRecreate deletes the app and runs the image as a new app of the same name, for changes that can't be made
+// by updating the app. The volume and secret bindings and the granted permissions of the old app are kept unless
+// opts sets them for the same target. Volumes of the old app are retained when it's deleted and bound to the new app
+// again by name, so the data of the app is not lost.
+func PromptRecreate(ctx context.Context, c client.Client, dangerous bool, image string, opts client.AppRunOptions) (*apiv1.App, error) {
+	existing, err := c.AppGet(ctx, opts.Name)
+	if apierrors.IsNotFound(err) {
+		return PromptRun(ctx, c, dangerous, image, opts)
+	} else if err != nil {
+		return nil, err
+	}
+
+	opts.Volumes = keepBindings(existing.Spec.Volumes, opts.Volumes, func(binding v1.VolumeBinding) string {
+		return binding.Target
+	})
+	opts.Secrets = keepBindings(existing.Spec.Secrets, opts.Secrets, func(binding v1.SecretBinding) string {
+		return binding.Target
+	})
+	if len(opts.Permissions) == 0 {
+		opts.Permissions = existing.Spec.Permissions
+	}
+
+	if _, err := c.AppDelete(ctx, opts.Name); err != nil {
+		return nil, err
+	}
+	if err := waitForRemoval(ctx, c, opts.Name); err != nil {
+		return nil, err
+	}
+
+	return PromptRun(ctx, c, dangerous, image, opts)
+}
+
+// keepBindings returns the bindings with the existing bindings of the targets that are not bound added
+func keepBindings[T any](existing, bindings []T, target func(T) string) []T {
+	result := slices.Clone(bindings)
+	for _, binding := range existing {
+		if slices.IndexFunc(bindings, func(b T) bool { return target(b) == target(binding) }) == -1 {
+			result = append(result, binding)
+		}
+	}
+	return result
+}
+
+func waitForRemoval(ctx context.Context, c client.Client, name string) error {
+	for {
+		if _, err := c.AppGet(ctx, name); apierrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			logrus.Debugf("Error getting app for removal check: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// WarnOverGrants warns about the granted permissions that are not used, so they can be tightened. This is advisory,
+// the granted permissions are not changed.
+func WarnOverGrants(granted, used []v1.Permissions) error {
+	requests := OverGrants(granted, used)
+	if len(requests) == 0 {
+		return nil
+	}
+
+	pterm.Warning.Println("The application is granted the following permissions that it does not use. Consider removing them.")
+	pterm.Println()
+
+	writer := table.NewWriter(tables.RuleRequests, false, "")
+	for _, request := range requests {
+		writer.Write(request)
+	}
+	return writer.Close()
+}
+
 func handleDangerous(dangerous bool, perms []v1.Permissions) (bool, error) {
 	if dangerous {
 		return true, nil
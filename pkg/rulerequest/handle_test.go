@@ -0,0 +1,87 @@
+package rulerequest
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/client"
+	"github.com/acorn-io/acorn/pkg/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestPromptRecreateKeepsBindings(t *testing.T) {
+	var (
+		ctrl     = gomock.NewController(t)
+		mClient  = mocks.NewMockClient(ctrl)
+		notFound = apierror.NewNotFound(schema.GroupResource{Resource: "apps"}, "app-name")
+		existing = &apiv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-name"},
+			Spec: v1.AppInstanceSpec{
+				Volumes: []v1.VolumeBinding{
+					{Volume: "old-data", Target: "data"},
+					{Volume: "old-cache", Target: "cache"},
+				},
+				Secrets: []v1.SecretBinding{
+					{Secret: "old-password", Target: "password"},
+				},
+				Permissions: []v1.Permissions{
+					{
+						ServiceName: "web",
+						Rules: []v1.PolicyRule{{
+							PolicyRule: rbacv1.PolicyRule{Verbs: []string{"get"}, Resources: []string{"secrets"}},
+						}},
+					},
+				},
+			},
+		}
+		opts = client.AppRunOptions{
+			Name: "app-name",
+			Volumes: []v1.VolumeBinding{
+				{Volume: "new-cache", Target: "cache"},
+			},
+		}
+	)
+
+	gomock.InOrder(
+		mClient.EXPECT().AppGet(gomock.Any(), "app-name").Return(existing, nil),
+		mClient.EXPECT().AppDelete(gomock.Any(), "app-name").Return(existing, nil),
+		mClient.EXPECT().AppGet(gomock.Any(), "app-name").Return(nil, notFound),
+		mClient.EXPECT().AppRun(gomock.Any(), "image", gomock.Any()).DoAndReturn(
+			func(_ context.Context, image string, opts *client.AppRunOptions) (*apiv1.App, error) {
+				// bindings of the new app replace the old ones, the others are kept
+				assert.Equal(t, []v1.VolumeBinding{
+					{Volume: "new-cache", Target: "cache"},
+					{Volume: "old-data", Target: "data"},
+				}, opts.Volumes)
+				assert.Equal(t, existing.Spec.Secrets, opts.Secrets)
+				assert.Equal(t, existing.Spec.Permissions, opts.Permissions)
+				return &apiv1.App{ObjectMeta: metav1.ObjectMeta{Name: opts.Name}}, nil
+			}),
+	)
+
+	app, err := PromptRecreate(context.Background(), mClient, false, "image", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "app-name", app.Name)
+}
+
+func TestPromptRecreateMissingApp(t *testing.T) {
+	var (
+		ctrl     = gomock.NewController(t)
+		mClient  = mocks.NewMockClient(ctrl)
+		notFound = apierror.NewNotFound(schema.GroupResource{Resource: "apps"}, "app-name")
+	)
+
+	mClient.EXPECT().AppGet(gomock.Any(), "app-name").Return(nil, notFound)
+	mClient.EXPECT().AppRun(gomock.Any(), "image", gomock.Any()).Return(&apiv1.App{}, nil)
+
+	_, err := PromptRecreate(context.Background(), mClient, false, "image", client.AppRunOptions{Name: "app-name"})
+	require.NoError(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"golang.org/x/exp/slices"
 )
 
 type RuleRequest struct {
@@ -87,3 +88,37 @@ func ruleToRequests(serviceName string, rule v1.PolicyRule, scope string) (resul
 
 	return
 }
+
+// OverGrants compares the granted permissions to the permissions that are used and returns the rule requests of the
+// grants that are not used, with only the unused verbs. A used rule covers the grants of the same service, scope and
+// namespace that it matches, including through wildcards.
+func OverGrants(granted, used []v1.Permissions) (result []RuleRequest) {
+	usedRequests := ToRuleRequests(used)
+	for _, request := range ToRuleRequests(granted) {
+		var unused []string
+		for _, verb := range strings.Split(request.Verbs, ",") {
+			if slices.IndexFunc(usedRequests, func(used RuleRequest) bool {
+				return covers(used, request, verb)
+			}) == -1 {
+				unused = append(unused, verb)
+			}
+		}
+		if len(unused) > 0 {
+			request.Verbs = strings.Join(unused, ",")
+			result = append(result, request)
+		}
+	}
+	return
+}
+
+func covers(used, granted RuleRequest, verb string) bool {
+	if used.Service != granted.Service || used.Scope != granted.Scope || used.Namespace != granted.Namespace {
+		return false
+	}
+	verbs := strings.Split(used.Verbs, ",")
+	if !slices.Contains(verbs, "*") && !slices.Contains(verbs, verb) {
+		return false
+	}
+	// a grant restricted to a resource name is covered by a use of all names of the resource
+	return used.Resource == "*" || used.Resource == granted.Resource || strings.HasPrefix(granted.Resource, used.Resource+"/")
+}
@@ -0,0 +1,64 @@
+package rulerequest
+
+import (
+	"testing"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func rule(verbs, apiGroups, resources, resourceNames []string, scopes ...string) v1.PolicyRule {
+	return v1.PolicyRule{
+		PolicyRule: rbacv1.PolicyRule{
+			Verbs:         verbs,
+			APIGroups:     apiGroups,
+			Resources:     resources,
+			ResourceNames: resourceNames,
+		},
+		Scopes: scopes,
+	}
+}
+
+func TestOverGrants(t *testing.T) {
+	granted := []v1.Permissions{
+		{
+			ServiceName: "web",
+			Rules: []v1.PolicyRule{
+				rule([]string{"get", "list", "delete"}, []string{""}, []string{"secrets"}, nil, "project"),
+				rule([]string{"get"}, []string{"apps"}, []string{"deployments"}, nil, "project"),
+				rule([]string{"get"}, []string{""}, []string{"configmaps"}, []string{"settings"}, "project"),
+			},
+		},
+		{
+			ServiceName: "worker",
+			Rules: []v1.PolicyRule{
+				rule([]string{"*"}, []string{""}, []string{"pods"}, nil, "project"),
+			},
+		},
+	}
+	used := []v1.Permissions{
+		{
+			ServiceName: "web",
+			Rules: []v1.PolicyRule{
+				rule([]string{"get", "list"}, []string{""}, []string{"secrets"}, nil, "project"),
+				rule([]string{"*"}, []string{""}, []string{"configmaps"}, nil, "project"),
+			},
+		},
+		{
+			ServiceName: "worker",
+			Rules: []v1.PolicyRule{
+				rule([]string{"get"}, []string{""}, []string{"pods"}, nil, "project"),
+			},
+		},
+	}
+
+	assert.Equal(t, []RuleRequest{
+		{Service: "web", Scope: "project", Namespace: "<APP>", Resource: "secrets", Verbs: "delete"},
+		{Service: "web", Scope: "project", Namespace: "<APP>", Resource: "deployments.apps", Verbs: "get"},
+		{Service: "worker", Scope: "project", Namespace: "<APP>", Resource: "pods", Verbs: "*"},
+	}, OverGrants(granted, used))
+
+	assert.Empty(t, OverGrants(granted, granted))
+	assert.Empty(t, OverGrants(nil, used))
+}
@@ -0,0 +1,121 @@
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/rancher/wrangler/pkg/data/convert"
+)
+
+const (
+	// defaultCharacters is the character set used for generated basic auth passwords
+	defaultCharacters = "bcdfghjklmnpqrstvwxz2456789"
+	// numericCharacters is the character set used for tokens with the numeric param, such as PINs
+	numericCharacters = "0123456789"
+	// maxGenerateAttempts is the number of values generated before giving up on meeting the constraints
+	maxGenerateAttempts = 10
+)
+
+// valueConstraints are the rules a generated secret value must meet, read from the minLength, pattern and
+// disallowedCharacters params of a secret.
+type valueConstraints struct {
+	minLength  int
+	pattern    *regexp.Regexp
+	disallowed string
+}
+
+func constraintsFromParams(params v1.GenericMap) (*valueConstraints, error) {
+	result := &valueConstraints{
+		disallowed: convert.ToString(params["disallowedCharacters"]),
+	}
+
+	if params["minLength"] != nil {
+		minLength, err := convert.ToNumber(params["minLength"])
+		if err != nil {
+			return nil, invalidParams(fmt.Errorf("invalid minLength param: %w", err))
+		}
+		result.minLength = int(minLength)
+	}
+
+	if pattern := convert.ToString(params["pattern"]); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, invalidParams(fmt.Errorf("invalid pattern param [%s]: %w", pattern, err))
+		}
+		result.pattern = re
+	}
+
+	return result, nil
+}
+
+// characters removes the disallowed characters from the given character set, and returns an error if no characters are
+// left to generate a value from
+func (c *valueConstraints) characters(characters string) (string, error) {
+	if c.disallowed != "" {
+		characters = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(c.disallowed, r) {
+				return -1
+			}
+			return r
+		}, characters)
+	}
+	if characters == "" {
+		if c.disallowed != "" {
+			return "", invalidParams(fmt.Errorf("disallowedCharacters param [%s] removes every character the value can be generated from", c.disallowed))
+		}
+		return "", invalidParams(fmt.Errorf("characters param must not be empty"))
+	}
+	return characters, nil
+}
+
+// secretCharacters returns the characters that the value of a basic or token secret is generated from, without the
+// disallowed characters. Only tokens have the numeric param.
+func secretCharacters(secretType string, params v1.GenericMap, constraints *valueConstraints) (string, error) {
+	characters := defaultCharacters
+	if c := convert.ToString(params["characters"]); c != "" {
+		characters = c
+	}
+	if secretType == "token" && convert.ToBool(params["numeric"]) {
+		characters = numericCharacters
+	}
+	return constraints.characters(characters)
+}
+
+// length returns the given length, raised to the minimum length if it is shorter
+func (c *valueConstraints) length(length int) int {
+	if length < c.minLength {
+		return c.minLength
+	}
+	return length
+}
+
+func (c *valueConstraints) check(value string) error {
+	if utf8.RuneCountInString(value) < c.minLength {
+		return fmt.Errorf("value is shorter than the minimum length %d", c.minLength)
+	}
+	if c.disallowed != "" && strings.ContainsAny(value, c.disallowed) {
+		return fmt.Errorf("value contains disallowed characters [%s]", c.disallowed)
+	}
+	if c.pattern != nil && !c.pattern.MatchString(value) {
+		return fmt.Errorf("value does not match pattern [%s]", c.pattern)
+	}
+	return nil
+}
+
+// generate calls gen until it returns a value that meets the constraints
+func (c *valueConstraints) generate(gen func() (string, error)) (string, error) {
+	var lastErr error
+	for i := 0; i < maxGenerateAttempts; i++ {
+		v, err := gen()
+		if err != nil {
+			return "", err
+		}
+		if lastErr = c.check(v); lastErr == nil {
+			return v, nil
+		}
+	}
+	return "", invalidParams(fmt.Errorf("failed to generate a value meeting the constraints after %d attempts: %w", maxGenerateAttempts, lastErr))
+}
@@ -0,0 +1,193 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/dockerconfig"
+	"github.com/acorn-io/acorn/pkg/jobs"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/tracing"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/typed"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// dockerRegistryParams returns the registry credential entries of the params of a docker secret. The entries are the
+// registries list, followed by the registry, username, password and secret params at the top level if a registry is
+// given there.
+func dockerRegistryParams(params v1.GenericMap) ([]v1.GenericMap, error) {
+	var result []v1.GenericMap
+	if registries, ok := params["registries"]; ok {
+		list, ok := registries.([]any)
+		if !ok {
+			return nil, invalidParams(fmt.Errorf("invalid registries param, must be a list"))
+		}
+		for i, entry := range list {
+			m, ok := entry.(map[string]any)
+			if !ok {
+				return nil, invalidParams(fmt.Errorf("invalid registries param, entry %d must be an object", i))
+			}
+			result = append(result, m)
+		}
+	}
+	if convert.ToString(params["registry"]) != "" {
+		result = append(result, params)
+	}
+	return result, nil
+}
+
+// DockerDependencies returns the names of the basic secrets that the credentials of a docker secret are read from
+func DockerDependencies(secretRef v1.Secret) []string {
+	entries, _ := dockerRegistryParams(secretRef.Params)
+	names := map[string]struct{}{}
+	for _, entry := range entries {
+		if name := convert.ToString(entry["secret"]); name != "" {
+			names[name] = struct{}{}
+		}
+	}
+	return typed.SortedKeys(names)
+}
+
+// generateDocker generates a .dockerconfigjson holding the credentials for one or more registries. The params are:
+//
+//	registry: the registry the credentials are for, such as index.docker.io
+//	username: the username for the registry
+//	password: the password for the registry
+//	secret: the name of a basic secret in the app to read the username and password from instead
+//	job: the name of a credential helper job that writes a short-lived token to use as the password instead
+//	tokenTTL: how long the token written by the job is valid, such as 12h, required with job
+//	registries: a list of entries with the same registry, username, password, secret, job and tokenTTL fields, for
+//	more registries
+//
+// A job is run again once 80% of the lifetime of its token has passed, and the current token is kept until the job
+// finishes again. The names of the jobs and the time to refresh them are recorded on the secret for RefreshDockerTokens
+// in the secrets controller.
+//
+// The credentials are merged into the .dockerconfigjson in the data of the secret, if one is supplied. If no registry
+// is given the supplied config is used as is, or the generated config has no auths.
+func generateDocker(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, namespace, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: secretName + "-",
+			Namespace:    namespace,
+			Labels:       labelsForSecret(secretName, namespace, appInstance, secretRef),
+			Annotations:  annotationsForSecret(secretName, appInstance, secretRef),
+		},
+		Data: seedData(existing, secretRef.Data, corev1.DockerConfigJsonKey),
+		Type: v1.SecretTypeDocker,
+	}
+
+	entries, err := dockerRegistryParams(secretRef.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		if len(secret.Data[corev1.DockerConfigJsonKey]) == 0 {
+			secret.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths":{}}`)
+		}
+		return updateOrCreate(req, existing, secret)
+	}
+
+	var (
+		creds     = map[string]dockerconfig.Credential{}
+		tokenJobs []string
+		refreshAt time.Time
+	)
+	for _, entry := range entries {
+		registry := convert.ToString(entry["registry"])
+		if registry == "" {
+			return nil, invalidParams(fmt.Errorf("registry is required for each entry of registries in secret [%s]", secretName))
+		}
+
+		cred := dockerconfig.Credential{
+			Username: convert.ToString(entry["username"]),
+			Password: convert.ToString(entry["password"]),
+		}
+		if name := convert.ToString(entry["secret"]); name != "" {
+			basic, err := GetOrCreateSecret(secrets, req, appInstance, name)
+			if err != nil {
+				return nil, err
+			}
+			cred.Username = string(basic.Data[corev1.BasicAuthUsernameKey])
+			cred.Password = string(basic.Data[corev1.BasicAuthPasswordKey])
+		}
+		if job := convert.ToString(entry["job"]); job != "" {
+			ttl, err := dockerTokenTTL(entry)
+			if err != nil {
+				return nil, invalidParams(err)
+			}
+			token, refresh, err := getDockerToken(req, appInstance, job, ttl)
+			if (errors.Is(err, jobs.ErrJobNotDone) || apierrors.IsNotFound(err)) && existing != nil && len(existing.Data) > 0 {
+				// The job is running again to refresh the token, keep the current one until it is done
+				return existing, nil
+			} else if err != nil {
+				return nil, err
+			}
+			cred.Password = token
+			tokenJobs = append(tokenJobs, job)
+			if refreshAt.IsZero() || refresh.Before(refreshAt) {
+				refreshAt = refresh
+			}
+		}
+
+		if cred.Username == "" || cred.Password == "" {
+			return nil, invalidParams(fmt.Errorf("username and password are required for registry [%s] in secret [%s]", registry, secretName))
+		}
+		creds[registry] = cred
+	}
+
+	// The credentials are always merged into the supplied config rather than the existing one, so that registries
+	// removed from the params are dropped
+	config, err := dockerconfig.Merge([]byte(secretRef.Data[corev1.DockerConfigJsonKey]), creds)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+	secret.Data[corev1.DockerConfigJsonKey] = config
+
+	if len(tokenJobs) > 0 {
+		secret.Annotations[labels.AcornSecretTokenJobs] = strings.Join(tokenJobs, ",")
+		secret.Annotations[labels.AcornSecretTokenRefresh] = refreshAt.UTC().Format(time.RFC3339)
+	}
+
+	return updateOrCreate(req, existing, secret)
+}
+
+// dockerTokenTTL returns the tokenTTL param of a registry entry with a credential helper job
+func dockerTokenTTL(entry v1.GenericMap) (time.Duration, error) {
+	ttl, err := time.ParseDuration(convert.ToString(entry["tokenTTL"]))
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid tokenTTL [%v] for registry [%s], must be a positive duration such as 12h",
+			entry["tokenTTL"], convert.ToString(entry["registry"]))
+	}
+	return ttl, nil
+}
+
+// getDockerToken returns the token written by a credential helper job and when the job must run again to refresh it.
+// The lifetime of the token starts when the job finished.
+func getDockerToken(req router.Request, appInstance *v1.AppInstance, jobName string, ttl time.Duration) (_ string, _ time.Time, err error) {
+	req, span := tracing.Start(req, "getJobOutput", attribute.String("acorn.job", jobName))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	var token string
+	job, err := jobs.GetOutputFor(req.Ctx, req.Client, appInstance, jobName, "", &token)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	issued := job.CreationTimestamp.Time
+	if job.Status.CompletionTime != nil {
+		issued = job.Status.CompletionTime.Time
+	}
+	return strings.TrimSpace(token), issued.Add(ttl * 4 / 5), nil
+}
@@ -0,0 +1,52 @@
+package secrets
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseDotenv parses KEY=value lines as written by env file tooling. Blank lines and lines starting with # are
+// ignored, an "export " prefix is allowed, double-quoted values support escapes, single-quoted values are taken
+// literally, and unquoted values end at an inline " #" comment.
+func parseDotenv(content string) (map[string]string, error) {
+	result := map[string]string{}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid dotenv line %d: expected KEY=value", lineNumber)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, `"`):
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid dotenv line %d: bad quoted value for %s", lineNumber, key)
+			}
+			value = unquoted
+		case strings.HasPrefix(value, "'"):
+			if len(value) < 2 || !strings.HasSuffix(value, "'") {
+				return nil, fmt.Errorf("invalid dotenv line %d: unterminated quoted value for %s", lineNumber, key)
+			}
+			value = value[1 : len(value)-1]
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+
+		result[key] = value
+	}
+
+	return result, scanner.Err()
+}
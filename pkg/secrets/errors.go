@@ -0,0 +1,104 @@
+package secrets
+
+import (
+	"errors"
+
+	"github.com/acorn-io/acorn/pkg/jobs"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// GenerationReason categorizes why a secret could not be generated
+type GenerationReason string
+
+const (
+	// GenerationReasonJob means the job producing the secret has not finished or its output could not be used
+	GenerationReasonJob = GenerationReason("job")
+	// GenerationReasonInvalidParams means the params of the secret are invalid or can't be satisfied
+	GenerationReasonInvalidParams = GenerationReason("invalid-params")
+	// GenerationReasonAPI means a request to the Kubernetes API failed
+	GenerationReasonAPI = GenerationReason("api")
+	// GenerationReasonWaiting means the secret is waiting on something outside of Acorn, like an operator
+	GenerationReasonWaiting = GenerationReason("waiting")
+	// GenerationReasonDisallowed means the type of the secret is not allowed in the project of the app
+	GenerationReasonDisallowed = GenerationReason("disallowed")
+	GenerationReasonUnknown    = GenerationReason("unknown")
+)
+
+// ErrSecretGeneration is returned when a secret defined in the app could not be generated. The message is the message
+// of the cause, so wrapping it doesn't change what is reported in the app status.
+type ErrSecretGeneration struct {
+	Name   string
+	Type   string
+	Reason GenerationReason
+	Cause  error
+}
+
+func (e *ErrSecretGeneration) Error() string {
+	return e.Cause.Error()
+}
+
+func (e *ErrSecretGeneration) Unwrap() error {
+	return e.Cause
+}
+
+// reasonError records the reason of a failure at the point it happens, so that it ends up in the ErrSecretGeneration
+type reasonError struct {
+	reason GenerationReason
+	err    error
+}
+
+func (e *reasonError) Error() string {
+	return e.err.Error()
+}
+
+func (e *reasonError) Unwrap() error {
+	return e.err
+}
+
+func invalidParams(err error) error {
+	return &reasonError{reason: GenerationReasonInvalidParams, err: err}
+}
+
+func invalidJobOutput(err error) error {
+	return &reasonError{reason: GenerationReasonJob, err: err}
+}
+
+func waiting(err error) error {
+	return &reasonError{reason: GenerationReasonWaiting, err: err}
+}
+
+func disallowed(err error) error {
+	return &reasonError{reason: GenerationReasonDisallowed, err: err}
+}
+
+func generationReason(err error) GenerationReason {
+	var (
+		genErr    *ErrSecretGeneration
+		reasonErr *reasonError
+		apiErr    apierrors.APIStatus
+	)
+	switch {
+	case errors.As(err, &genErr):
+		// A secret this one depends on failed, so this one failed for the same reason
+		return genErr.Reason
+	case errors.As(err, &reasonErr):
+		return reasonErr.reason
+	case errors.Is(err, jobs.ErrJobNotDone) || errors.Is(err, jobs.ErrJobNoOutput):
+		return GenerationReasonJob
+	case errors.As(err, &apiErr):
+		return GenerationReasonAPI
+	}
+	return GenerationReasonUnknown
+}
+
+func newGenerationError(secretName, secretType string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ErrSecretGeneration{
+		Name:   secretName,
+		Type:   secretType,
+		Reason: generationReason(err),
+		Cause:  err,
+	}
+}
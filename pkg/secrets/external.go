@@ -0,0 +1,156 @@
+package secrets
+
+import (
+	"fmt"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/name"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/typed"
+	"github.com/acorn-io/baaah/pkg/uncached"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	externalSecretCRD             = "externalsecrets.external-secrets.io"
+	defaultExternalSecretStore    = "SecretStore"
+	defaultExternalSecretInterval = "1h"
+)
+
+var externalSecretGVK = schema.GroupVersionKind{
+	Group:   "external-secrets.io",
+	Version: "v1beta1",
+	Kind:    "ExternalSecret",
+}
+
+// toExternalSecret builds the ExternalSecret that the external-secrets operator will sync into a Secret carrying the
+// same labels as an Acorn generated secret, so that it is found by getSecret once it exists. The params are:
+//
+//	secretStore: name of the SecretStore (required)
+//	secretStoreKind: SecretStore or ClusterSecretStore (default SecretStore)
+//	refreshInterval: how often the operator refreshes the data (default 1h)
+//	data: map of secret key to a remote key string, or an object with key, property and version fields
+func toExternalSecret(appInstance *v1.AppInstance, secretName string, secretRef v1.Secret) (*unstructured.Unstructured, error) {
+	store := convert.ToString(secretRef.Params["secretStore"])
+	if store == "" {
+		return nil, fmt.Errorf("secret [%s] of type external requires the secretStore param", secretName)
+	}
+
+	storeKind := convert.ToString(secretRef.Params["secretStoreKind"])
+	if storeKind == "" {
+		storeKind = defaultExternalSecretStore
+	}
+
+	refreshInterval := convert.ToString(secretRef.Params["refreshInterval"])
+	if refreshInterval == "" {
+		refreshInterval = defaultExternalSecretInterval
+	}
+
+	dataParam, _ := secretRef.Params["data"].(map[string]interface{})
+	if len(dataParam) == 0 {
+		return nil, fmt.Errorf("secret [%s] of type external requires at least one entry in the data param", secretName)
+	}
+
+	data := make([]interface{}, 0, len(dataParam))
+	for _, entry := range typed.Sorted(dataParam) {
+		remoteRef := map[string]interface{}{}
+		switch v := entry.Value.(type) {
+		case string:
+			if v != "" {
+				remoteRef["key"] = v
+			}
+		case map[string]interface{}:
+			for _, field := range []string{"key", "property", "version"} {
+				if s := convert.ToString(v[field]); s != "" {
+					remoteRef[field] = s
+				}
+			}
+		}
+		if remoteRef["key"] == nil {
+			return nil, fmt.Errorf("secret [%s] of type external is missing the remote key for data [%s]", secretName, entry.Key)
+		}
+		data = append(data, map[string]interface{}{
+			"secretKey": entry.Key,
+			"remoteRef": remoteRef,
+		})
+	}
+
+	targetName := name.SafeConcatName(appInstance.Name, secretName)
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"refreshInterval": refreshInterval,
+				"secretStoreRef": map[string]interface{}{
+					"name": store,
+					"kind": storeKind,
+				},
+				"target": map[string]interface{}{
+					"name":           targetName,
+					"creationPolicy": "Owner",
+					"template": map[string]interface{}{
+						"type": string(corev1.SecretTypeOpaque),
+						"metadata": map[string]interface{}{
+							"labels":      toInterfaceMap(labelsForSecret(secretName, appInstance.Namespace, appInstance, secretRef)),
+							"annotations": toInterfaceMap(annotationsForSecret(secretName, appInstance, secretRef)),
+						},
+					},
+				},
+				"data": data,
+			},
+		},
+	}
+	obj.SetGroupVersionKind(externalSecretGVK)
+	obj.SetName(targetName)
+	obj.SetNamespace(appInstance.Namespace)
+	obj.SetLabels(acornLabelsForSecret(secretName, appInstance.Namespace, appInstance))
+	return obj, nil
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func generateExternal(req router.Request, appInstance *v1.AppInstance, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
+	crd := &apiextensionv1.CustomResourceDefinition{}
+	if err := req.Get(uncached.Get(crd), "", externalSecretCRD); apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("secret [%s] is of type external, but the external-secrets operator is not installed "+
+			"(CustomResourceDefinition %s not found)", secretName, externalSecretCRD)
+	} else if err != nil {
+		return nil, err
+	}
+
+	externalSecret, err := toExternalSecret(appInstance, secretName, secretRef)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(externalSecretGVK)
+	if err := req.Get(uncached.Get(current), externalSecret.GetNamespace(), externalSecret.GetName()); apierrors.IsNotFound(err) {
+		if err := req.Client.Create(req.Ctx, externalSecret); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if !equality.Semantic.DeepEqual(current.Object["spec"], externalSecret.Object["spec"]) {
+		current.Object["spec"] = externalSecret.Object["spec"]
+		if err := req.Client.Update(req.Ctx, current); err != nil {
+			return nil, err
+		}
+	}
+
+	if existing == nil {
+		return nil, waiting(fmt.Errorf("waiting for the external-secrets operator to sync secret [%s]", secretName))
+	}
+	return existing, nil
+}
@@ -0,0 +1,94 @@
+package secrets
+
+import (
+	"fmt"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/config"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/typed"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// GenerationJobSettings returns the backoff limit and the TTL after finishing to set on the job with the given name,
+// and false if the job doesn't generate any secret of the app. The defaults come from the config and can be
+// overridden by the backoffLimit and ttlSecondsAfterFinished params of the secrets. If several secrets are generated
+// by the job, the first of them by name that sets a param wins. A nil TTL keeps the job after it finishes.
+func GenerationJobSettings(cfg *apiv1.Config, appSpec *v1.AppSpec, jobName string) (backoffLimit, ttlSeconds *int32, ok bool, err error) {
+	var backoffLimitSet, ttlSecondsSet bool
+	for _, entry := range typed.Sorted(appSpec.Secrets) {
+		if entry.Value.Type != "generated" || convert.ToString(entry.Value.Params["job"]) != jobName {
+			continue
+		}
+		if !ok {
+			ok = true
+			backoffLimit = toInt32(*cfg.GenerationJobBackoffLimit)
+			ttlSeconds = toTTL(*cfg.GenerationJobTTLSeconds)
+		}
+		if v, set := entry.Value.Params["backoffLimit"]; set && !backoffLimitSet {
+			n, err := generationJobParam(v)
+			if err != nil {
+				return nil, nil, false, fmt.Errorf("invalid backoffLimit of secret [%s]: %w", entry.Key, err)
+			}
+			backoffLimit, backoffLimitSet = toInt32(n), true
+		}
+		if v, set := entry.Value.Params["ttlSecondsAfterFinished"]; set && !ttlSecondsSet {
+			n, err := generationJobParam(v)
+			if err != nil {
+				return nil, nil, false, fmt.Errorf("invalid ttlSecondsAfterFinished of secret [%s]: %w", entry.Key, err)
+			}
+			ttlSeconds, ttlSecondsSet = toTTL(n), true
+		}
+	}
+	return backoffLimit, ttlSeconds, ok, nil
+}
+
+// GenerationJobFinished returns true if every secret generated by the job with the given name exists and doesn't
+// need to be regenerated. A job that was deleted after it finished doesn't need to run again in that case, the
+// secrets keep the values it generated.
+func GenerationJobFinished(req router.Request, appInstance *v1.AppInstance, jobName string) (bool, error) {
+	cfg, err := config.Get(req.Ctx, req.Client)
+	if err != nil {
+		return false, err
+	}
+
+	found := false
+	for _, entry := range typed.Sorted(appInstance.Status.AppSpec.Secrets) {
+		if entry.Value.Type != "generated" || convert.ToString(entry.Value.Params["job"]) != jobName {
+			continue
+		}
+		found = true
+		existing, err := getSecret(req, appInstance, backingSecretNamespace(cfg, appInstance), entry.Key)
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		if len(existing.Data) == 0 || needsRegeneration(existing, entry.Value) {
+			return false, nil
+		}
+	}
+	return found, nil
+}
+
+func generationJobParam(v interface{}) (int, error) {
+	n, err := convert.ToNumber(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("must be a non-negative number, got [%v]", v)
+	}
+	return int(n), nil
+}
+
+func toInt32(n int) *int32 {
+	return &[]int32{int32(n)}[0]
+}
+
+// toTTL returns nil for a TTL of 0, which keeps the job after it finishes
+func toTTL(n int) *int32 {
+	if n == 0 {
+		return nil
+	}
+	return toInt32(n)
+}
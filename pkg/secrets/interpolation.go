@@ -3,6 +3,7 @@ package secrets
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"path"
 	"strconv"
@@ -31,6 +32,9 @@ import (
 )
 
 var (
+	// ErrSecretReference is returned when content that is stored in a non-secret object references secret values
+	ErrSecretReference = errors.New("secret values can only be referenced from secrets")
+
 	serviceTokens = sets.NewString("address",
 		"hostname",
 		"port",
@@ -54,6 +58,8 @@ type Interpolator struct {
 	namespace   string
 	serviceName string
 	errs        *[]error
+	// sensitive is set when a replacement resolves a secret value
+	sensitive bool
 }
 
 type Ref struct {
@@ -169,6 +175,7 @@ func (i *Interpolator) resolveSecrets(secretName []string, keyName string) (stri
 	} else if err != nil {
 		return "", false, err
 	}
+	i.sensitive = true
 	return string(secret.Data[keyName]), true, nil
 }
 
@@ -204,6 +211,7 @@ func (i *Interpolator) serviceProperty(svc *v1.ServiceInstance, prop string, ext
 		} else if err != nil {
 			return "", err
 		}
+		i.sensitive = true
 		return string(secret.Data[extra[1]]), nil
 	case "endpoint":
 		if len(svc.Status.Endpoints) > 0 {
@@ -333,11 +341,78 @@ func (i *Interpolator) replace(content string) (string, error) {
 		return "", err
 	}
 	return replace.Replace(content, nacl.EncPrefix, nacl.EncSuffix, func(s string) (string, bool, error) {
+		i.sensitive = true
 		data, err := nacl.DecryptNamespacedData(i.ctx, i.client, []byte(nacl.EncPrefix+s+nacl.EncSuffix), i.app.Namespace)
 		return string(data), true, err
 	})
 }
 
+// ReplacePlain interpolates content that will be stored in a non-secret object, such as a ConfigMap or the args of a
+// container. References to secrets, secrets of services and encrypted values are refused with ErrSecretReference.
+func (i *Interpolator) ReplacePlain(content string) (string, error) {
+	i.sensitive = false
+	newValue, err := i.replace(content)
+	if err != nil {
+		return "", err
+	}
+	if i.sensitive {
+		return "", fmt.Errorf("%w: [%s]", ErrSecretReference, content)
+	}
+	return newValue, nil
+}
+
+// ContainsSecretReference returns true if content references a secret value, either directly or through a secret of a
+// service, or holds an encrypted value. Such content must never be stored in a non-secret object.
+func ContainsSecretReference(content string) bool {
+	if strings.Contains(content, nacl.EncPrefix) || templateSecretRegexp.MatchString(content) {
+		return true
+	}
+
+	found := false
+	_, _ = replace.Replace(content, "@{", "}", func(token string) (string, bool, error) {
+		found = found || isSecretToken(token)
+		return "", false, nil
+	})
+	return found
+}
+
+func isSecretToken(token string) bool {
+	if scheme, _, ok := strings.Cut(token, "://"); ok {
+		return scheme == "secret" || scheme == "secrets"
+	}
+
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	switch parts[0] {
+	case "secret", "secrets":
+		return true
+	case "service", "services":
+		_, properties, err := splitServiceProperty(parts[1:])
+		return err == nil && (properties[0] == "secret" || properties[0] == "secrets")
+	}
+	return false
+}
+
+// ToArgs interpolates the args of a container. Args that only reference non-secret values, such as the address of a
+// service, are rendered in place. Args that reference secret values are stored in the interpolated secret and passed
+// through an environment variable that is returned with the args, so the values never appear in the pod spec.
+func (i *Interpolator) ToArgs(args []string) (result []string, env []corev1.EnvVar) {
+	for idx, arg := range args {
+		newValue, err := i.ReplacePlain(arg)
+		if errors.Is(err, ErrSecretReference) {
+			envVar := i.ToEnv(fmt.Sprintf("ACORN_ARG_%d", idx), arg)
+			env = append(env, envVar)
+			result = append(result, "$("+envVar.Name+")")
+			continue
+		} else if err != nil {
+			*i.errs = append(*i.errs, err)
+			i.missing[i.serviceName] = append(i.missing[i.serviceName], err.Error())
+			newValue = arg
+		}
+		result = append(result, newValue)
+	}
+	return result, env
+}
+
 func (i *Interpolator) ToEnv(key, value string) corev1.EnvVar {
 	newValue, err := i.replace(value)
 	if err != nil {
@@ -0,0 +1,233 @@
+package secrets
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	JWTPrivateKeyKey = "key.pem"
+	JWTJWKSKey       = "jwks.json"
+	JWTKeyIDKey      = "kid"
+
+	jwtDefaultAlgorithm   = "RS256"
+	jwtDefaultRetainKeys  = 1
+	jwtRSAKeySize         = 2048
+	jwtPrivateKeyPEMBlock = "PRIVATE KEY"
+	jwtRSAKeyPEMBlock     = "RSA PRIVATE KEY"
+
+	JWTKeyFormatPKCS8 = "pkcs8"
+	JWTKeyFormatPKCS1 = "pkcs1"
+)
+
+// JWK is a public JSON Web Key as defined by RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set as defined by RFC 7517
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// generateJWT generates a signing key and a JWKS holding its public key. The params are:
+//
+//	algorithm: RS256, ES256 or EdDSA (default RS256)
+//	keyId: the kid of the key (default the RFC 7638 thumbprint of the public key)
+//	retainKeys: number of previous public keys kept in the JWKS when the secret is regenerated (default 1)
+//	keyFormat: pkcs8 or pkcs1, the encoding of the private key PEM (default pkcs8), pkcs1 is only valid for RS256
+//
+// The previous data is the data of the secret before it was regenerated, if it is being regenerated.
+func generateJWT(req router.Request, appInstance *v1.AppInstance, namespace, secretName string, secretRef v1.Secret, existing *corev1.Secret, previous map[string][]byte) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: secretName + "-",
+			Namespace:    namespace,
+			Labels:       labelsForSecret(secretName, namespace, appInstance, secretRef),
+			Annotations:  annotationsForSecret(secretName, appInstance, secretRef),
+		},
+		Data: seedData(existing, secretRef.Data, JWTPrivateKeyKey, JWTJWKSKey, JWTKeyIDKey),
+		Type: v1.SecretTypeJWT,
+	}
+
+	if len(secret.Data[JWTPrivateKeyKey]) > 0 {
+		return updateOrCreate(req, existing, secret)
+	}
+
+	algorithm := convert.ToString(secretRef.Params["algorithm"])
+	if algorithm == "" {
+		algorithm = jwtDefaultAlgorithm
+	}
+
+	retainKeys := jwtDefaultRetainKeys
+	if v, ok := secretRef.Params["retainKeys"]; ok {
+		n, err := convert.ToNumber(v)
+		if err != nil || n < 0 {
+			return nil, invalidParams(fmt.Errorf("invalid retainKeys [%v] for secret [%s], must be a non-negative number", v, secretName))
+		}
+		retainKeys = int(n)
+	}
+
+	keyFormat, err := jwtKeyFormat(secretRef.Params, algorithm)
+	if err != nil {
+		return nil, invalidParams(fmt.Errorf("%w for secret [%s]", err, secretName))
+	}
+
+	privateKey, publicKey, err := generateJWTKey(algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("generating JWT signing key for secret [%s]: %w", secretName, err)
+	}
+
+	keyBlock, err := marshalJWTKey(privateKey, keyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := toJWK(publicKey, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	key.KeyID = convert.ToString(secretRef.Params["keyId"])
+	if key.KeyID == "" {
+		if key.KeyID, err = jwkThumbprint(key); err != nil {
+			return nil, err
+		}
+	}
+
+	keySet := JWKS{
+		Keys: []JWK{key},
+	}
+	if len(previous[JWTJWKSKey]) > 0 {
+		var old JWKS
+		if err := json.Unmarshal(previous[JWTJWKSKey], &old); err != nil {
+			return nil, fmt.Errorf("parsing the previous %s of secret [%s]: %w", JWTJWKSKey, secretName, err)
+		}
+		for _, oldKey := range old.Keys {
+			if len(keySet.Keys) > retainKeys {
+				break
+			}
+			if oldKey.KeyID != key.KeyID {
+				keySet.Keys = append(keySet.Keys, oldKey)
+			}
+		}
+	}
+
+	jwks, err := json.Marshal(keySet)
+	if err != nil {
+		return nil, err
+	}
+
+	secret.Data[JWTPrivateKeyKey] = pem.EncodeToMemory(keyBlock)
+	secret.Data[JWTJWKSKey] = jwks
+	secret.Data[JWTKeyIDKey] = []byte(key.KeyID)
+	return updateOrCreate(req, existing, secret)
+}
+
+func generateJWTKey(algorithm string) (crypto.PrivateKey, crypto.PublicKey, error) {
+	switch algorithm {
+	case "RS256", "ES256", "EdDSA":
+		return source.GenerateKey(algorithm)
+	default:
+		return nil, nil, invalidParams(fmt.Errorf("invalid algorithm [%s], must be RS256, ES256 or EdDSA", algorithm))
+	}
+}
+
+// jwtKeyFormat returns the keyFormat param of a JWT secret, or an error if the format is unknown or can't encode keys of
+// the algorithm. PKCS #1 only holds RSA keys, so it is only valid for RS256.
+func jwtKeyFormat(params v1.GenericMap, algorithm string) (string, error) {
+	switch format := convert.ToString(params["keyFormat"]); format {
+	case "", JWTKeyFormatPKCS8:
+		return JWTKeyFormatPKCS8, nil
+	case JWTKeyFormatPKCS1:
+		if algorithm != "RS256" {
+			return "", fmt.Errorf("keyFormat [%s] is only supported for algorithm RS256, not [%s]", format, algorithm)
+		}
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid keyFormat [%s], must be pkcs8 or pkcs1", format)
+	}
+}
+
+// marshalJWTKey encodes the private key as a PEM block in the given format
+func marshalJWTKey(privateKey crypto.PrivateKey, format string) (*pem.Block, error) {
+	if rsaKey, ok := privateKey.(*rsa.PrivateKey); ok && format == JWTKeyFormatPKCS1 {
+		return &pem.Block{Type: jwtRSAKeyPEMBlock, Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}, nil
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &pem.Block{Type: jwtPrivateKeyPEMBlock, Bytes: keyDER}, nil
+}
+
+func toJWK(publicKey crypto.PublicKey, algorithm string) (JWK, error) {
+	key := JWK{
+		Use:       "sig",
+		Algorithm: algorithm,
+	}
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		key.KeyType = "RSA"
+		key.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		key.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		key.KeyType = "EC"
+		key.Curve = k.Curve.Params().Name
+		key.X = base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size)))
+		key.Y = base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		key.KeyType = "OKP"
+		key.Curve = "Ed25519"
+		key.X = base64.RawURLEncoding.EncodeToString(k)
+	default:
+		return JWK{}, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return key, nil
+}
+
+// jwkThumbprint computes the RFC 7638 thumbprint of the key, the hash of its required members in lexicographic order
+func jwkThumbprint(key JWK) (string, error) {
+	members := map[string]string{
+		"kty": key.KeyType,
+	}
+	switch key.KeyType {
+	case "RSA":
+		members["e"], members["n"] = key.E, key.N
+	case "EC":
+		members["crv"], members["x"], members["y"] = key.Curve, key.X, key.Y
+	case "OKP":
+		members["crv"], members["x"] = key.Curve, key.X
+	}
+
+	// json.Marshal sorts the keys of maps, which gives the canonical form required by the thumbprint
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
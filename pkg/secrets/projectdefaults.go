@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/baaah/pkg/router"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ProjectDefaults returns the default secrets of a project, stored as JSON in an annotation of the project namespace
+func ProjectDefaults(ns *corev1.Namespace) (map[string]v1.Secret, error) {
+	data := ns.Annotations[labels.AcornProjectDefaultSecrets]
+	if data == "" {
+		return nil, nil
+	}
+
+	var result map[string]v1.Secret
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return nil, fmt.Errorf("invalid default secrets of project [%s]: %w", ns.Name, err)
+	}
+	return result, nil
+}
+
+// AddProjectDefaults declares the default secrets of the project of the app in the secrets of the app. A secret the
+// app declares itself takes precedence over the default of the same name. Once declared, the defaults are generated
+// and resolved like any other secret of the app.
+func AddProjectDefaults(req router.Request, appInstance *v1.AppInstance) error {
+	ns := &corev1.Namespace{}
+	if err := req.Get(ns, "", appInstance.Namespace); apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	defaults, err := ProjectDefaults(ns)
+	if err != nil {
+		return err
+	}
+	for secretName, secret := range defaults {
+		if _, declared := appInstance.Status.AppSpec.Secrets[secretName]; declared {
+			continue
+		}
+		if appInstance.Status.AppSpec.Secrets == nil {
+			appInstance.Status.AppSpec.Secrets = map[string]v1.Secret{}
+		}
+		appInstance.Status.AppSpec.Secrets[secretName] = secret
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package secrets
 import (
 	"context"
 	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"math/big"
 	"regexp"
@@ -11,6 +13,7 @@ import (
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/config"
 	"github.com/acorn-io/acorn/pkg/encryption/nacl"
 	"github.com/acorn-io/acorn/pkg/images"
 	"github.com/acorn-io/acorn/pkg/jobs"
@@ -18,11 +21,13 @@ import (
 	"github.com/acorn-io/acorn/pkg/publicname"
 	"github.com/acorn-io/acorn/pkg/ref"
 	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/acorn-io/acorn/pkg/tracing"
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/typed"
+	"github.com/acorn-io/baaah/pkg/uncached"
 	"github.com/rancher/wrangler/pkg/data/convert"
 	"github.com/rancher/wrangler/pkg/merr"
-	"github.com/rancher/wrangler/pkg/randomtoken"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/maps"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -30,6 +35,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -78,63 +84,189 @@ func getJSONSecretData(ctx context.Context, c kclient.Client, appInstance *v1.Ap
 	return newSecret, nil
 }
 
-func generatedSecret(req router.Request, appInstance *v1.AppInstance, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
-	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: secretName + "-",
-			Namespace:    appInstance.Namespace,
-			Labels:       labelsForSecret(secretName, appInstance, secretRef),
-			Annotations:  annotationsForSecret(secretName, appInstance, secretRef),
-		},
-		Data: seedData(existing, secretRef.Data),
-		Type: v1.SecretTypeGenerated,
+func getDotenvSecretData(ctx context.Context, c kclient.Client, appInstance *v1.AppInstance, secretRef v1.Secret, secretName string) (*v1.Secret, error) {
+	var output string
+	_, err := jobs.GetOutputFor(ctx, c, appInstance, convert.ToString(secretRef.Params["job"]), secretName, &output)
+	if err != nil {
+		return nil, err
 	}
+	data, err := parseDotenv(output)
+	if err != nil {
+		return nil, invalidJobOutput(fmt.Errorf("parsing output of job for secret [%s]: %w", secretName, err))
+	}
+	return &v1.Secret{
+		Data: data,
+	}, nil
+}
 
-	var (
-		newSecret *v1.Secret
-		format    = convert.ToString(secretRef.Params["format"])
-		err       error
-	)
+// generatedTypeKeys are the types that can be declared for a generated secret with the type param, and the keys the
+// output of the job must have for each type
+var generatedTypeKeys = map[corev1.SecretType][]string{
+	v1.SecretTypeOpaque:    nil,
+	v1.SecretTypeGenerated: nil,
+	v1.SecretTypeBasic:     {"username", "password"},
+	v1.SecretTypeToken:     {"token"},
+	v1.SecretTypeTLS:       {corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+	v1.SecretTypeJWT:       {"key.pem", "jwks.json", "kid"},
+	v1.SecretTypeDocker:    {corev1.DockerConfigJsonKey},
+}
+
+// kubernetesSecretTypes maps the Kubernetes secret types that can be declared for a generated secret to the equivalent
+// acorn type. The secret keeps the acorn type, so it is still listed and managed as an acorn secret.
+var kubernetesSecretTypes = map[corev1.SecretType]corev1.SecretType{
+	corev1.SecretTypeOpaque:           v1.SecretTypeOpaque,
+	corev1.SecretTypeBasicAuth:        v1.SecretTypeBasic,
+	corev1.SecretTypeTLS:              v1.SecretTypeTLS,
+	corev1.SecretTypeDockerConfigJson: v1.SecretTypeDocker,
+}
+
+// declaredSecretType returns the type declared by the type param of a generated secret, or an empty type if none is
+// declared
+func declaredSecretType(params v1.GenericMap) (corev1.SecretType, error) {
+	declared := convert.ToString(params["type"])
+	if declared == "" {
+		return "", nil
+	}
+	if secretType, ok := kubernetesSecretTypes[corev1.SecretType(declared)]; ok {
+		return secretType, nil
+	}
+	secretType := corev1.SecretType(v1.SecretTypePrefix + strings.TrimPrefix(declared, v1.SecretTypePrefix))
+	if _, ok := generatedTypeKeys[secretType]; !ok {
+		return "", fmt.Errorf("invalid generated secret type [%s]", declared)
+	}
+	return secretType, nil
+}
+
+// missingTypeKeys returns the keys that secrets of the type must have but the data lacks
+func missingTypeKeys(secretType corev1.SecretType, data map[string][]byte) (missing []string) {
+	for _, key := range generatedTypeKeys[secretType] {
+		if len(data[key]) == 0 {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// checkTypeKeys returns an error if the data lacks a key that secrets of the type must have
+func checkTypeKeys(secretType corev1.SecretType, data map[string][]byte) error {
+	if missing := missingTypeKeys(secretType, data); len(missing) > 0 {
+		return fmt.Errorf("output for secret of type [%s] is missing keys [%s]",
+			strings.TrimPrefix(string(secretType), v1.SecretTypePrefix), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkTLSCert returns an error if the tls.crt of a TLS secret is not a PEM encoded certificate that can be parsed
+func checkTLSCert(data map[string][]byte) error {
+	block, _ := pem.Decode(data[corev1.TLSCertKey])
+	if block == nil {
+		return fmt.Errorf("invalid certificate in [%s]: no PEM data found", corev1.TLSCertKey)
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return fmt.Errorf("invalid certificate in [%s]: %w", corev1.TLSCertKey, err)
+	}
+	return nil
+}
+
+// getJobOutput reads the secret written by the job of a generated secret in the given format
+func getJobOutput(req router.Request, appInstance *v1.AppInstance, secretRef v1.Secret, secretName, format string) (_ *v1.Secret, err error) {
+	req, span := tracing.Start(req, "getJobOutput", attribute.String("acorn.secret", secretName))
+	defer func() {
+		tracing.End(span, err)
+	}()
 
 	switch format {
 	case "":
-		newSecret, err = getJSONSecretData(req.Ctx, req.Client, appInstance, secretRef, secretName)
+		newSecret, err := getJSONSecretData(req.Ctx, req.Client, appInstance, secretRef, secretName)
 		if err != nil {
-			newSecret, err = getTextSecretData(req.Ctx, req.Client, appInstance, secretRef, secretName)
+			return getTextSecretData(req.Ctx, req.Client, appInstance, secretRef, secretName)
 		}
+		return newSecret, nil
 	case "text":
-		newSecret, err = getTextSecretData(req.Ctx, req.Client, appInstance, secretRef, secretName)
+		return getTextSecretData(req.Ctx, req.Client, appInstance, secretRef, secretName)
+	case "dotenv":
+		return getDotenvSecretData(req.Ctx, req.Client, appInstance, secretRef, secretName)
 	case "aml":
 		fallthrough
 	case "json":
-		newSecret, err = getJSONSecretData(req.Ctx, req.Client, appInstance, secretRef, secretName)
+		return getJSONSecretData(req.Ctx, req.Client, appInstance, secretRef, secretName)
 	default:
-		return nil, fmt.Errorf("invalid generated secret format [%s]", format)
+		return nil, invalidParams(fmt.Errorf("invalid generated secret format [%s]", format))
+	}
+}
+
+func generatedSecret(req router.Request, appInstance *v1.AppInstance, namespace, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: secretName + "-",
+			Namespace:    namespace,
+			Labels:       labelsForSecret(secretName, namespace, appInstance, secretRef),
+			Annotations:  annotationsForSecret(secretName, appInstance, secretRef),
+		},
+		Data: seedData(existing, secretRef.Data),
+		Type: v1.SecretTypeGenerated,
 	}
 
+	declaredType, err := declaredSecretType(secretRef.Params)
 	if err != nil {
+		return nil, invalidParams(err)
+	}
+
+	newSecret, err := getJobOutput(req, appInstance, secretRef, secretName, convert.ToString(secretRef.Params["format"]))
+	if apierrors.IsNotFound(err) && existing != nil && len(existing.Data) > 0 {
+		// The job, or its pods, were deleted after the job finished, keep the values it generated
+		return existing, nil
+	} else if err != nil {
 		return nil, err
 	}
 
 	for k, v := range newSecret.Data {
 		secret.Data[k] = []byte(v)
 	}
-	if newSecret.Type != "" {
+	if declaredType != "" {
+		if newSecret.Type != "" && corev1.SecretType(v1.SecretTypePrefix+newSecret.Type) != declaredType {
+			return nil, invalidJobOutput(fmt.Errorf("output type [%s] does not match the declared type [%s]",
+				newSecret.Type, strings.TrimPrefix(string(declaredType), v1.SecretTypePrefix)))
+		}
+		if err := checkTypeKeys(declaredType, secret.Data); err != nil {
+			return nil, invalidJobOutput(err)
+		}
+		secret.Type = declaredType
+	} else if newSecret.Type != "" {
 		inType := corev1.SecretType(v1.SecretTypePrefix + newSecret.Type)
 		if v1.SecretTypes[inType] {
 			secret.Type = inType
 		}
 	}
 
+	// Don't trust a certificate that consumers can't parse. The existing secret is left as is, so an invalid output is
+	// reported on the secrets condition instead of being written and regenerated on every reconcile.
+	if secret.Type == v1.SecretTypeTLS {
+		if err := checkTLSCert(secret.Data); err != nil {
+			return nil, invalidJobOutput(err)
+		}
+	}
+
 	return updateOrCreate(req, existing, secret)
 }
 
-func generateTemplate(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
+// TemplateDependencies returns the names of the secrets that a template secret references
+func TemplateDependencies(secretRef v1.Secret) []string {
+	names := map[string]struct{}{}
+	for _, value := range secretRef.Data {
+		for _, groups := range templateSecretRegexp.FindAllStringSubmatch(value, -1) {
+			names[groups[1]] = struct{}{}
+		}
+	}
+	return typed.SortedKeys(names)
+}
+
+func generateTemplate(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, namespace, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: secretName + "-",
-			Namespace:    appInstance.Namespace,
-			Labels:       labelsForSecret(secretName, appInstance, secretRef),
+			Namespace:    namespace,
+			Labels:       labelsForSecret(secretName, namespace, appInstance, secretRef),
 			Annotations:  annotationsForSecret(secretName, appInstance, secretRef),
 		},
 		Data: seedData(existing, secretRef.Data, typed.SortedKeys(secretRef.Data)...),
@@ -188,12 +320,12 @@ func generateTemplate(secrets map[string]*corev1.Secret, req router.Request, app
 	return updateOrCreate(req, existing, secret)
 }
 
-func generateToken(req router.Request, appInstance *v1.AppInstance, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
+func generateToken(req router.Request, appInstance *v1.AppInstance, namespace, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: secretName + "-",
-			Namespace:    appInstance.Namespace,
-			Labels:       labelsForSecret(secretName, appInstance, secretRef),
+			Namespace:    namespace,
+			Labels:       labelsForSecret(secretName, namespace, appInstance, secretRef),
 			Annotations:  annotationsForSecret(secretName, appInstance, secretRef),
 		},
 		Data: seedData(existing, secretRef.Data, "token"),
@@ -203,25 +335,34 @@ func generateToken(req router.Request, appInstance *v1.AppInstance, secretName s
 	if len(secret.Data["token"]) == 0 {
 		length, err := convert.ToNumber(secretRef.Params["length"])
 		if err != nil {
-			return nil, err
+			return nil, invalidParams(err)
 		}
-		characters := convert.ToString(secretRef.Params["characters"])
-		v, err := generate(characters, int(length))
+		constraints, err := constraintsFromParams(secretRef.Params)
 		if err != nil {
 			return nil, err
 		}
+		characters, err := secretCharacters("token", secretRef.Params, constraints)
+		if err != nil {
+			return nil, fmt.Errorf("generating token for secret [%s]: %w", secretName, err)
+		}
+		v, err := constraints.generate(func() (string, error) {
+			return generate(characters, int(length))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("generating token for secret [%s]: %w", secretName, err)
+		}
 		secret.Data["token"] = []byte(v)
 	}
 
 	return updateOrCreate(req, existing, secret)
 }
 
-func generateOpaque(req router.Request, appInstance *v1.AppInstance, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
+func generateOpaque(req router.Request, appInstance *v1.AppInstance, namespace, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: secretName + "-",
-			Namespace:    appInstance.Namespace,
-			Labels:       labelsForSecret(secretName, appInstance, secretRef),
+			Namespace:    namespace,
+			Labels:       labelsForSecret(secretName, namespace, appInstance, secretRef),
 			Annotations:  annotationsForSecret(secretName, appInstance, secretRef),
 		},
 		Data: seedData(existing, secretRef.Data, maps.Keys(secretRef.Data)...),
@@ -231,23 +372,70 @@ func generateOpaque(req router.Request, appInstance *v1.AppInstance, secretName
 	return updateOrCreate(req, existing, secret)
 }
 
-func generateBasic(req router.Request, appInstance *v1.AppInstance, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
+// basicAuthKeys returns the keys that the username and password of a basic secret are stored under, which can be
+// changed with the usernameKey and passwordKey params
+func basicAuthKeys(params v1.GenericMap) (string, string, error) {
+	usernameKey, passwordKey := corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey
+	if key := convert.ToString(params["usernameKey"]); key != "" {
+		usernameKey = key
+	}
+	if key := convert.ToString(params["passwordKey"]); key != "" {
+		passwordKey = key
+	}
+	for _, key := range []string{usernameKey, passwordKey} {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return "", "", invalidParams(fmt.Errorf("invalid key name [%s]: %s", key, strings.Join(errs, ", ")))
+		}
+	}
+	if usernameKey == passwordKey {
+		return "", "", invalidParams(fmt.Errorf("usernameKey and passwordKey must be different, both are [%s]", usernameKey))
+	}
+	return usernameKey, passwordKey, nil
+}
+
+func generateBasic(req router.Request, appInstance *v1.AppInstance, namespace, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
+	usernameKey, passwordKey, err := basicAuthKeys(secretRef.Params)
+	if err != nil {
+		return nil, err
+	}
+
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: secretName + "-",
-			Namespace:    appInstance.Namespace,
-			Labels:       labelsForSecret(secretName, appInstance, secretRef),
+			Namespace:    namespace,
+			Labels:       labelsForSecret(secretName, namespace, appInstance, secretRef),
 			Annotations:  annotationsForSecret(secretName, appInstance, secretRef),
 		},
-		Data: seedData(existing, secretRef.Data, corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey),
+		Data: seedData(existing, secretRef.Data, usernameKey, passwordKey),
 		Type: v1.SecretTypeBasic,
 	}
+	if usernameKey != corev1.BasicAuthUsernameKey || passwordKey != corev1.BasicAuthPasswordKey {
+		// A basic secret is expected to have the username and password keys, so with other key names it is opaque
+		secret.Type = v1.SecretTypeOpaque
+	}
+
+	if len(secret.Data[passwordKey]) == 0 && len(secretRef.Params) > 0 {
+		constraints, err := constraintsFromParams(secretRef.Params)
+		if err != nil {
+			return nil, err
+		}
+		characters, err := secretCharacters("basic", secretRef.Params, constraints)
+		if err != nil {
+			return nil, fmt.Errorf("generating password for secret [%s]: %w", secretName, err)
+		}
+		v, err := constraints.generate(func() (string, error) {
+			return generate(characters, constraints.length(16))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("generating password for secret [%s]: %w", secretName, err)
+		}
+		secret.Data[passwordKey] = []byte(v)
+	}
 
-	for i, key := range []string{corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey} {
+	for i, key := range []string{usernameKey, passwordKey} {
 		if len(secret.Data[key]) == 0 {
 			// TODO: Improve with more characters (special, upper/lowercase, etc)
-			v, err := randomtoken.Generate()
-			v = v[:(i+1)*8]
+			v, err := generate(defaultCharacters, (i+1)*8)
 			if err != nil {
 				return nil, err
 			}
@@ -265,14 +453,28 @@ func updateOrCreate(req router.Request, existing, secret *corev1.Secret) (result
 		// The result secret should be decrypted, but the written secret in the app namespace should be encrypted
 		// if the source data was encrypted
 		result = result.DeepCopy()
-		result.Data, err = nacl.DecryptNamespacedDataMap(req.Ctx, req.Client, result.Data, result.Namespace)
+		namespace := result.Namespace
+		if appNamespace := result.Labels[labels.AcornAppNamespace]; appNamespace != "" {
+			// The data is encrypted for the namespace of the app, not the namespace for backing secrets
+			namespace = appNamespace
+		}
+		result.Data, err = nacl.DecryptNamespacedDataMap(req.Ctx, req.Client, result.Data, namespace)
 		if err != nil {
 			err = fmt.Errorf("decrypting %s/%s: %w", secret.Namespace, secret.Name, err)
 		}
 	}()
 
 	if existing == nil {
-		return secret, req.Client.Create(req.Ctx, secret)
+		if err := req.Client.Create(req.Ctx, secret); err != nil {
+			return nil, err
+		}
+		return dedupeCreated(req, secret)
+	}
+	if lastUsed, ok := existing.Annotations[labels.AcornSecretLastUsed]; ok {
+		// The last use is recorded by the controller that expires unused secrets, keep it
+		secret.Annotations = labels.Merge(secret.Annotations, map[string]string{
+			labels.AcornSecretLastUsed: lastUsed,
+		})
 	}
 	if equality.Semantic.DeepEqual(existing.Data, secret.Data) && maps.Equal(existing.Labels, secret.Labels) &&
 		maps.Equal(existing.Annotations, secret.Annotations) {
@@ -287,60 +489,186 @@ func updateOrCreate(req router.Request, existing, secret *corev1.Secret) (result
 	return newSecret, req.Client.Update(req.Ctx, newSecret)
 }
 
-func acornLabelsForSecret(secretName string, appInstance *v1.AppInstance) map[string]string {
-	return map[string]string{
+// dedupeCreated ensures that only one secret exists after a secret was created. Concurrent reconciles of an app can
+// each create the same secret because the secret is created with a generated name. All of them keep the secret that
+// getSecret picks and delete the others, so they agree on the secret regardless of the order they run in. The
+// secrets are listed uncached so that the secrets just created by the others are seen.
+func dedupeCreated(req router.Request, created *corev1.Secret) (*corev1.Secret, error) {
+	var secrets corev1.SecretList
+	err := req.List(uncached.List(&secrets), &kclient.ListOptions{
+		Namespace:     created.Namespace,
+		LabelSelector: klabels.SelectorFromSet(createdSelector(created)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, secret := range secrets.Items {
+		found = found || secret.UID == created.UID
+	}
+	if !found {
+		secrets.Items = append(secrets.Items, *created)
+	}
+	sortByUID(secrets.Items)
+
+	for i := range secrets.Items[1:] {
+		duplicate := &secrets.Items[i+1]
+		if err := req.Client.Delete(req.Ctx, duplicate); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("deleting duplicate secret %s/%s: %w", duplicate.Namespace, duplicate.Name, err)
+		}
+	}
+
+	if secrets.Items[0].UID == created.UID {
+		return created, nil
+	}
+	return &secrets.Items[0], nil
+}
+
+// createdSelector returns the labels that select the secrets created for the same secret of an app as the given secret
+func createdSelector(created *corev1.Secret) map[string]string {
+	result := map[string]string{
+		labels.AcornAppName:         created.Labels[labels.AcornAppName],
+		labels.AcornManaged:         "true",
+		labels.AcornSecretName:      created.Labels[labels.AcornSecretName],
+		labels.AcornSecretGenerated: "true",
+	}
+	if appNamespace, ok := created.Labels[labels.AcornAppNamespace]; ok {
+		result[labels.AcornAppNamespace] = appNamespace
+	}
+	return result
+}
+
+// sortByUID orders the secrets created for the same secret of an app so that the first one is used
+func sortByUID(secrets []corev1.Secret) {
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].UID < secrets[j].UID
+	})
+}
+
+func acornLabelsForSecret(secretName, namespace string, appInstance *v1.AppInstance) map[string]string {
+	result := map[string]string{
 		labels.AcornAppName:         appInstance.Name,
 		labels.AcornManaged:         "true",
 		labels.AcornSecretName:      secretName,
 		labels.AcornSecretGenerated: "true",
 	}
+	if namespace != appInstance.Namespace {
+		// The backing secrets of apps in every project share the configured namespace
+		result[labels.AcornAppNamespace] = appInstance.Namespace
+	}
+	return result
 }
 
-func labelsForSecret(secretName string, appInstance *v1.AppInstance, secretRef v1.Secret) map[string]string {
-	result := labels.Merge(acornLabelsForSecret(secretName, appInstance),
+func labelsForSecret(secretName, namespace string, appInstance *v1.AppInstance, secretRef v1.Secret) map[string]string {
+	result := labels.Merge(acornLabelsForSecret(secretName, namespace, appInstance),
 		labels.GatherScoped(secretName, v1.LabelTypeSecret,
 			appInstance.Status.AppSpec.Labels, secretRef.Labels, appInstance.Spec.Labels))
+	if namespace != appInstance.Namespace && appInstance.UID != "" {
+		// The namespace for backing secrets outlives the app, the UID tells a new app of the same name apart from it
+		result[labels.AcornAppUID] = string(appInstance.UID)
+	}
 	return labels.Merge(result, map[string]string{
 		labels.AcornPublicName: publicname.ForChild(appInstance, secretName),
 	})
 }
 
 func annotationsForSecret(secretName string, appInstance *v1.AppInstance, secretRef v1.Secret) map[string]string {
-	return labels.GatherScoped(secretName, v1.LabelTypeSecret, appInstance.Status.AppSpec.Annotations, secretRef.Annotations,
+	result := labels.GatherScoped(secretName, v1.LabelTypeSecret, appInstance.Status.AppSpec.Annotations, secretRef.Annotations,
 		appInstance.Spec.Annotations)
+	// GatherScoped drops acorn.io keys, but the regenerate value is recorded so that a later bump can be detected
+	if regenerate := secretRef.Annotations[labels.AcornSecretRegenerate]; regenerate != "" {
+		result[labels.AcornSecretRegenerate] = regenerate
+	}
+	// the TTL is recorded so that the controller can expire the secret when it isn't used
+	if ttl := secretRef.Annotations[labels.AcornSecretTTL]; ttl != "" {
+		result[labels.AcornSecretTTL] = ttl
+	}
+	return result
 }
 
-func getSecret(req router.Request, appInstance *v1.AppInstance, name string) (*corev1.Secret, error) {
-	l := acornLabelsForSecret(name, appInstance)
+// needsRegeneration returns true if the regenerate annotation on the secret definition has been set to a value
+// other than the one recorded on the existing secret.
+func needsRegeneration(existing *corev1.Secret, secretRef v1.Secret) bool {
+	regenerate := secretRef.Annotations[labels.AcornSecretRegenerate]
+	return existing != nil && regenerate != "" && existing.Annotations[labels.AcornSecretRegenerate] != regenerate
+}
 
-	var secrets corev1.SecretList
-	err := req.List(&secrets, &kclient.ListOptions{
-		Namespace:     appInstance.Namespace,
-		LabelSelector: klabels.SelectorFromSet(l),
-	})
+// backingSecretNamespace returns the namespace that the secrets generated for the app are stored in, which is the
+// namespace of the app unless a namespace for backing secrets is configured
+func backingSecretNamespace(cfg *apiv1.Config, appInstance *v1.AppInstance) string {
+	if *cfg.BackingSecretNamespace != "" {
+		return *cfg.BackingSecretNamespace
+	}
+	return appInstance.Namespace
+}
+
+func getSecret(req router.Request, appInstance *v1.AppInstance, namespace, name string) (*corev1.Secret, error) {
+	l := acornLabelsForSecret(name, namespace, appInstance)
+
+	secrets, err := listSecrets(req, namespace, l)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(secrets.Items) == 0 {
+	if len(secrets) == 0 {
+		// Backing secrets created by an earlier version may carry other labels. They are found with the configured
+		// compat labels and get the current labels when they are next updated.
+		cfg, err := config.Get(req.Ctx, req.Client)
+		if err != nil {
+			return nil, err
+		}
+		if compat := compatLabelsForSecret(cfg, l); compat != nil {
+			if secrets, err = listSecrets(req, namespace, compat); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(secrets) == 0 {
 		return nil, apierrors.NewNotFound(schema.GroupResource{
 			Group:    "v1",
 			Resource: "secrets",
 		}, name)
 	}
 
-	sort.Slice(secrets.Items, func(i, j int) bool {
-		return secrets.Items[i].UID < secrets.Items[j].UID
+	sortByUID(secrets)
+	return &secrets[0], nil
+}
+
+func listSecrets(req router.Request, namespace string, l map[string]string) ([]corev1.Secret, error) {
+	var secrets corev1.SecretList
+	err := req.List(&secrets, &kclient.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: klabels.SelectorFromSet(l),
 	})
+	return secrets.Items, err
+}
 
-	return &secrets.Items[0], nil
+// compatLabelsForSecret returns the labels of a backing secret with the keys replaced by the configured compat label
+// keys, or nil if no compat labels are configured for any of its keys
+func compatLabelsForSecret(cfg *apiv1.Config, l map[string]string) map[string]string {
+	result := maps.Clone(l)
+	changed := false
+	for _, entry := range cfg.SecretCompatLabels {
+		key, legacyKey, _ := strings.Cut(entry, "=")
+		value, ok := result[key]
+		if !ok || key == legacyKey {
+			continue
+		}
+		delete(result, key)
+		if legacyKey != "" {
+			result[legacyKey] = value
+		}
+		changed = true
+	}
+	if !changed || len(result) == 0 {
+		return nil
+	}
+	return result
 }
 
 func generateSecret(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, secretName string) (*corev1.Secret, error) {
-	existing, err := getSecret(req, appInstance, secretName)
-	if err != nil && !apierrors.IsNotFound(err) {
-		return nil, err
-	}
 	secretRef, ok := appInstance.Status.AppSpec.Secrets[secretName]
 	if !ok {
 		return nil, apierrors.NewNotFound(schema.GroupResource{
@@ -349,20 +677,67 @@ func generateSecret(secrets map[string]*corev1.Secret, req router.Request, appIn
 		}, secretName)
 	}
 
+	cfg, err := config.Get(req.Ctx, req.Client)
+	if err != nil {
+		return nil, err
+	}
+	if config.SecretTypeDisallowed(cfg, appInstance.Namespace, secretRef.Type) {
+		return nil, newGenerationError(secretName, secretRef.Type,
+			disallowed(fmt.Errorf("secret type [%s] is not allowed in project [%s]", secretRef.Type, appInstance.Namespace)))
+	}
+
+	// External secrets stay in the namespace of the app, because the secret store they reference is resolved there
+	namespace := appInstance.Namespace
+	if secretRef.Type != "external" {
+		namespace = backingSecretNamespace(cfg, appInstance)
+	}
+
+	existing, err := getSecret(req, appInstance, namespace, secretName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	event := ""
+	if existing == nil {
+		event = WebhookEventCreated
+	}
+
+	var previous map[string][]byte
+	if secretRef.Type != "external" && needsRegeneration(existing, secretRef) {
+		event = WebhookEventRegenerated
+		// Drop the existing data so new values are generated, but keep the object so that it is updated in place
+		previous = existing.Data
+		existing = existing.DeepCopy()
+		existing.Data = nil
+	}
+
+	var secret *corev1.Secret
 	switch secretRef.Type {
 	case "opaque":
-		return generateOpaque(req, appInstance, secretName, secretRef, existing)
+		secret, err = generateOpaque(req, appInstance, namespace, secretName, secretRef, existing)
 	case "basic":
-		return generateBasic(req, appInstance, secretName, secretRef, existing)
+		secret, err = generateBasic(req, appInstance, namespace, secretName, secretRef, existing)
 	case "generated":
-		return generatedSecret(req, appInstance, secretName, secretRef, existing)
+		secret, err = generatedSecret(req, appInstance, namespace, secretName, secretRef, existing)
 	case "token":
-		return generateToken(req, appInstance, secretName, secretRef, existing)
+		secret, err = generateToken(req, appInstance, namespace, secretName, secretRef, existing)
 	case "template":
-		return generateTemplate(secrets, req, appInstance, secretName, secretRef, existing)
+		secret, err = generateTemplate(secrets, req, appInstance, namespace, secretName, secretRef, existing)
+	case "external":
+		secret, err = generateExternal(req, appInstance, secretName, secretRef, existing)
+	case "jwt":
+		secret, err = generateJWT(req, appInstance, namespace, secretName, secretRef, existing, previous)
+	case "docker":
+		secret, err = generateDocker(secrets, req, appInstance, namespace, secretName, secretRef, existing)
+	case "tls":
+		secret, err = generateTLS(secrets, req, appInstance, namespace, secretName, secretRef, existing)
 	default:
-		return nil, err
+		err = invalidParams(fmt.Errorf("unknown secret type [%s]", secretRef.Type))
+	}
+	if err == nil && event != "" {
+		notifyWebhook(req, appInstance, event, secretName, secretRef)
 	}
+	return secret, newGenerationError(secretName, secretRef.Type, err)
 }
 
 func GetOrCreateSecret(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, secretName string) (*corev1.Secret, error) {
@@ -372,9 +747,11 @@ func GetOrCreateSecret(secrets map[string]*corev1.Secret, req router.Request, ap
 
 	secretRef := ""
 	refNamespace := appInstance.Namespace
+	bound := false
 	for _, binding := range appInstance.Spec.Secrets {
 		if binding.Target == secretName {
 			secretRef = binding.Secret
+			bound = true
 		}
 	}
 
@@ -413,6 +790,11 @@ func GetOrCreateSecret(secrets map[string]*corev1.Secret, req router.Request, ap
 		if err != nil {
 			return nil, err
 		}
+		if bound {
+			if err := checkBoundKeys(appInstance, secretName, secretRef, existingSecret); err != nil {
+				return nil, err
+			}
+		}
 		secrets[secretName] = existingSecret
 		return existingSecret, nil
 	}
@@ -425,14 +807,36 @@ func GetOrCreateSecret(secrets map[string]*corev1.Secret, req router.Request, ap
 	return secret, nil
 }
 
+// checkBoundKeys returns an error if a secret bound to a secret of the app lacks a key that the declared type of the
+// secret requires, so that the app fails to deploy instead of breaking when the key is used
+func checkBoundKeys(appInstance *v1.AppInstance, secretName, boundName string, bound *corev1.Secret) error {
+	declared, ok := appInstance.Status.AppSpec.Secrets[secretName]
+	if !ok || declared.Type == "" {
+		return nil
+	}
+	secretType := corev1.SecretType(v1.SecretTypePrefix + declared.Type)
+	if missing := missingTypeKeys(secretType, bound.Data); len(missing) > 0 {
+		return fmt.Errorf("secret [%s] bound to secret [%s] of type [%s] is missing keys [%s]",
+			boundName, secretName, declared.Type, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// generate returns a random string of the given length from the given characters, read from the Source. Each character
+// is picked with crypto/rand.Int, which rejects out of range samples rather than reducing them modulo the number of
+// characters, so every character is equally likely. The characters are runes, so multibyte characters are kept whole.
 func generate(characters string, tokenLength int) (string, error) {
-	token := make([]byte, tokenLength)
+	runes := []rune(characters)
+	if len(runes) == 0 {
+		return "", fmt.Errorf("no characters to generate a value from")
+	}
+	token := make([]rune, tokenLength)
 	for i := range token {
-		r, err := rand.Int(rand.Reader, big.NewInt(int64(len(characters))))
+		r, err := rand.Int(source, big.NewInt(int64(len(runes))))
 		if err != nil {
 			return "", err
 		}
-		token[i] = characters[r.Int64()]
+		token[i] = runes[r.Int64()]
 	}
 	return string(token), nil
 }
@@ -0,0 +1,66 @@
+package secrets
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+)
+
+// Source is where generated secret values and keys get their randomness from. Random values, like the username and
+// password of basic secrets and tokens, are read from the Source. Keys, like the signing keys of JWT secrets, are
+// generated by it, so that an HSM or a validated key generator can create them.
+type Source interface {
+	io.Reader
+	// GenerateKey returns a new key pair for the JWT algorithm, one of RS256, ES256 or EdDSA
+	GenerateKey(algorithm string) (crypto.PrivateKey, crypto.PublicKey, error)
+}
+
+// source is the Source used by the secret generators, crypto/rand unless replaced with SetSource
+var source Source = cryptoSource{}
+
+// SetSource replaces the Source that secrets are generated from and returns the previous one. It is not safe to call
+// while secrets are being generated, so it should be called before the controller starts.
+func SetSource(s Source) Source {
+	previous := source
+	source = s
+	return previous
+}
+
+// GenerateKeyFrom generates a new key pair for the JWT algorithm with the randomness read from r. It is how the
+// default Source generates keys, and can be used by a Source that only replaces the reader.
+func GenerateKeyFrom(r io.Reader, algorithm string) (crypto.PrivateKey, crypto.PublicKey, error) {
+	switch algorithm {
+	case "RS256":
+		key, err := rsa.GenerateKey(r, jwtRSAKeySize)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, key.Public(), nil
+	case "ES256":
+		key, err := ecdsa.GenerateKey(elliptic.P256(), r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, key.Public(), nil
+	case "EdDSA":
+		publicKey, privateKey, err := ed25519.GenerateKey(r)
+		return privateKey, publicKey, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported algorithm [%s]", algorithm)
+	}
+}
+
+type cryptoSource struct{}
+
+func (cryptoSource) Read(p []byte) (int, error) {
+	return rand.Read(p)
+}
+
+func (cryptoSource) GenerateKey(algorithm string) (crypto.PrivateKey, crypto.PublicKey, error) {
+	return GenerateKeyFrom(rand.Reader, algorithm)
+}
@@ -0,0 +1,364 @@
+package secrets
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/encryption/nacl"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TLSCACertKey is the key of the certificate of the CA in a tls secret
+	TLSCACertKey = "ca.crt"
+	// TLSCAKeyKey is the key of the private key of the CA in a tls secret that can sign other certificates
+	TLSCAKeyKey = "ca.key"
+
+	tlsValidity = 365 * 24 * time.Hour
+)
+
+// TLSParams are the params of a tls secret
+type TLSParams struct {
+	// CommonName is the common name of the certificate, the name of the secret by default
+	CommonName string
+	// SANs are the DNS names and IP addresses the certificate is valid for, the common name by default
+	SANs []string
+	// CASecrets are the names of the secrets of the app holding a chain of CAs, starting with the root. Each CA signs
+	// the next one, and the last one signs the certificate. The certificate is self-signed if there are none.
+	CASecrets []string
+	// NotBeforeSkew is how far the start of the validity of the certificate is backdated, so that clients with clocks
+	// that are behind don't reject it as not yet valid
+	NotBeforeSkew time.Duration
+}
+
+// tlsCA is a CA in the chain of a tls secret. Only the CA that signs the certificate of the secret has a key.
+type tlsCA struct {
+	name    string
+	cert    *x509.Certificate
+	certPEM []byte
+	key     crypto.Signer
+}
+
+// tlsParams reads the params of a tls secret
+func tlsParams(params v1.GenericMap, secretName string) (result TLSParams, err error) {
+	result.CommonName = convert.ToString(params["commonName"])
+	if result.CommonName == "" {
+		result.CommonName = secretName
+	}
+	result.CASecrets, err = stringListParam(params, "caSecrets")
+	if err != nil {
+		return result, err
+	}
+	if caSecret := convert.ToString(params["caSecret"]); caSecret != "" {
+		if len(result.CASecrets) > 0 {
+			return result, fmt.Errorf("caSecret and caSecrets can't both be set")
+		}
+		result.CASecrets = []string{caSecret}
+	}
+
+	if v := convert.ToString(params["notBeforeSkew"]); v != "" {
+		result.NotBeforeSkew, err = time.ParseDuration(v)
+		if err != nil || result.NotBeforeSkew < 0 {
+			return result, fmt.Errorf("invalid notBeforeSkew param [%s], must be a duration such as 5m", v)
+		}
+	}
+
+	result.SANs, err = stringListParam(params, "sans")
+	if err != nil {
+		return result, err
+	}
+	for _, san := range result.SANs {
+		if _, _, err := net.ParseCIDR(san); err == nil {
+			return result, fmt.Errorf("invalid sans param entry [%s], a certificate can't be valid for a CIDR, list each IP address as its own SAN instead", san)
+		}
+	}
+	if len(result.SANs) == 0 && result.CommonName != "" {
+		result.SANs = []string{result.CommonName}
+	}
+	return result, nil
+}
+
+// stringListParam returns the param with the given name, which must be a list of non-empty strings if it is set
+func stringListParam(params v1.GenericMap, name string) ([]string, error) {
+	v, ok := params[name]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s param [%v], must be a list", name, v)
+	}
+	result := make([]string, 0, len(list))
+	for _, entry := range list {
+		s, ok := entry.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("invalid %s param entry [%v], must be a non-empty string", name, entry)
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// TLSDependencies returns the names of the secrets holding the CAs of a tls secret
+func TLSDependencies(secretRef v1.Secret) []string {
+	params, _ := tlsParams(secretRef.Params, "")
+	return params.CASecrets
+}
+
+// generateTLS generates a certificate and its ECDSA P-256 key. The params are:
+//
+//	commonName: the common name of the certificate (default the name of the secret)
+//	sans: the DNS names and IP addresses the certificate is valid for, not CIDRs (default the common name)
+//	caSecret: the name of a tls secret in the app holding the CA that signs the certificate, in ca.crt and ca.key
+//	caSecrets: the names of tls secrets in the app holding a chain of CAs instead, starting with the root CA. Each CA
+//	must sign the next, and only the last one, which signs the certificate, needs its ca.key.
+//	notBeforeSkew: how far to backdate the start of the validity of the certificate, such as 5m (default 0)
+//
+// An existing certificate is kept unless it can't be parsed or doesn't match its key. Then it is replaced once, and the
+// reason is recorded on the secret. If it is invalid again after that, an error is returned instead of replacing it
+// again. Without a CA the certificate is self-signed. The secret holds the certificate in tls.crt, followed by the
+// intermediate CAs of a chain, its key in tls.key and the certificate of the root CA, or the certificate itself if it
+// is self-signed, in ca.crt. The certificate is valid for a year.
+func generateTLS(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, namespace, secretName string, secretRef v1.Secret, existing *corev1.Secret) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: secretName + "-",
+			Namespace:    namespace,
+			Labels:       labelsForSecret(secretName, namespace, appInstance, secretRef),
+			Annotations:  annotationsForSecret(secretName, appInstance, secretRef),
+		},
+		Data: seedData(existing, secretRef.Data, corev1.TLSCertKey, corev1.TLSPrivateKeyKey, TLSCACertKey),
+		Type: v1.SecretTypeTLS,
+	}
+
+	var replacedReason string
+	if existing != nil {
+		replacedReason = existing.Annotations[labels.AcornSecretReplacedInvalid]
+	}
+
+	if len(secret.Data[corev1.TLSCertKey]) > 0 && len(secret.Data[corev1.TLSPrivateKeyKey]) > 0 {
+		// the data declared in the Acornfile may be encrypted, it is checked decrypted
+		data, err := nacl.DecryptNamespacedDataMap(req.Ctx, req.Client, secret.Data, appInstance.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		invalid := checkTLSKeyPair(data)
+		if invalid == nil {
+			if replacedReason != "" {
+				secret.Annotations = labels.Merge(secret.Annotations, map[string]string{
+					labels.AcornSecretReplacedInvalid: replacedReason,
+				})
+			}
+			return updateOrCreate(req, existing, secret)
+		}
+		if replacedReason != "" {
+			// the certificate was already replaced once, replacing it again could loop forever
+			return nil, fmt.Errorf("certificate of secret [%s] is invalid after it was replaced: %w", secretName, invalid)
+		}
+		// the certificate is replaced once, and the reason is recorded so that it is reported on the app
+		secret.Annotations = labels.Merge(secret.Annotations, map[string]string{
+			labels.AcornSecretReplacedInvalid: invalid.Error(),
+		})
+	}
+
+	params, err := tlsParams(secretRef.Params, secretName)
+	if err != nil {
+		return nil, invalidParams(fmt.Errorf("%w for secret [%s]", err, secretName))
+	}
+
+	chain, err := loadCAChain(secrets, req, appInstance, secretName, params.CASecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	var signer *tlsCA
+	if len(chain) > 0 {
+		signer = chain[len(chain)-1]
+	}
+	certPEM, keyPEM, err := issueCertificate(params, signer)
+	if err != nil {
+		return nil, fmt.Errorf("issuing certificate for secret [%s]: %w", secretName, err)
+	}
+
+	secret.Data[corev1.TLSPrivateKeyKey] = keyPEM
+	secret.Data[TLSCACertKey] = certPEM
+	if len(chain) > 0 {
+		secret.Data[TLSCACertKey] = chain[0].certPEM
+		// clients are only expected to trust the root, so the intermediates are sent with the certificate
+		for i := len(chain) - 1; i > 0; i-- {
+			certPEM = append(certPEM, chain[i].certPEM...)
+		}
+	}
+	secret.Data[corev1.TLSCertKey] = certPEM
+	return updateOrCreate(req, existing, secret)
+}
+
+// checkTLSKeyPair returns an error if tls.crt and tls.key are not a certificate and its matching key
+func checkTLSKeyPair(data map[string][]byte) error {
+	if err := checkTLSCert(data); err != nil {
+		return err
+	}
+	if _, err := tls.X509KeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey]); err != nil {
+		return fmt.Errorf("invalid key pair in [%s] and [%s]: %w", corev1.TLSCertKey, corev1.TLSPrivateKeyKey, err)
+	}
+	return nil
+}
+
+// loadCAChain reads the CAs of a tls secret, starting with the root, and checks that each CA is signed by the one
+// before it
+func loadCAChain(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, secretName string, names []string) ([]*tlsCA, error) {
+	var chain []*tlsCA
+	for i, name := range names {
+		ca, err := loadCA(secrets, req, appInstance, secretName, name, i == len(names)-1)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			parent := chain[i-1]
+			if err := ca.cert.CheckSignatureFrom(parent.cert); err != nil {
+				return nil, invalidParams(fmt.Errorf("CA in caSecret [%s] of secret [%s] is not signed by the CA in caSecret [%s]: %w",
+					name, secretName, parent.name, err))
+			}
+		}
+		chain = append(chain, ca)
+	}
+	return chain, nil
+}
+
+// loadCA reads a CA of a tls secret from the secret named by its caSecret or caSecrets param. That secret must be a tls
+// secret holding a CA certificate in ca.crt, as created by acorn secret create --ca-cert and --ca-key, so that a wrong
+// secret is reported clearly instead of failing to sign. The key of the CA in ca.key is only read if the CA signs the
+// certificate of the secret.
+func loadCA(secrets map[string]*corev1.Secret, req router.Request, appInstance *v1.AppInstance, secretName, caSecretName string, signs bool) (*tlsCA, error) {
+	caSecret, err := GetOrCreateSecret(secrets, req, appInstance, caSecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	if caSecret.Type != v1.SecretTypeTLS && caSecret.Type != corev1.SecretTypeTLS {
+		return nil, invalidParams(fmt.Errorf("caSecret [%s] of secret [%s] is of type [%s], it must be a tls secret holding a CA",
+			caSecretName, secretName, strings.TrimPrefix(string(caSecret.Type), v1.SecretTypePrefix)))
+	}
+
+	keys := []string{TLSCACertKey}
+	if signs {
+		keys = append(keys, TLSCAKeyKey)
+	}
+	var missing []string
+	for _, key := range keys {
+		if len(caSecret.Data[key]) == 0 {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, invalidParams(fmt.Errorf("caSecret [%s] of secret [%s] is missing keys [%s], it must hold a CA that can sign certificates",
+			caSecretName, secretName, strings.Join(missing, ", ")))
+	}
+
+	block, _ := pem.Decode(caSecret.Data[TLSCACertKey])
+	if block == nil {
+		return nil, invalidParams(fmt.Errorf("invalid CA in caSecret [%s] of secret [%s]: no PEM data found", caSecretName, secretName))
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, invalidParams(fmt.Errorf("invalid CA in caSecret [%s] of secret [%s]: %w", caSecretName, secretName, err))
+	}
+	if !cert.IsCA {
+		return nil, invalidParams(fmt.Errorf("certificate in caSecret [%s] of secret [%s] is not a CA, it can't sign other certificates",
+			caSecretName, secretName))
+	}
+
+	ca := &tlsCA{
+		name:    caSecretName,
+		cert:    cert,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+	}
+	if !signs {
+		return ca, nil
+	}
+
+	pair, err := tls.X509KeyPair(caSecret.Data[TLSCACertKey], caSecret.Data[TLSCAKeyKey])
+	if err != nil {
+		return nil, invalidParams(fmt.Errorf("invalid CA in caSecret [%s] of secret [%s]: %w", caSecretName, secretName, err))
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, invalidParams(fmt.Errorf("unsupported key in caSecret [%s] of secret [%s]", caSecretName, secretName))
+	}
+	ca.key = key
+	return ca, nil
+}
+
+// issueCertificate returns a new certificate and its key, both PEM encoded. The certificate is signed by the CA, or is
+// self-signed if the CA is nil.
+func issueCertificate(params TLSParams, ca *tlsCA) (certPEM, keyPEM []byte, _ error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), source)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: params.CommonName},
+		NotBefore:             now.Add(-params.NotBeforeSkew),
+		NotAfter:              now.Add(tlsValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, san := range params.SANs {
+		if ip := net.ParseIP(san); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, san)
+		}
+	}
+
+	parent, signer := template, crypto.Signer(key)
+	if ca != nil {
+		parent, signer = ca.cert, ca.key
+	}
+	certDER, err := x509.CreateCertificate(source, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// randomSerial returns a random 128 bit serial number for a certificate, read from the Source
+func randomSerial() (*big.Int, error) {
+	serial := make([]byte, 16)
+	if _, err := io.ReadFull(source, serial); err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(serial), nil
+}
@@ -0,0 +1,16 @@
+package secrets
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseTTL parses the value of the acorn.io/secret-ttl annotation of a secret, which is how long the secret is kept
+// while it isn't used by a running app
+func ParseTTL(ttl string) (time.Duration, error) {
+	d, err := time.ParseDuration(ttl)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid secret TTL [%s], must be a positive duration such as 720h", ttl)
+	}
+	return d, nil
+}
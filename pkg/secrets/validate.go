@@ -0,0 +1,97 @@
+package secrets
+
+import (
+	"fmt"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/rancher/wrangler/pkg/data/convert"
+)
+
+// ValidateParams returns an error if the type of the secret is unknown or its params are invalid for the type. Only
+// the params that can be checked without generating the secret are validated.
+func ValidateParams(secretRef v1.Secret) error {
+	if ttl, ok := secretRef.Annotations[labels.AcornSecretTTL]; ok {
+		if _, err := ParseTTL(ttl); err != nil {
+			return err
+		}
+	}
+
+	switch secretRef.Type {
+	case "opaque", "template", "external":
+		return nil
+	case "tls":
+		_, err := tlsParams(secretRef.Params, "")
+		return err
+	case "basic":
+		if _, _, err := basicAuthKeys(secretRef.Params); err != nil {
+			return err
+		}
+		return validateConstraints(secretRef)
+	case "token":
+		return validateConstraints(secretRef)
+	case "generated":
+		if _, err := declaredSecretType(secretRef.Params); err != nil {
+			return err
+		}
+		for _, param := range []string{"backoffLimit", "ttlSecondsAfterFinished"} {
+			if v, ok := secretRef.Params[param]; ok {
+				if _, err := generationJobParam(v); err != nil {
+					return fmt.Errorf("invalid %s: %w", param, err)
+				}
+			}
+		}
+		switch format := convert.ToString(secretRef.Params["format"]); format {
+		case "", "text", "dotenv", "aml", "json":
+			return nil
+		default:
+			return fmt.Errorf("invalid generated secret format [%s]", format)
+		}
+	case "jwt":
+		algorithm := convert.ToString(secretRef.Params["algorithm"])
+		switch algorithm {
+		case "":
+			algorithm = jwtDefaultAlgorithm
+		case "RS256", "ES256", "EdDSA":
+		default:
+			return fmt.Errorf("invalid algorithm [%s], must be RS256, ES256 or EdDSA", algorithm)
+		}
+		if _, err := jwtKeyFormat(secretRef.Params, algorithm); err != nil {
+			return err
+		}
+		if v, ok := secretRef.Params["retainKeys"]; ok {
+			if n, err := convert.ToNumber(v); err != nil || n < 0 {
+				return fmt.Errorf("invalid retainKeys [%v], must be a non-negative number", v)
+			}
+		}
+		return nil
+	case "docker":
+		entries, err := dockerRegistryParams(secretRef.Params)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if convert.ToString(entry["registry"]) == "" {
+				return fmt.Errorf("registry is required for each entry of registries")
+			}
+			if convert.ToString(entry["job"]) != "" {
+				if _, err := dockerTokenTTL(entry); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid secret type [%s]", secretRef.Type)
+}
+
+// validateConstraints returns an error if the constraints of a basic or token secret are invalid, or leave no characters
+// to generate the value from
+func validateConstraints(secretRef v1.Secret) error {
+	constraints, err := constraintsFromParams(secretRef.Params)
+	if err != nil {
+		return err
+	}
+	_, err = secretCharacters(secretRef.Type, secretRef.Params, constraints)
+	return err
+}
@@ -0,0 +1,74 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/config"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	WebhookEventCreated     = "created"
+	WebhookEventRegenerated = "regenerated"
+)
+
+// webhookClient is used to post notifications to the secret webhook. The timeout bounds how long a notification can
+// run in the background, it never delays the reconcile that generated the secret.
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// WebhookNotification is posted to the secret webhook when a secret of an app is generated or regenerated. It must
+// never hold the values of the secret.
+type WebhookNotification struct {
+	Event        string      `json:"event"`
+	App          string      `json:"app"`
+	AppNamespace string      `json:"appNamespace"`
+	Secret       string      `json:"secret"`
+	Type         string      `json:"type"`
+	Timestamp    metav1.Time `json:"timestamp"`
+}
+
+// notifyWebhook posts a notification for the secret to the secret webhook, if one is configured. Notifications are
+// best-effort, failures are logged and don't fail the reconcile.
+func notifyWebhook(req router.Request, appInstance *v1.AppInstance, event, secretName string, secretRef v1.Secret) {
+	cfg, err := config.Get(req.Ctx, req.Client)
+	if err != nil {
+		logrus.Errorf("failed to get config to notify the secret webhook of secret [%s] of app %s/%s: %v", secretName, appInstance.Namespace, appInstance.Name, err)
+		return
+	}
+	if *cfg.SecretWebhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(WebhookNotification{
+		Event:        event,
+		App:          appInstance.Name,
+		AppNamespace: appInstance.Namespace,
+		Secret:       secretName,
+		Type:         secretRef.Type,
+		Timestamp:    metav1.Now(),
+	})
+	if err != nil {
+		logrus.Errorf("failed to marshal secret webhook notification: %v", err)
+		return
+	}
+
+	go postWebhook(*cfg.SecretWebhookURL, body)
+}
+
+func postWebhook(url string, body []byte) {
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		logrus.Errorf("failed to notify the secret webhook: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		logrus.Errorf("failed to notify the secret webhook: unexpected status %s", resp.Status)
+	}
+}
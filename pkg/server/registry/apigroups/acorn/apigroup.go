@@ -1,6 +1,8 @@
 package acorn
 
 import (
+	"context"
+
 	api "github.com/acorn-io/acorn/pkg/apis/api.acorn.io"
 	v1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/client"
@@ -29,7 +31,7 @@ import (
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func Stores(c kclient.WithWatch, cfg, localCfg *clientgo.Config) (map[string]rest.Storage, error) {
+func Stores(ctx context.Context, c kclient.WithWatch, cfg, localCfg *clientgo.Config) (map[string]rest.Storage, error) {
 	clientFactory, err := client.NewClientFactory(localCfg)
 	if err != nil {
 		return nil, err
@@ -88,15 +90,15 @@ func Stores(c kclient.WithWatch, cfg, localCfg *clientgo.Config) (map[string]res
 		"secrets/reveal":         secrets.NewReveal(c),
 		"infos":                  info.NewStorage(c),
 		"computeclasses":         computeclass.NewAggregateStorage(c),
-		"regions":                regions.NewStorage(c),
+		"regions":                regions.NewStorage(ctx, c),
 		"imageallowrules":        imageallowrules.NewStorage(c),
 	}
 
 	return stores, nil
 }
 
-func APIGroup(c kclient.WithWatch, cfg, localCfg *clientgo.Config) (*genericapiserver.APIGroupInfo, error) {
-	stores, err := Stores(c, cfg, localCfg)
+func APIGroup(ctx context.Context, c kclient.WithWatch, cfg, localCfg *clientgo.Config) (*genericapiserver.APIGroupInfo, error) {
+	stores, err := Stores(ctx, c, cfg, localCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -13,14 +13,14 @@ import (
 	"github.com/acorn-io/acorn/pkg/computeclasses"
 	apiv1config "github.com/acorn-io/acorn/pkg/config"
 	"github.com/acorn-io/acorn/pkg/imageallowrules"
+	"github.com/acorn-io/acorn/pkg/images"
 	"github.com/acorn-io/acorn/pkg/imagesystem"
-	"github.com/acorn-io/acorn/pkg/pullsecret"
+	"github.com/acorn-io/acorn/pkg/lint"
+	"github.com/acorn-io/acorn/pkg/secrets"
 	"github.com/acorn-io/acorn/pkg/tags"
 	"github.com/acorn-io/acorn/pkg/volume"
 	"github.com/acorn-io/baaah/pkg/merr"
 	"github.com/acorn-io/baaah/pkg/typed"
-	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"golang.org/x/exp/slices"
 	authv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -62,6 +62,7 @@ func (s *Validator) Validate(ctx context.Context, obj runtime.Object) (result fi
 		return
 	}
 
+	var appSpec *v1.AppSpec
 	if _, isPattern := autoupgrade.AutoUpgradePattern(params.Spec.Image); !isPattern {
 		image, local, err := s.resolveLocalImage(ctx, params.Namespace, params.Spec.Image)
 		if err != nil {
@@ -83,7 +84,7 @@ func (s *Validator) Validate(ctx context.Context, obj runtime.Object) (result fi
 		}
 
 		disableCheckImageAllowRules := false
-		if params.Spec.Stop != nil && *params.Spec.Stop {
+		if params.Spec.GetStopped() {
 			// app was stopped, so we don't need to check image allow rules (this could prevent stopping an app if the image allow rules changed)
 			disableCheckImageAllowRules = true
 		}
@@ -95,6 +96,8 @@ func (s *Validator) Validate(ctx context.Context, obj runtime.Object) (result fi
 			}
 		}
 
+		appSpec = imageDetails.AppSpec
+
 		workloadsFromImage, err := s.getWorkloads(imageDetails)
 		if err != nil {
 			result = append(result, field.Invalid(field.NewPath("spec", "image"), params.Spec.Image, err.Error()))
@@ -113,6 +116,21 @@ func (s *Validator) Validate(ctx context.Context, obj runtime.Object) (result fi
 			return
 		}
 
+		if errs := lint.Ports(workloadsFromImage); len(errs) != 0 {
+			result = append(result, errs...)
+			return
+		}
+
+		if errs := lint.VolumeNames(imageDetails.AppSpec, workloadsFromImage); len(errs) != 0 {
+			result = append(result, errs...)
+			return
+		}
+
+		if errs := lint.Secrets(imageDetails.AppSpec); len(errs) != 0 {
+			result = append(result, errs...)
+			return
+		}
+
 		if err := validateVolumeClasses(ctx, s.client, params.Namespace, params.Spec, imageDetails.AppSpec, project); err != nil {
 			result = append(result, err)
 			return
@@ -130,6 +148,11 @@ func (s *Validator) Validate(ctx context.Context, obj runtime.Object) (result fi
 		}
 	}
 
+	if errs := validateLabelsAndAnnotations(params.Spec, appSpec); len(errs) != 0 {
+		result = append(result, errs...)
+		return
+	}
+
 	if err := s.checkPermissionsForPrivilegeEscalation(ctx, params.Spec.Permissions); err != nil {
 		result = append(result, field.Invalid(field.NewPath("spec", "permissions"), params.Spec.Permissions, err.Error()))
 	}
@@ -165,17 +188,7 @@ func (s *Validator) validateRegion(app *apiv1.App, project *apiv1.Project) error
 }
 
 func (s *Validator) checkRemoteAccess(ctx context.Context, namespace, image string) error {
-	keyChain, err := pullsecret.Keychain(ctx, s.client, namespace)
-	if err != nil {
-		return err
-	}
-
-	ref, err := name.ParseReference(image)
-	if err != nil {
-		return err
-	}
-
-	_, err = remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keyChain))
+	_, err := images.ImageDigest(ctx, s.client, namespace, image)
 	if err != nil {
 		return fmt.Errorf("failed to pull %s: %v", image, err)
 	}
@@ -453,6 +466,70 @@ func validateMemoryRunFlags(memory v1.MemoryMap, workloads map[string]v1.Contain
 	return validationErrors
 }
 
+// validateLabelsAndAnnotations checks that no label or annotation of the app, or of the resources in its Acornfile,
+// references a secret value. Labels and annotations are copied to many objects that are not secrets, so such a value
+// would leak. appSpec can be nil if the image of the app is not resolved yet.
+func validateLabelsAndAnnotations(appInstanceSpec v1.AppInstanceSpec, appSpec *v1.AppSpec) (result field.ErrorList) {
+	for i, label := range appInstanceSpec.Labels {
+		result = append(result, checkSecretReference(field.NewPath("spec", "labels").Index(i), label.Value)...)
+	}
+	for i, annotation := range appInstanceSpec.Annotations {
+		result = append(result, checkSecretReference(field.NewPath("spec", "annotations").Index(i), annotation.Value)...)
+	}
+	if appSpec == nil {
+		return result
+	}
+
+	result = append(result, checkSecretReferences(field.NewPath("labels"), appSpec.Labels)...)
+	result = append(result, checkSecretReferences(field.NewPath("annotations"), appSpec.Annotations)...)
+	for _, entry := range typed.Sorted(appSpec.Containers) {
+		result = append(result, checkContainerSecretReferences(field.NewPath("containers").Key(entry.Key), entry.Value)...)
+	}
+	for _, entry := range typed.Sorted(appSpec.Jobs) {
+		result = append(result, checkContainerSecretReferences(field.NewPath("jobs").Key(entry.Key), entry.Value)...)
+	}
+	for _, entry := range typed.Sorted(appSpec.Routers) {
+		path := field.NewPath("routers").Key(entry.Key)
+		result = append(result, checkSecretReferences(path.Child("labels"), entry.Value.Labels)...)
+		result = append(result, checkSecretReferences(path.Child("annotations"), entry.Value.Annotations)...)
+	}
+	for _, entry := range typed.Sorted(appSpec.Volumes) {
+		path := field.NewPath("volumes").Key(entry.Key)
+		result = append(result, checkSecretReferences(path.Child("labels"), entry.Value.Labels)...)
+		result = append(result, checkSecretReferences(path.Child("annotations"), entry.Value.Annotations)...)
+	}
+	for _, entry := range typed.Sorted(appSpec.Secrets) {
+		path := field.NewPath("secrets").Key(entry.Key)
+		result = append(result, checkSecretReferences(path.Child("labels"), entry.Value.Labels)...)
+		result = append(result, checkSecretReferences(path.Child("annotations"), entry.Value.Annotations)...)
+	}
+	return result
+}
+
+func checkContainerSecretReferences(path *field.Path, container v1.Container) (result field.ErrorList) {
+	result = append(result, checkSecretReferences(path.Child("labels"), container.Labels)...)
+	result = append(result, checkSecretReferences(path.Child("annotations"), container.Annotations)...)
+	for _, entry := range typed.Sorted(container.Sidecars) {
+		result = append(result, checkContainerSecretReferences(path.Child("sidecars").Key(entry.Key), entry.Value)...)
+	}
+	return result
+}
+
+func checkSecretReferences(path *field.Path, values map[string]string) (result field.ErrorList) {
+	for _, entry := range typed.Sorted(values) {
+		result = append(result, checkSecretReference(path.Key(entry.Key), entry.Value)...)
+	}
+	return result
+}
+
+func checkSecretReference(path *field.Path, value string) field.ErrorList {
+	if secrets.ContainsSecretReference(value) {
+		// the value is not echoed back as it may hold an encrypted secret
+		return field.ErrorList{field.Forbidden(path, "labels and annotations cannot reference secret values")}
+	}
+	return nil
+}
+
 func validateVolumeClasses(ctx context.Context, c kclient.Client, namespace string, appInstanceSpec v1.AppInstanceSpec, appSpec *v1.AppSpec, project *apiv1.Project) *field.Error {
 	if len(appInstanceSpec.Volumes) == 0 && len(appSpec.Volumes) == 0 {
 		return nil
@@ -497,7 +574,12 @@ func validateVolumeClasses(ctx context.Context, c kclient.Client, namespace stri
 			return field.Invalid(field.NewPath("spec", "image"), appInstanceSpec.Image, fmt.Sprintf("%s is not a valid volume class", volClass.Name))
 		}
 
-		if calculatedVolumeRequest.Size != "" {
+		if _, ok := calculatedVolumeRequest.Size.Percentage(); ok {
+			// a percentage is resolved against the ephemeral storage of the nodes, so there is no size to check yet
+			if !strings.EqualFold(calculatedVolumeRequest.Class, v1.VolumeRequestTypeEphemeral) {
+				return field.Invalid(field.NewPath("spec", "volumes", volName, "size"), calculatedVolumeRequest.Size, "a percentage size is only supported for ephemeral volumes")
+			}
+		} else if calculatedVolumeRequest.Size != "" {
 			q := v1.MustParseResourceQuantity(calculatedVolumeRequest.Size)
 			if volClass.Size.Min != "" && q.Cmp(*v1.MustParseResourceQuantity(volClass.Size.Min)) < 0 {
 				return field.Invalid(field.NewPath("spec", "volumes", volName, "size"), q.String(), fmt.Sprintf("less than volume class %s minimum of %v", calculatedVolumeRequest.Class, volClass.Size.Min))
@@ -563,18 +645,7 @@ func (s *Validator) buildNestedPermissions(ctx context.Context, servicePrefix st
 }
 
 func (s *Validator) getWorkloads(details *client.ImageDetails) (map[string]v1.Container, error) {
-	result := make(map[string]v1.Container, len(details.AppSpec.Containers)+len(details.AppSpec.Jobs))
-	for workload, container := range details.AppSpec.Containers {
-		result[workload] = container
-		for sidecarWorkload, sidecarContainer := range container.Sidecars {
-			result[sidecarWorkload] = sidecarContainer
-		}
-	}
-	for workload, container := range details.AppSpec.Jobs {
-		result[workload] = container
-	}
-
-	return result, nil
+	return lint.Workloads(details.AppSpec), nil
 }
 
 func buildPermissionsFrom(servicePrefix string, containers map[string]v1.Container) []v1.Permissions {
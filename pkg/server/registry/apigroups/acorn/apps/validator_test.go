@@ -54,3 +54,85 @@ func TestCannotChangeAppRegion(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateLabelsAndAnnotations(t *testing.T) {
+	tests := []struct {
+		name            string
+		appInstanceSpec internalv1.AppInstanceSpec
+		appSpec         *internalv1.AppSpec
+		wantErr         string
+	}{
+		{
+			name: "No secret references",
+			appInstanceSpec: internalv1.AppInstanceSpec{
+				Labels:      []internalv1.ScopedLabel{{Key: "team", Value: "web"}},
+				Annotations: []internalv1.ScopedLabel{{Key: "name", Value: "@{app.name}"}},
+			},
+			appSpec: &internalv1.AppSpec{
+				Containers: map[string]internalv1.Container{
+					"web": {
+						Annotations: map[string]string{"db": "@{services.db.address}"},
+					},
+				},
+			},
+		},
+		{
+			name: "Annotation referencing a secret",
+			appInstanceSpec: internalv1.AppInstanceSpec{
+				Annotations: []internalv1.ScopedLabel{{Key: "password", Value: "@{secrets.db.password}"}},
+			},
+			wantErr: `spec.annotations[0]: Forbidden: labels and annotations cannot reference secret values`,
+		},
+		{
+			name: "Label referencing a secret by URI",
+			appInstanceSpec: internalv1.AppInstanceSpec{
+				Labels: []internalv1.ScopedLabel{{Key: "password", Value: "${secret://db/password}"}},
+			},
+			wantErr: `spec.labels[0]: Forbidden: labels and annotations cannot reference secret values`,
+		},
+		{
+			name: "Sidecar annotation referencing a secret of a service",
+			appSpec: &internalv1.AppSpec{
+				Containers: map[string]internalv1.Container{
+					"web": {
+						Sidecars: map[string]internalv1.Container{
+							"proxy": {
+								Annotations: map[string]string{"token": "token-@{services.db.secrets.admin.token}"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: `containers[web].sidecars[proxy].annotations[token]: Forbidden: labels and annotations cannot reference secret values`,
+		},
+		{
+			name: "Volume label with an encrypted value",
+			appSpec: &internalv1.AppSpec{
+				Volumes: map[string]internalv1.VolumeRequest{
+					"data": {
+						Labels: map[string]string{"key": "ACORNENC:abc::"},
+					},
+				},
+			},
+			wantErr: `volumes[data].labels[key]: Forbidden: labels and annotations cannot reference secret values`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateLabelsAndAnnotations(tt.appInstanceSpec, tt.appSpec)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("Expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("Expected 1 error, got %v", errs)
+			}
+			if errs[0].Error() != tt.wantErr {
+				t.Fatalf("Expected error %q, got %q", tt.wantErr, errs[0].Error())
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package projects
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/secrets"
 	"github.com/acorn-io/mink/pkg/types"
 	corev1 "k8s.io/api/core/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
@@ -54,15 +56,22 @@ func (t *Translator) ToPublic(ctx context.Context, obj ...runtime.Object) (resul
 			calculatedDefaultRegion = t.defaultRegion
 		}
 
+		defaultSecrets, err := secrets.ProjectDefaults(ns)
+		if err != nil {
+			return nil, err
+		}
+
 		delete(ns.Labels, labels.AcornProject)
 		delete(ns.Annotations, labels.AcornProjectDefaultRegion)
 		delete(ns.Annotations, labels.AcornProjectSupportedRegions)
+		delete(ns.Annotations, labels.AcornProjectDefaultSecrets)
 
 		result = append(result, &apiv1.Project{
 			ObjectMeta: ns.ObjectMeta,
 			Spec: apiv1.ProjectSpec{
 				DefaultRegion:    defaultRegion,
 				SupportedRegions: supportedRegions,
+				DefaultSecrets:   defaultSecrets,
 			},
 			Status: apiv1.ProjectStatus{
 				Namespace:     ns.Name,
@@ -91,6 +100,13 @@ func (t *Translator) FromPublic(ctx context.Context, obj runtime.Object) (types.
 	ns.Annotations[labels.AcornProjectDefaultRegion] = prj.Spec.DefaultRegion
 	ns.Annotations[labels.AcornProjectSupportedRegions] = strings.Join(prj.Spec.SupportedRegions, ",")
 	ns.Annotations[labels.AcornCalculatedProjectDefaultRegion] = prj.Status.DefaultRegion
+	if len(prj.Spec.DefaultSecrets) > 0 {
+		data, err := json.Marshal(prj.Spec.DefaultSecrets)
+		if err != nil {
+			return nil, err
+		}
+		ns.Annotations[labels.AcornProjectDefaultSecrets] = string(data)
+	}
 
 	return ns, nil
 }
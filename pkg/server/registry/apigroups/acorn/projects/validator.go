@@ -4,6 +4,8 @@ import (
 	"context"
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/secrets"
+	"github.com/acorn-io/baaah/pkg/typed"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -15,6 +17,15 @@ type Validator struct {
 func (v *Validator) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
 	var result field.ErrorList
 	project := obj.(*apiv1.Project)
+	for _, entry := range typed.Sorted(project.Spec.DefaultSecrets) {
+		if err := secrets.ValidateParams(entry.Value); err != nil {
+			result = append(result, field.Invalid(field.NewPath("spec", "defaultSecrets").Key(entry.Key), entry.Value.Type, err.Error()))
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+
 	if project.Spec.DefaultRegion == "" && len(project.Spec.SupportedRegions) == 0 {
 		// If no regions are specified, use the default region.
 		project.Status.DefaultRegion = v.DefaultRegion
@@ -0,0 +1,110 @@
+package regions
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// watchRetryInterval is how long to wait before restarting a failed or closed watch of the acorn config
+var watchRetryInterval = 5 * time.Second
+
+// regionCache keeps the local region between requests. The region is built from the acorn config, so the cache is
+// invalidated on every event of a watch on the config ConfigMap, and is only used while that watch is running.
+type regionCache struct {
+	watchConfig func(ctx context.Context) (watch.Interface, error)
+
+	lock     sync.Mutex
+	watching bool
+	// generation is incremented on every invalidation so that a region computed from an older config is not stored
+	generation int
+	region     *apiv1.Region
+}
+
+// newRegionCache returns a cache whose watch on the acorn config is started right away and stops when ctx is done
+func newRegionCache(ctx context.Context, c kclient.WithWatch) *regionCache {
+	cache := &regionCache{
+		watchConfig: func(ctx context.Context) (watch.Interface, error) {
+			return c.Watch(ctx, &corev1.ConfigMapList{}, kclient.InNamespace(system.Namespace),
+				kclient.MatchingFields{"metadata.name": system.ConfigName})
+		},
+	}
+	go cache.watch(ctx)
+	return cache
+}
+
+// get returns the cached region, or nil on a miss. The generation must be passed to set when storing the region
+// computed after the miss.
+func (c *regionCache) get() (*apiv1.Region, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.watching || c.region == nil {
+		return nil, c.generation
+	}
+	return c.region.DeepCopy(), c.generation
+}
+
+func (c *regionCache) set(region *apiv1.Region, generation int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.watching && c.generation == generation {
+		c.region = region.DeepCopy()
+	}
+}
+
+func (c *regionCache) invalidate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.generation++
+	c.region = nil
+}
+
+func (c *regionCache) setWatching(watching bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.watching = watching
+	c.generation++
+	c.region = nil
+}
+
+func (c *regionCache) watch(ctx context.Context) {
+	for {
+		w, err := c.watchConfig(ctx)
+		if err != nil {
+			logrus.Errorf("failed to watch acorn config for the region cache: %v", err)
+		} else {
+			c.setWatching(true)
+			c.consume(ctx, w)
+			w.Stop()
+		}
+		c.setWatching(false)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+// consume invalidates the cache on every event of the watch until the watch is closed or ctx is done
+func (c *regionCache) consume(ctx context.Context, w watch.Interface) {
+	for {
+		select {
+		case _, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			c.invalidate()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
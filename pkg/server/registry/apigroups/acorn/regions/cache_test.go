@@ -0,0 +1,139 @@
+package regions
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/acorn-io/baaah/pkg/router/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// countingClient counts the reads of the acorn config
+type countingClient struct {
+	*tester.Client
+	configReads atomic.Int32
+}
+
+func (c *countingClient) Get(ctx context.Context, key kclient.ObjectKey, obj kclient.Object) error {
+	if key.Name == system.ConfigName {
+		c.configReads.Add(1)
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func configMap(config string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      system.ConfigName,
+			Namespace: system.Namespace,
+		},
+		Data: map[string]string{
+			"config": config,
+		},
+	}
+}
+
+func getMirror(t *testing.T, s *strategy) string {
+	t.Helper()
+	obj, err := s.Get(context.Background(), "", "local")
+	require.NoError(t, err)
+	return obj.(*apiv1.Region).Spec.RegistryMirror
+}
+
+func TestRegionCache(t *testing.T) {
+	c := &countingClient{
+		Client: &tester.Client{
+			SchemeObj: scheme.Scheme,
+			Objects:   []kclient.Object{configMap(`{"registryMirrors": ["local=mirror.one"]}`)},
+		},
+	}
+	watcher := watch.NewFake()
+	s := &strategy{
+		client: c,
+		cache: &regionCache{
+			watchConfig: func(context.Context) (watch.Interface, error) {
+				return watcher, nil
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.cache.watch(ctx)
+
+	// Nothing is cached until the watch is running
+	assert.Equal(t, "mirror.one", getMirror(t, s))
+	assert.Eventually(t, func() bool {
+		s.cache.lock.Lock()
+		defer s.cache.lock.Unlock()
+		return s.cache.watching
+	}, time.Second, 10*time.Millisecond)
+
+	// The first read while watching fills the cache and the following ones are hits
+	reads := c.configReads.Load()
+	assert.Equal(t, "mirror.one", getMirror(t, s))
+	assert.Equal(t, "mirror.one", getMirror(t, s))
+	assert.Equal(t, "mirror.one", getMirror(t, s))
+	assert.Equal(t, reads+1, c.configReads.Load())
+
+	// A change to the config invalidates the cache, so the next read sees the new mirror
+	c.Objects = []kclient.Object{configMap(`{"registryMirrors": ["local=mirror.two"]}`)}
+	watcher.Modify(c.Objects[0])
+	assert.Eventually(t, func() bool {
+		return getMirror(t, s) == "mirror.two"
+	}, time.Second, 10*time.Millisecond)
+
+	// When the watch stops the cache is no longer used
+	watchRetryInterval = time.Hour
+	watcher.Stop()
+	assert.Eventually(t, func() bool {
+		s.cache.lock.Lock()
+		defer s.cache.lock.Unlock()
+		return !s.cache.watching
+	}, time.Second, 10*time.Millisecond)
+	reads = c.configReads.Load()
+	getMirror(t, s)
+	getMirror(t, s)
+	assert.Equal(t, reads+2, c.configReads.Load())
+}
+
+func TestRegionCacheStopsWithContext(t *testing.T) {
+	var watches atomic.Int32
+	cache := &regionCache{
+		watchConfig: func(context.Context) (watch.Interface, error) {
+			watches.Add(1)
+			return watch.NewFake(), nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		cache.watch(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool {
+		cache.lock.Lock()
+		defer cache.lock.Unlock()
+		return cache.watching
+	}, time.Second, 10*time.Millisecond)
+
+	// Cancelling the context stops the watch for good, instead of leaking it for the life of the process
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watch did not stop after its context was cancelled")
+	}
+	assert.Equal(t, int32(1), watches.Load())
+	region, _ := cache.get()
+	assert.Nil(t, region)
+}
@@ -1,6 +1,7 @@
 package regions
 
 import (
+	"context"
 	"time"
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
@@ -11,8 +12,10 @@ import (
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func NewStorage(c kclient.WithWatch) rest.Storage {
-	s := &strategy{metav1.NewTime(time.Now())}
+// NewStorage returns the storage of regions. The local region is cached while a watch on the acorn config runs, which
+// lasts until ctx is done.
+func NewStorage(ctx context.Context, c kclient.WithWatch) rest.Storage {
+	s := &strategy{client: c, cache: newRegionCache(ctx, c), startTime: metav1.NewTime(time.Now())}
 	return stores.NewBuilder(c.Scheme(), &apiv1.Region{}).
 		WithGet(s).
 		WithList(s).
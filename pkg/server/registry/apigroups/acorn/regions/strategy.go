@@ -5,18 +5,22 @@ import (
 
 	apiv1 "github.com/acorn-io/acorn/pkg/apis/api.acorn.io/v1"
 	v1 "github.com/acorn-io/acorn/pkg/apis/internal.acorn.io/v1"
+	"github.com/acorn-io/acorn/pkg/config"
 	"github.com/acorn-io/mink/pkg/types"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/storage"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type strategy struct {
+	client    kclient.Client
+	cache     *regionCache
 	startTime metav1.Time
 }
 
-func (s *strategy) Get(_ context.Context, _, name string) (types.Object, error) {
+func (s *strategy) Get(ctx context.Context, _, name string) (types.Object, error) {
 	if name != "local" {
 		return nil, apierrors.NewNotFound(schema.GroupResource{
 			Group:    apiv1.SchemeGroupVersion.Group,
@@ -24,7 +28,17 @@ func (s *strategy) Get(_ context.Context, _, name string) (types.Object, error)
 		}, name)
 	}
 
-	return &apiv1.Region{
+	region, generation := s.cache.get()
+	if region != nil {
+		return region, nil
+	}
+
+	cfg, err := config.Get(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	region = &apiv1.Region{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:              "local",
 			CreationTimestamp: s.startTime,
@@ -35,8 +49,9 @@ func (s *strategy) Get(_ context.Context, _, name string) (types.Object, error)
 			},
 		},
 		Spec: apiv1.RegionSpec{
-			Description: "Local Region",
-			RegionName:  "local",
+			Description:    "Local Region",
+			RegionName:     "local",
+			RegistryMirror: config.RegistryMirror(cfg, "local"),
 		},
 		Status: apiv1.RegionStatus{
 			Conditions: []v1.Condition{
@@ -48,11 +63,16 @@ func (s *strategy) Get(_ context.Context, _, name string) (types.Object, error)
 				},
 			},
 		},
-	}, nil
+	}
+	s.cache.set(region, generation)
+	return region, nil
 }
 
-func (s *strategy) List(_ context.Context, _ string, _ storage.ListOptions) (types.ObjectList, error) {
-	region, _ := s.Get(context.Background(), "", "local")
+func (s *strategy) List(ctx context.Context, _ string, _ storage.ListOptions) (types.ObjectList, error) {
+	region, err := s.Get(ctx, "", "local")
+	if err != nil {
+		return nil, err
+	}
 	return &apiv1.RegionList{
 		Items: []apiv1.Region{*(region.(*apiv1.Region))},
 	}, nil
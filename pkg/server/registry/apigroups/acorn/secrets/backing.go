@@ -0,0 +1,134 @@
+package secrets
+
+import (
+	"context"
+	"strings"
+
+	"github.com/acorn-io/acorn/pkg/config"
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/mink/pkg/strategy/remote"
+	"github.com/acorn-io/mink/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/storage"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// backingContinuePrefix starts the continue token of a list of secrets that has returned every secret in the project
+// namespace and continues with the backing secrets of the project
+const backingContinuePrefix = "backing:"
+
+// backingStrategy reads secrets from the namespace of the project, and also from the namespace for backing secrets when
+// one is configured. The secrets generated for the apps of the project are stored there, labeled with the project, and
+// are returned as if they were in the project namespace so that they can be listed and revealed like before.
+type backingStrategy struct {
+	*remote.Remote
+	client kclient.Client
+}
+
+func newBackingStrategy(c kclient.WithWatch) *backingStrategy {
+	return &backingStrategy{
+		Remote: remote.NewRemote(&corev1.Secret{}, c),
+		client: c,
+	}
+}
+
+func (s *backingStrategy) Get(ctx context.Context, namespace, name string) (types.Object, error) {
+	obj, err := s.Remote.Get(ctx, namespace, name)
+	if !apierrors.IsNotFound(err) {
+		return obj, err
+	}
+
+	backingNamespace, backingErr := backingSecretNamespace(ctx, s.client, namespace)
+	if backingErr != nil || backingNamespace == "" {
+		return obj, err
+	}
+	backing, backingErr := s.Remote.Get(ctx, backingNamespace, name)
+	if apierrors.IsNotFound(backingErr) || (backingErr == nil && !inProject(backing.(*corev1.Secret), namespace)) {
+		// the secrets of other projects are never returned
+		return obj, err
+	} else if backingErr != nil {
+		return nil, backingErr
+	}
+	return toProject(backing.(*corev1.Secret), namespace), nil
+}
+
+// List returns the secrets in the namespace of the project, followed by the backing secrets of the project. With a
+// limit, the pages of the project namespace come first, then the pages of the backing secrets, which have a continue
+// token with backingContinuePrefix. Backing secrets of other projects are filtered out of a page, so a page of backing
+// secrets can be shorter than the limit even if more follow.
+func (s *backingStrategy) List(ctx context.Context, namespace string, opts storage.ListOptions) (types.ObjectList, error) {
+	if namespace == "" {
+		return s.Remote.List(ctx, namespace, opts)
+	}
+
+	secrets := &corev1.SecretList{}
+	backingContinue, listingBacking := strings.CutPrefix(opts.Predicate.Continue, backingContinuePrefix)
+	if !listingBacking {
+		list, err := s.Remote.List(ctx, namespace, opts)
+		if err != nil {
+			return nil, err
+		}
+		secrets = list.(*corev1.SecretList)
+		if secrets.Continue != "" {
+			// the backing secrets follow the last page of the project namespace
+			return secrets, nil
+		}
+	}
+
+	backingNamespace, err := backingSecretNamespace(ctx, s.client, namespace)
+	if err != nil || backingNamespace == "" {
+		return secrets, err
+	}
+	backingOpts := opts
+	backingOpts.Predicate.Continue = backingContinue
+	if limit := opts.Predicate.Limit; limit > 0 {
+		if int64(len(secrets.Items)) >= limit {
+			secrets.Continue = backingContinuePrefix
+			return secrets, nil
+		}
+		backingOpts.Predicate.Limit = limit - int64(len(secrets.Items))
+	}
+	list, err := s.Remote.List(ctx, backingNamespace, backingOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	backing := list.(*corev1.SecretList)
+	for i := range backing.Items {
+		if secret := &backing.Items[i]; inProject(secret, namespace) {
+			secrets.Items = append(secrets.Items, *toProject(secret, namespace))
+		}
+	}
+	if listingBacking {
+		secrets.ResourceVersion = backing.ResourceVersion
+	}
+	if backing.Continue != "" {
+		secrets.Continue = backingContinuePrefix + backing.Continue
+	}
+	return secrets, nil
+}
+
+// backingSecretNamespace returns the namespace for backing secrets, or an empty string if none is configured or it is
+// the given namespace
+func backingSecretNamespace(ctx context.Context, c kclient.Client, namespace string) (string, error) {
+	cfg, err := config.Get(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	if *cfg.BackingSecretNamespace == namespace {
+		return "", nil
+	}
+	return *cfg.BackingSecretNamespace, nil
+}
+
+// inProject returns true if the secret in the namespace for backing secrets was generated for an app in the project
+func inProject(secret *corev1.Secret, namespace string) bool {
+	return secret.Labels[labels.AcornAppNamespace] == namespace && secret.Labels[labels.AcornSecretGenerated] == "true"
+}
+
+func toProject(secret *corev1.Secret, namespace string) *corev1.Secret {
+	secret = secret.DeepCopy()
+	secret.Namespace = namespace
+	return secret
+}
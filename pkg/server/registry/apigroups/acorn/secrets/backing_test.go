@@ -0,0 +1,98 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/acorn-io/acorn/pkg/labels"
+	"github.com/acorn-io/acorn/pkg/scheme"
+	"github.com/acorn-io/acorn/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/storage"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func generatedSecret(name, namespace, appNamespace string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				labels.AcornAppName:         "app",
+				labels.AcornAppNamespace:    appNamespace,
+				labels.AcornManaged:         "true",
+				labels.AcornSecretName:      "db",
+				labels.AcornSecretGenerated: "true",
+			},
+		},
+		Type: "secrets.acorn.io/token",
+	}
+}
+
+func TestBackingSecretsReadFromBackingNamespace(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      system.ConfigName,
+				Namespace: system.Namespace,
+			},
+			Data: map[string]string{
+				"config": `{"backingSecretNamespace": "acorn-secrets"}`,
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "user-secret",
+				Namespace: "project-one",
+			},
+			Type: "secrets.acorn.io/opaque",
+		},
+		generatedSecret("db-one", "acorn-secrets", "project-one"),
+		generatedSecret("db-two", "acorn-secrets", "project-two"),
+	).Build()
+	s := newBackingStrategy(c)
+
+	list, err := s.List(ctx, "project-one", storage.ListOptions{})
+	require.NoError(t, err)
+	var names []string
+	for _, secret := range list.(*corev1.SecretList).Items {
+		// the backing secrets are returned in the project of their app
+		assert.Equal(t, "project-one", secret.Namespace)
+		names = append(names, secret.Name)
+	}
+	assert.ElementsMatch(t, []string{"user-secret", "db-one"}, names)
+
+	// with a limit, the backing secrets follow the last page of the project namespace
+	list, err = s.List(ctx, "project-one", storage.ListOptions{Predicate: storage.SelectionPredicate{Limit: 1}})
+	require.NoError(t, err)
+	page := list.(*corev1.SecretList)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "user-secret", page.Items[0].Name)
+	assert.Equal(t, "backing:", page.Continue)
+
+	list, err = s.List(ctx, "project-one", storage.ListOptions{Predicate: storage.SelectionPredicate{Limit: 1, Continue: page.Continue}})
+	require.NoError(t, err)
+	page = list.(*corev1.SecretList)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "db-one", page.Items[0].Name)
+	assert.Empty(t, page.Continue)
+
+	obj, err := s.Get(ctx, "project-one", "db-one")
+	require.NoError(t, err)
+	assert.Equal(t, "project-one", obj.GetNamespace())
+
+	// the backing secrets of other projects can't be read
+	_, err = s.Get(ctx, "project-one", "db-two")
+	assert.True(t, apierrors.IsNotFound(err), err)
+
+	// public names of generated secrets resolve to the backing secret
+	namespace, name, err := (&Translator{c: c}).FromPublicName(ctx, "project-one", "app.db")
+	require.NoError(t, err)
+	assert.Equal(t, "project-one", namespace)
+	assert.Equal(t, "db-one", name)
+}
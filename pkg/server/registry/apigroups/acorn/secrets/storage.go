@@ -5,9 +5,7 @@ import (
 	"github.com/acorn-io/acorn/pkg/publicname"
 	"github.com/acorn-io/acorn/pkg/tables"
 	"github.com/acorn-io/mink/pkg/stores"
-	"github.com/acorn-io/mink/pkg/strategy/remote"
 	"github.com/acorn-io/mink/pkg/strategy/translation"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apiserver/pkg/registry/rest"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -15,7 +13,7 @@ import (
 func NewStorage(c kclient.WithWatch) rest.Storage {
 	translated := translation.NewTranslationStrategy(&Translator{
 		c: c,
-	}, remote.NewRemote(&corev1.Secret{}, c))
+	}, newBackingStrategy(c))
 	remoteResource := publicname.NewStrategy(translated)
 	validator := &Validator{}
 
@@ -66,6 +66,27 @@ func (t *Translator) FromPublicName(ctx context.Context, namespace, name string)
 		return namespace, secrets.Items[0].Name, nil
 	}
 
+	// the secrets generated for the app may be stored in the namespace for backing secrets instead, the backing
+	// strategy reads them from there by name
+	backingNamespace, err := backingSecretNamespace(ctx, t.c, namespace)
+	if err != nil || backingNamespace == "" {
+		return namespace, name, err
+	}
+	err = t.c.List(ctx, secrets, &kclient.ListOptions{
+		Namespace: backingNamespace,
+		LabelSelector: klabels.SelectorFromSet(map[string]string{
+			labels.AcornAppName:      prefix,
+			labels.AcornAppNamespace: namespace,
+			labels.AcornSecretName:   secretName,
+		}),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if len(secrets.Items) == 1 {
+		return namespace, secrets.Items[0].Name, nil
+	}
+
 	return namespace, name, nil
 }
 
@@ -84,6 +84,9 @@ func (t *Translator) pvToVolume(ctx context.Context, pv corev1.PersistentVolume)
 		case corev1.ReadWriteMany:
 			accessModes = append(accessModes, v1.AccessModeReadWriteMany)
 			shortAccessModes = append(shortAccessModes, "RWX")
+		case corev1.ReadWriteOncePod:
+			accessModes = append(accessModes, v1.AccessModeReadWriteOncePod)
+			shortAccessModes = append(shortAccessModes, "RWOP")
 		}
 	}
 
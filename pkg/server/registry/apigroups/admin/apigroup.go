@@ -1,6 +1,8 @@
 package admin
 
 import (
+	"context"
+
 	adminapi "github.com/acorn-io/acorn/pkg/apis/admin.acorn.io"
 	v1 "github.com/acorn-io/acorn/pkg/apis/admin.acorn.io/v1"
 	"github.com/acorn-io/acorn/pkg/scheme"
@@ -24,7 +26,7 @@ func Stores(c kclient.WithWatch) (map[string]rest.Storage, error) {
 	}, nil
 }
 
-func APIGroup(c kclient.WithWatch, _, _ *clientgo.Config) (*genericapiserver.APIGroupInfo, error) {
+func APIGroup(_ context.Context, c kclient.WithWatch, _, _ *clientgo.Config) (*genericapiserver.APIGroupInfo, error) {
 	stores, err := Stores(c)
 	if err != nil {
 		return nil, err
@@ -13,9 +13,10 @@ import (
 )
 
 var validAccessModes = map[v1.AccessMode]struct{}{
-	v1.AccessModeReadWriteOnce: {},
-	v1.AccessModeReadWriteMany: {},
-	v1.AccessModeReadOnlyMany:  {},
+	v1.AccessModeReadWriteOnce:    {},
+	v1.AccessModeReadWriteMany:    {},
+	v1.AccessModeReadOnlyMany:     {},
+	v1.AccessModeReadWriteOncePod: {},
 }
 
 type ProjectValidator struct {
@@ -1,6 +1,8 @@
 package registry
 
 import (
+	"context"
+
 	"github.com/acorn-io/acorn/pkg/server/registry/apigroups/acorn"
 	"github.com/acorn-io/acorn/pkg/server/registry/apigroups/admin"
 	genericapiserver "k8s.io/apiserver/pkg/server"
@@ -13,11 +15,11 @@ var apiGroupFactories = []APIGroupFunc{
 	acorn.APIGroup,
 }
 
-type APIGroupFunc func(kclient.WithWatch, *clientgo.Config, *clientgo.Config) (*genericapiserver.APIGroupInfo, error)
+type APIGroupFunc func(context.Context, kclient.WithWatch, *clientgo.Config, *clientgo.Config) (*genericapiserver.APIGroupInfo, error)
 
-func APIGroups(c kclient.WithWatch, cfg, localCfg *clientgo.Config) (result []*genericapiserver.APIGroupInfo, err error) {
+func APIGroups(ctx context.Context, c kclient.WithWatch, cfg, localCfg *clientgo.Config) (result []*genericapiserver.APIGroupInfo, err error) {
 	for _, factory := range apiGroupFactories {
-		apiGroup, err := factory(c, cfg, localCfg)
+		apiGroup, err := factory(ctx, c, cfg, localCfg)
 		if err != nil {
 			return nil, err
 		}
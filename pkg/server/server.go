@@ -94,7 +94,7 @@ func (s *Server) Run(ctx context.Context, config *Config) error {
 		c = aggr
 	}
 
-	apiGroups, err := registry.APIGroups(c, cfg, localCfg)
+	apiGroups, err := registry.APIGroups(ctx, c, cfg, localCfg)
 	if err != nil {
 		return err
 	}
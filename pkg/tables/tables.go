@@ -4,6 +4,7 @@ var (
 	CheckResult = [][]string{
 		{"Name", "Name"},
 		{"Passed", "Passed"},
+		{"Warning", "{{ boolToStar .Warning }}"},
 		{"Message", "Message"},
 	}
 
@@ -159,6 +160,20 @@ var (
 	}
 	RegionConverter = MustConverter(Region)
 
+	NetworkPolicyDrift = [][]string{
+		{"Name", "Name"},
+		{"Namespace", "Namespace"},
+		{"Status", "Status"},
+	}
+
+	RegionDescribe = "Name:\t{{ .Name }}\n" +
+		"Account:\t{{ ownerName . }}\n" +
+		"Region Name:\t{{ .Spec.RegionName }}\n" +
+		"Default:\t{{ .Default }}\n" +
+		"Created:\t{{ ago .CreationTimestamp }}\n" +
+		"Description:\t{{ .Spec.Description }}\n" +
+		"Conditions:\t{{ range .Status.Conditions }}\n  {{ .Type }}:\t{{ .Status }}\t{{ .Message }}{{ end }}"
+
 	RuleRequests = [][]string{
 		{"Service", "Service"},
 		{"Verbs", "Verbs"},
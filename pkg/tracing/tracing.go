@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"github.com/acorn-io/baaah/pkg/router"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/acorn-io/acorn"
+
+// Start starts a span named name as a child of the span in the context of the request. The returned request carries
+// the context of the new span, so that the spans started with it are its children.
+func Start(req router.Request, name string, attrs ...attribute.KeyValue) (router.Request, trace.Span) {
+	ctx, span := otel.Tracer(instrumentationName).Start(req.Ctx, name, trace.WithAttributes(attrs...))
+	return req.WithContext(ctx), span
+}
+
+// End records err on the span, if there is one, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Handler traces each call of handler in a span named name, with the object being reconciled as attributes
+func Handler(name string, handler router.HandlerFunc) router.HandlerFunc {
+	return func(req router.Request, resp router.Response) (err error) {
+		req, span := Start(req, name,
+			attribute.String("acorn.kind", req.GVK.Kind),
+			attribute.String("acorn.namespace", req.Namespace),
+			attribute.String("acorn.name", req.Name))
+		defer func() {
+			End(span, err)
+		}()
+		return handler(req, resp)
+	}
+}